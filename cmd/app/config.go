@@ -3,6 +3,7 @@ package main
 import (
 	"time"
 
+	"github.com/shngxx/point/internal/usecase"
 	"github.com/shngxx/point/pkg/http"
 	applog "github.com/shngxx/point/pkg/log"
 )
@@ -20,6 +21,17 @@ type PointConfig struct {
 	MaxY          int `koanf:"maxY"`          // Maximum Y coordinate (default: 600)
 	BatchInterval int `koanf:"batchInterval"` // Batch processing interval in milliseconds (~60 FPS, default: 16ms)
 	SaveInterval  int `koanf:"saveInterval"`  // Save interval in seconds (default: 5s)
+
+	PositionFormat string `koanf:"positionFormat"` // Position serialization: object, array or delta (default: object)
+	PausePolicy    string `koanf:"pausePolicy"`    // Moves received while paused: queue or drop (default: queue)
+
+	PhysicsEnabled bool    `koanf:"physicsEnabled"` // Moves set a velocity instead of an instant offset (default: false)
+	Friction       float64 `koanf:"friction"`       // Fraction of velocity lost per batch tick in physics mode, 0..1 (default: 0)
+
+	HistorySize int `koanf:"historySize"` // Applied moves recorded per point for replay (default: 0, disabled)
+
+	MaxStep    int    `koanf:"maxStep"`    // Largest |dx| or |dy| of a client move command (default: 0, unlimited)
+	StepPolicy string `koanf:"stepPolicy"` // Move commands over maxStep: clamp or reject (default: clamp)
 }
 
 // BatchInterval returns batch interval as time.Duration
@@ -38,6 +50,14 @@ func (c *PointConfig) SaveIntervalDuration() time.Duration {
 	return 5 * time.Second // Default
 }
 
+// PausePolicyValue returns the pause policy with default fallback
+func (c *PointConfig) PausePolicyValue() usecase.PausePolicy {
+	if c.PausePolicy == "drop" {
+		return usecase.DropPaused
+	}
+	return usecase.QueuePaused // Default
+}
+
 // MaxXValue returns max X coordinate with default fallback
 func (c *PointConfig) MaxXValue() int {
 	if c.MaxX > 0 {
@@ -2,15 +2,17 @@ package main
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/websocket/v2"
+	"github.com/shngxx/point/internal/domain/point"
 	httphandler "github.com/shngxx/point/internal/http"
 	"github.com/shngxx/point/internal/infrastructure/db"
+	"github.com/shngxx/point/internal/infrastructure/events"
 	"github.com/shngxx/point/internal/usecase"
 	"github.com/shngxx/point/internal/ws"
 	"github.com/shngxx/point/pkg/config"
 	"github.com/shngxx/point/pkg/di"
 	"github.com/shngxx/point/pkg/http"
 	httphooks "github.com/shngxx/point/pkg/http/hooks"
+	"github.com/shngxx/point/pkg/http/middleware"
 	logging "github.com/shngxx/point/pkg/log"
 	wsmanager "github.com/shngxx/point/pkg/ws"
 )
@@ -25,52 +27,74 @@ func main() {
 		logging.New,
 		wsmanager.NewManagerWithDefaults,
 		http.NewWithDefaults,
-		db.NewPointRepository,
+		func() *db.PointRepository {
+			return db.NewPointRepositoryWithConfig(cfg.Point.MaxXValue(), cfg.Point.MaxYValue())
+		},
+		events.NewMemoryEventBus,
 		usecase.NewGetPointUC,
 		usecase.NewMovePointUC,
 		ws.NewHandler,
 		httphandler.NewGetPointHandler,
 	)
 
+	// Use cases depend on the repository interface
+	c.Bind((*point.PointRepository)(nil), (*db.PointRepository)(nil))
+
 	// Register dependencies for server
 	c.Supply(
 		cfg.Server,
 		cfg.Logger,
 		usecase.MovePointConfig{
-			BatchInterval: cfg.Point.BatchIntervalDuration(),
-			SaveInterval:  cfg.Point.SaveIntervalDuration(),
+			BatchInterval:  cfg.Point.BatchIntervalDuration(),
+			SaveInterval:   cfg.Point.SaveIntervalDuration(),
+			PausePolicy:    cfg.Point.PausePolicyValue(),
+			PhysicsEnabled: cfg.Point.PhysicsEnabled,
+			Friction:       cfg.Point.Friction,
+			HistorySize:    cfg.Point.HistorySize,
+		},
+		ws.HandlerConfig{
+			PositionFormat: ws.PositionFormat(cfg.Point.PositionFormat),
+			MaxStep:        cfg.Point.MaxStep,
+			StepPolicy:     ws.StepPolicy(cfg.Point.StepPolicy),
 		},
 	)
 
+	// Fail fast if any dependency is missing
+	if err := c.Validate(); err != nil {
+		panic(err)
+	}
+
 	// Get dependencies from DI
 	server := di.MustResolve[*http.Server](c)
-	wsManager := di.MustResolve[*wsmanager.Manager](c)
 
-	// Register all routes in a centralized location (routes.go)
-	// Routes resolve their handlers from DI container automatically
-	registerRoutes(server, c)
+	// Register all routes in a centralized location
+	// Route handlers are injected from the DI container
+	if err := c.Invoke(registerRoutes); err != nil {
+		panic(err)
+	}
 
-	// Register shutdown hook for WebSocket manager
-	server.AddHook(httphooks.BeforeShutdown, func() error {
-		return wsManager.Shutdown()
-	})
+	// Release container services (e.g. the WebSocket manager) before the server shuts down
+	server.AddHook(httphooks.BeforeShutdown, c.Shutdown)
 
 	// Start server
 	server.Start()
 }
 
-func registerRoutes(server *http.Server, c *di.Container) {
+func registerRoutes(server *http.Server, wsHandler *ws.Handler, getPointHandler fiber.Handler) {
 	// ============================================================================
 	// WebSocket Routes
 	// ============================================================================
-	wsHandler := di.MustResolve[*ws.Handler](c)
-	server.App().Get("/ws", websocket.New(wsHandler.Manager().HandleConnection))
+	wsManager := wsHandler.Manager()
+	server.WS("/ws", wsManager.HandleConnection, wsManager.UpgradeConfig())
 
 	// ============================================================================
 	// Point API Routes
 	// ============================================================================
-	getPointHandler := di.MustResolve[fiber.Handler](c)
-	server.GET("/api/point/:id", getPointHandler)
-	server.GET("/api/point", getPointHandler) // For case when id is not specified
+	// Invalid IDs and missing points are client errors (400/404)
+	httphandler.RegisterErrors(server)
 
+	// Both routes share the per-IP limit (60 requests per minute by default)
+	rateLimit := middleware.RateLimit(middleware.RateLimitConfig{})
+	server.GET("/api/point/:id", getPointHandler, rateLimit)
+	server.GET("/api/point", getPointHandler, rateLimit) // For case when id is not specified
 }
@@ -1,5 +1,7 @@
 package point
 
+import "fmt"
+
 // Point represents a point on a plane with boundaries
 type Point struct {
 	X    int `json:"x"`
@@ -51,6 +53,13 @@ func (p *Point) Move(dx, dy int) {
 	p.Clamp()
 }
 
+// Teleport moves the point to absolute coordinates with boundary clamping
+func (p *Point) Teleport(x, y int) {
+	p.X = x
+	p.Y = y
+	p.Clamp()
+}
+
 // Clamp limits coordinates to the boundaries defined in the point
 func (p *Point) Clamp() {
 	if p.X < 0 {
@@ -66,3 +75,59 @@ func (p *Point) Clamp() {
 		p.Y = p.MaxY - 1
 	}
 }
+
+// Validate checks that the point has positive boundaries and lies within them
+func (p *Point) Validate() error {
+	if p.MaxX <= 0 || p.MaxY <= 0 {
+		return fmt.Errorf("invalid point boundaries %dx%d", p.MaxX, p.MaxY)
+	}
+	if p.X < 0 || p.X >= p.MaxX || p.Y < 0 || p.Y >= p.MaxY {
+		return fmt.Errorf("point (%d, %d) is outside boundaries %dx%d", p.X, p.Y, p.MaxX, p.MaxY)
+	}
+	return nil
+}
+
+// Collides reports whether the point occupies the same position as another point
+func (p *Point) Collides(other *Point) bool {
+	return other != nil && other != p && p.X == other.X && p.Y == other.Y
+}
+
+// MoveWithCollision moves the point by the specified offsets like Move,
+// but stops it adjacent to an obstacle instead of overlapping it
+// The X offset is applied first and then the Y offset, so a point blocked
+// on one axis still slides along the obstacle on the other
+// The point steps towards the clamped target, which may lie against the offset
+// (e.g. for a point outside its boundaries), so the walk always ends
+func (p *Point) MoveWithCollision(dx, dy int, obstacles []*Point) {
+	targetX := min(max(p.X+dx, 0), p.MaxX-1)
+	for step := sign(targetX - p.X); p.X != targetX && !p.occupied(p.X+step, p.Y, obstacles); {
+		p.X += step
+	}
+
+	targetY := min(max(p.Y+dy, 0), p.MaxY-1)
+	for step := sign(targetY - p.Y); p.Y != targetY && !p.occupied(p.X, p.Y+step, obstacles); {
+		p.Y += step
+	}
+}
+
+// occupied reports whether another point is at the given position
+func (p *Point) occupied(x, y int, obstacles []*Point) bool {
+	for _, obstacle := range obstacles {
+		if obstacle != nil && obstacle != p && obstacle.X == x && obstacle.Y == y {
+			return true
+		}
+	}
+	return false
+}
+
+// sign returns -1, 0 or 1 depending on the sign of v
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,140 @@
+package point_test
+
+import (
+	"testing"
+
+	"github.com/shngxx/point/internal/domain/point"
+)
+
+func TestCollides(t *testing.T) {
+	p := &point.Point{X: 5, Y: 5, MaxX: 10, MaxY: 10}
+
+	if !p.Collides(&point.Point{X: 5, Y: 5}) {
+		t.Error("Collides() = false for a point at the same position")
+	}
+	if p.Collides(&point.Point{X: 5, Y: 6}) {
+		t.Error("Collides() = true for an adjacent point")
+	}
+	if p.Collides(p) || p.Collides(nil) {
+		t.Error("Collides() = true for the point itself or nil")
+	}
+}
+
+func TestMoveWithCollision(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     point.Point
+		dx, dy    int
+		obstacles []*point.Point
+		expected  point.Point
+	}{
+		{
+			name:      "head-on approach stops adjacent",
+			start:     point.Point{X: 0, Y: 5, MaxX: 10, MaxY: 10},
+			dx:        8,
+			obstacles: []*point.Point{{X: 4, Y: 5}},
+			expected:  point.Point{X: 3, Y: 5, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:      "already adjacent does not move",
+			start:     point.Point{X: 3, Y: 5, MaxX: 10, MaxY: 10},
+			dx:        1,
+			obstacles: []*point.Point{{X: 4, Y: 5}},
+			expected:  point.Point{X: 3, Y: 5, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:      "obstacle beyond the target is ignored",
+			start:     point.Point{X: 0, Y: 5, MaxX: 10, MaxY: 10},
+			dx:        2,
+			obstacles: []*point.Point{{X: 4, Y: 5}},
+			expected:  point.Point{X: 2, Y: 5, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:      "slides along an obstacle",
+			start:     point.Point{X: 3, Y: 2, MaxX: 10, MaxY: 10},
+			dx:        2,
+			dy:        3,
+			obstacles: []*point.Point{{X: 4, Y: 2}},
+			expected:  point.Point{X: 3, Y: 5, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:      "moves around a corner",
+			start:     point.Point{X: 2, Y: 2, MaxX: 10, MaxY: 10},
+			dx:        3,
+			dy:        3,
+			obstacles: []*point.Point{{X: 5, Y: 4}},
+			expected:  point.Point{X: 5, Y: 3, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:      "obstacle at the boundary",
+			start:     point.Point{X: 5, Y: 9, MaxX: 10, MaxY: 10},
+			dx:        20,
+			dy:        20,
+			obstacles: []*point.Point{{X: 9, Y: 9}},
+			expected:  point.Point{X: 8, Y: 9, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:      "boundary reached before the obstacle",
+			start:     point.Point{X: 2, Y: 0, MaxX: 10, MaxY: 10},
+			dx:        -5,
+			dy:        -5,
+			obstacles: []*point.Point{{X: 0, Y: 1}},
+			expected:  point.Point{X: 0, Y: 0, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:      "boxed into a corner",
+			start:     point.Point{X: 0, Y: 0, MaxX: 10, MaxY: 10},
+			dx:        -1,
+			dy:        1,
+			obstacles: []*point.Point{{X: 1, Y: 0}, {X: 0, Y: 1}},
+			expected:  point.Point{X: 0, Y: 0, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:     "out of bounds start with zero offsets is clamped",
+			start:    point.Point{X: 15, Y: -3, MaxX: 10, MaxY: 10},
+			expected: point.Point{X: 9, Y: 0, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:     "out of bounds start moving away from the boundary",
+			start:    point.Point{X: 15, Y: 12, MaxX: 10, MaxY: 10},
+			dx:       5,
+			dy:       1,
+			expected: point.Point{X: 9, Y: 9, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:      "out of bounds start stops at an obstacle",
+			start:     point.Point{X: 15, Y: 5, MaxX: 10, MaxY: 10},
+			dx:        2,
+			obstacles: []*point.Point{{X: 9, Y: 5}},
+			expected:  point.Point{X: 10, Y: 5, MaxX: 10, MaxY: 10},
+		},
+		{
+			name:     "zero offsets inside the boundaries",
+			start:    point.Point{X: 4, Y: 4, MaxX: 10, MaxY: 10},
+			expected: point.Point{X: 4, Y: 4, MaxX: 10, MaxY: 10},
+		},
+		{
+			// Same coordinates as Move/Clamp give for zero-size boundaries
+			name:     "zero-size boundaries",
+			start:    point.Point{X: 0, Y: 0},
+			dx:       3,
+			dy:       3,
+			expected: point.Point{X: -1, Y: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.start
+			p.MoveWithCollision(tt.dx, tt.dy, tt.obstacles)
+			if p != tt.expected {
+				t.Errorf("MoveWithCollision(%d, %d) = %+v, expected %+v", tt.dx, tt.dy, p, tt.expected)
+			}
+			for _, obstacle := range tt.obstacles {
+				if p.Collides(obstacle) {
+					t.Errorf("Point %+v overlaps obstacle %+v", p, *obstacle)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package point
+
+import "time"
+
+// PointMovedEvent is published whenever the position of a point changes
+type PointMovedEvent struct {
+	ID           int
+	OldX         int
+	OldY         int
+	NewX         int
+	NewY         int
+	CommandCount int // Number of commands that produced the change
+	At           time.Time
+}
+
+// EventBus publishes point domain events
+// Publish must not block: it is called from the point movement loop
+type EventBus interface {
+	Publish(evt PointMovedEvent)
+}
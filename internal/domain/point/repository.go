@@ -1,6 +1,13 @@
 package point
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound возвращается репозиториями, в которых точки с таким идентификатором нет
+// (встроенные репозитории возвращают для неизвестных идентификаторов точку по умолчанию)
+var ErrNotFound = errors.New("point not found")
 
 // PointRepository определяет интерфейс репозитория для работы с точкой
 type PointRepository interface {
@@ -10,3 +17,18 @@ type PointRepository interface {
 	// Save сохраняет точку по идентификатору
 	Save(ctx context.Context, id int, p *Point) error
 }
+
+// Offset описывает относительное перемещение точки
+type Offset struct {
+	DX int
+	DY int
+}
+
+// Mover — необязательное расширение PointRepository для общего хранилища
+// (например, при нескольких экземплярах приложения): перемещения применяются атомарно
+// на стороне хранилища, поэтому параллельные перемещения одной точки не теряются
+type Mover interface {
+	// Move применяет смещения по порядку (с ограничением границами после каждого, как Point.Move)
+	// и возвращает точку до и после перемещения
+	Move(ctx context.Context, id int, offsets []Offset) (before, after *Point, err error)
+}
@@ -2,11 +2,11 @@ package http
 
 import (
 	"context"
-	"fmt"
-	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/shngxx/point/internal/usecase"
+	httpserver "github.com/shngxx/point/pkg/http"
+	"github.com/shngxx/point/pkg/http/response"
 )
 
 // GetPointService defines the interface for getting point information
@@ -14,33 +14,40 @@ type GetPointService interface {
 	GetPoint(ctx context.Context, id int) (*usecase.PointInfo, error)
 }
 
+// ErrorMapper maps errors to HTTP statuses (implemented by the HTTP server and its default error handler)
+type ErrorMapper interface {
+	MapError(target error, status int)
+}
+
+// RegisterErrors maps the use case errors returned by the handlers to client errors
+// A request whose context expired (see middleware.Timeout) is answered with 408 Request Timeout;
+// one cancelled before completion (e.g. the server shutting down) with 503 Service Unavailable
+func RegisterErrors(m ErrorMapper) {
+	m.MapError(usecase.ErrInvalidID, fiber.StatusBadRequest)
+	m.MapError(usecase.ErrNotFound, fiber.StatusNotFound)
+	m.MapError(context.DeadlineExceeded, fiber.StatusRequestTimeout)
+	m.MapError(context.Canceled, fiber.StatusServiceUnavailable)
+}
+
 // NewGetPointHandler creates a handler for getting point information
 func NewGetPointHandler(service GetPointService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// The request context carries the middleware.Timeout deadline (if any),
+		// so an expired request stops the repository lookup
 		ctx := c.UserContext()
-		if ctx == nil {
-			ctx = context.Background()
-		}
-
-		id := c.Params("id")
-		if id == "" {
-			id = "1"
-		}
 
-		pointID, err := strconv.Atoi(id)
-		if err != nil || pointID <= 0 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": fmt.Sprintf("Invalid point ID: %s", id),
-			})
+		pointID, err := httpserver.ParamInt(c, "id", 1)
+		if err != nil {
+			return err
 		}
 
+		// ErrInvalidID and ErrNotFound are mapped to client errors by the error handler (see RegisterErrors)
 		pointInfo, err := service.GetPoint(ctx, pointID)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": fmt.Sprintf("Error getting point information: %v", err),
-			})
+			return err
 		}
 
-		return c.JSON(pointInfo)
+		// Clients polling the point get 304 Not Modified while it has not changed
+		return response.JSONWithETag(c, pointInfo)
 	}
 }
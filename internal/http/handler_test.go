@@ -0,0 +1,204 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shngxx/point/internal/domain/point"
+	httphandler "github.com/shngxx/point/internal/http"
+	"github.com/shngxx/point/internal/infrastructure/db"
+	"github.com/shngxx/point/internal/usecase"
+	httpserver "github.com/shngxx/point/pkg/http"
+	httperrors "github.com/shngxx/point/pkg/http/errors"
+)
+
+// TestGetPointHandler tests that the point response includes the point's boundaries
+func TestGetPointHandler(t *testing.T) {
+	repo := db.NewPointRepositoryWithConfig(100, 50)
+	app := fiber.New()
+	app.Get("/api/point/:id", httphandler.NewGetPointHandler(usecase.NewGetPointUC(repo)))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/point/3", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("Status = %d, expected 200", resp.StatusCode)
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if body["id"] != float64(3) || body["maxX"] != float64(100) || body["maxY"] != float64(50) {
+		t.Errorf("Body = %v, expected id 3 and boundaries 100x50", body)
+	}
+	if _, ok := body["point"].(map[string]any); !ok {
+		t.Errorf("Body = %v, expected the point position", body)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/point/abc", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("Status for an invalid ID = %d, expected 400", resp.StatusCode)
+	}
+}
+
+// TestGetPointHandler_ETag tests that a conditional GET with the returned ETag is answered
+// with 304 Not Modified until the point changes
+func TestGetPointHandler_ETag(t *testing.T) {
+	repo := db.NewPointRepository()
+	app := fiber.New()
+	app.Get("/api/point/:id", httphandler.NewGetPointHandler(usecase.NewGetPointUC(repo)))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/point/1", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	etag := resp.Header.Get(fiber.HeaderETag)
+	if resp.StatusCode != 200 || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("Status = %d, ETag = %q, expected 200 with a weak ETag", resp.StatusCode, etag)
+	}
+
+	conditional := func() *nethttp.Response {
+		req := httptest.NewRequest("GET", "/api/point/1", nil)
+		req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+		return resp
+	}
+
+	resp = conditional()
+	if resp.StatusCode != fiber.StatusNotModified {
+		t.Errorf("Conditional status = %d, expected 304", resp.StatusCode)
+	}
+	if body, _ := io.ReadAll(resp.Body); len(body) != 0 {
+		t.Errorf("304 body = %q, expected empty", body)
+	}
+
+	// Moving the point changes the ETag
+	p, _ := repo.Get(context.Background(), 1)
+	p.Move(1, 0)
+	if err := repo.Save(context.Background(), 1, p); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	resp = conditional()
+	if resp.StatusCode != 200 || resp.Header.Get(fiber.HeaderETag) == etag {
+		t.Errorf("Status after move = %d, ETag = %q, expected 200 with a new ETag", resp.StatusCode, resp.Header.Get(fiber.HeaderETag))
+	}
+}
+
+// missingRepository is a point repository without any points
+type missingRepository struct{}
+
+func (missingRepository) Get(ctx context.Context, id int) (*point.Point, error) {
+	return nil, fmt.Errorf("get point %d: %w", id, point.ErrNotFound)
+}
+
+func (missingRepository) Save(ctx context.Context, id int, p *point.Point) error {
+	return nil
+}
+
+// TestGetPointHandler_Errors tests that use case errors are answered with client errors
+// by the server's error handler
+func TestGetPointHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		repo   point.PointRepository
+		path   string
+		status int
+		code   string
+	}{
+		{"invalid id", db.NewPointRepository(), "/api/point/-1", fiber.StatusBadRequest, httperrors.CodeBadRequest},
+		{"missing point", missingRepository{}, "/api/point/7", fiber.StatusNotFound, httperrors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpserver.New()
+			httphandler.RegisterErrors(server)
+			server.GET("/api/point/:id", httphandler.NewGetPointHandler(usecase.NewGetPointUC(tt.repo)))
+
+			resp, err := server.App().Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("Status = %d, expected %d", resp.StatusCode, tt.status)
+			}
+
+			var body httperrors.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if body.Code != tt.code {
+				t.Errorf("Code = %q, expected %q", body.Code, tt.code)
+			}
+		})
+	}
+}
+
+// TestGetPointHandler_Context tests that the request context reaches the repository,
+// so an expired or cancelled request fails with a context error and a matching status
+func TestGetPointHandler_Context(t *testing.T) {
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		status int
+		code   string
+	}{
+		{"deadline exceeded", expired, context.DeadlineExceeded, fiber.StatusRequestTimeout, httperrors.CodeTimeout},
+		{"cancelled", cancelled, context.Canceled, fiber.StatusServiceUnavailable, httperrors.CodeServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := usecase.NewGetPointUC(db.NewPointRepository())
+			if _, err := uc.GetPoint(tt.ctx, 1); !errors.Is(err, tt.err) {
+				t.Fatalf("GetPoint() error = %v, expected %v", err, tt.err)
+			}
+
+			server := httpserver.New()
+			httphandler.RegisterErrors(server)
+			server.Use(func(c *fiber.Ctx) error {
+				c.SetUserContext(tt.ctx)
+				return c.Next()
+			})
+			server.GET("/api/point/:id", httphandler.NewGetPointHandler(uc))
+
+			resp, err := server.App().Test(httptest.NewRequest("GET", "/api/point/1", nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("Status = %d, expected %d", resp.StatusCode, tt.status)
+			}
+
+			var body httperrors.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if body.Code != tt.code {
+				t.Errorf("Code = %q, expected %q", body.Code, tt.code)
+			}
+		})
+	}
+}
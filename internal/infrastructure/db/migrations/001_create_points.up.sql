@@ -0,0 +1,8 @@
+-- Points and their movement boundaries, see db.PostgresPointRepository
+CREATE TABLE IF NOT EXISTS points (
+    id    INTEGER PRIMARY KEY,
+    x     INTEGER NOT NULL,
+    y     INTEGER NOT NULL,
+    max_x INTEGER NOT NULL,
+    max_y INTEGER NOT NULL
+);
@@ -12,20 +12,28 @@ import (
 type PointRepository struct {
 	mu     sync.RWMutex
 	points map[int]*point.Point
+	maxX   int // Boundaries of new points
+	maxY   int
 }
 
-// NewPointRepository creates a new repository
+// NewPointRepository creates a new repository with default point boundaries
 func NewPointRepository() *PointRepository {
-	// Initialize with default point
-	points := make(map[int]*point.Point)
-	// Create default point with ID 1 and boundaries
-	points[1] = point.NewPoint(0, 0, 0, 0)
+	return NewPointRepositoryWithConfig(point.DefaultMaxX, point.DefaultMaxY)
+}
+
+// NewPointRepositoryWithConfig creates a new repository whose new points get the given boundaries
+// If maxX or maxY equals 0, default boundaries are used
+func NewPointRepositoryWithConfig(maxX, maxY int) *PointRepository {
+	defaults := point.NewPoint(0, 0, maxX, maxY)
 	return &PointRepository{
-		points: points,
+		points: make(map[int]*point.Point),
+		maxX:   defaults.MaxX,
+		maxY:   defaults.MaxY,
 	}
 }
 
 // Get returns a point by identifier
+// A point that was never saved is returned with default coordinates and the repository boundaries
 func (r *PointRepository) Get(ctx context.Context, id int) (*point.Point, error) {
 	// Check context
 	if ctx.Err() != nil {
@@ -36,20 +44,12 @@ func (r *PointRepository) Get(ctx context.Context, id int) (*point.Point, error)
 	defer r.mu.RUnlock()
 
 	// TODO: in the future this will be a database query by id
-	// For now, return the point from memory or create a default one
 	p, exists := r.points[id]
 	if !exists {
-		// Return default point if not found (use boundaries from first point if exists)
-		if len(r.points) > 0 {
-			// Use boundaries from existing point
-			for _, existingPoint := range r.points {
-				p = point.NewPoint(0, 0, existingPoint.MaxX, existingPoint.MaxY)
-				break
-			}
-		} else {
-			// Use default boundaries
-			p = point.NewPoint(0, 0, 0, 0)
-		}
+		p = point.NewPoint(0, 0, r.maxX, r.maxY)
+		// Default coordinates may lie outside small boundaries
+		p.Clamp()
+		return p, nil
 	}
 
 	// Create a copy for safety
@@ -62,6 +62,7 @@ func (r *PointRepository) Get(ctx context.Context, id int) (*point.Point, error)
 }
 
 // Save saves a point by identifier
+// Zero boundaries of p keep the stored boundaries (or the repository ones for a new point)
 func (r *PointRepository) Save(ctx context.Context, id int, p *point.Point) error {
 	// Check context
 	if ctx.Err() != nil {
@@ -76,23 +77,23 @@ func (r *PointRepository) Save(ctx context.Context, id int, p *point.Point) erro
 	defer r.mu.Unlock()
 
 	// TODO: in the future this will be saved to database
-	// For now, update the point in memory
-	if r.points[id] == nil {
-		// Create new point with boundaries from existing point or defaults
-		if len(r.points) > 0 {
-			for _, existingPoint := range r.points {
-				r.points[id] = point.NewPoint(p.X, p.Y, existingPoint.MaxX, existingPoint.MaxY)
-				return nil
-			}
-		}
-		r.points[id] = point.NewPoint(p.X, p.Y, 0, 0)
-		return nil
+	maxX, maxY := r.maxX, r.maxY
+	if existing := r.points[id]; existing != nil {
+		maxX, maxY = existing.MaxX, existing.MaxY
+	}
+	if p.MaxX > 0 {
+		maxX = p.MaxX
+	}
+	if p.MaxY > 0 {
+		maxY = p.MaxY
+	}
+
+	r.points[id] = &point.Point{
+		X:    p.X,
+		Y:    p.Y,
+		MaxX: maxX,
+		MaxY: maxY,
 	}
-	r.points[id].X = p.X
-	r.points[id].Y = p.Y
-	// Preserve boundaries
-	r.points[id].MaxX = p.MaxX
-	r.points[id].MaxY = p.MaxY
 
 	return nil
 }
@@ -0,0 +1,273 @@
+package db_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/shngxx/point/internal/domain/point"
+	"github.com/shngxx/point/internal/infrastructure/db"
+)
+
+// postgresDSNEnv is the environment variable with the DSN of a test database
+// PostgreSQL tests are skipped when it is not set
+const postgresDSNEnv = "POINT_TEST_POSTGRES_DSN"
+
+// testPointRepository runs the point.PointRepository contract tests shared by all implementations
+// newRepository must return an empty repository
+func testPointRepository(t *testing.T, newRepository func(t *testing.T) point.PointRepository) {
+	ctx := context.Background()
+
+	t.Run("get unsaved point returns default", func(t *testing.T) {
+		repository := newRepository(t)
+		p, err := repository.Get(ctx, 42)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if p.MaxX <= 0 || p.MaxY <= 0 || p.X < 0 || p.X >= p.MaxX || p.Y < 0 || p.Y >= p.MaxY {
+			t.Errorf("Get() = %+v, expected a default point within its boundaries", p)
+		}
+	})
+
+	t.Run("save and get", func(t *testing.T) {
+		repository := newRepository(t)
+		saved := &point.Point{X: 10, Y: 20, MaxX: point.DefaultMaxX, MaxY: point.DefaultMaxY}
+		if err := repository.Save(ctx, 2, saved); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		p, err := repository.Get(ctx, 2)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if *p != *saved {
+			t.Errorf("Get() = %+v, expected %+v", p, saved)
+		}
+	})
+
+	t.Run("save overwrites", func(t *testing.T) {
+		repository := newRepository(t)
+		for _, x := range []int{10, 30} {
+			if err := repository.Save(ctx, 3, &point.Point{X: x, Y: 5, MaxX: point.DefaultMaxX, MaxY: point.DefaultMaxY}); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+		}
+
+		p, err := repository.Get(ctx, 3)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if p.X != 30 || p.Y != 5 {
+			t.Errorf("Get() = %+v, expected the last saved position", p)
+		}
+	})
+
+	t.Run("returned point is a copy", func(t *testing.T) {
+		repository := newRepository(t)
+		if err := repository.Save(ctx, 4, &point.Point{X: 1, Y: 1, MaxX: point.DefaultMaxX, MaxY: point.DefaultMaxY}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		p, _ := repository.Get(ctx, 4)
+		p.X = 99
+
+		p, _ = repository.Get(ctx, 4)
+		if p.X != 1 {
+			t.Errorf("Modifying a returned point changed the stored one: %+v", p)
+		}
+	})
+
+	t.Run("points are independent", func(t *testing.T) {
+		repository := newRepository(t)
+		if err := repository.Save(ctx, 6, &point.Point{X: 1, Y: 2, MaxX: point.DefaultMaxX, MaxY: point.DefaultMaxY}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := repository.Save(ctx, 7, &point.Point{X: 3, Y: 4, MaxX: point.DefaultMaxX, MaxY: point.DefaultMaxY}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		first, _ := repository.Get(ctx, 6)
+		second, _ := repository.Get(ctx, 7)
+		if first.X != 1 || first.Y != 2 || second.X != 3 || second.Y != 4 {
+			t.Errorf("Get() = %+v and %+v, expected independent points (1, 2) and (3, 4)", first, second)
+		}
+	})
+
+	t.Run("save nil point", func(t *testing.T) {
+		repository := newRepository(t)
+		if err := repository.Save(ctx, 5, nil); err == nil {
+			t.Error("Save() should return error for nil point")
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		repository := newRepository(t)
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		if _, err := repository.Get(canceled, 1); err == nil {
+			t.Error("Get() should return error for canceled context")
+		}
+		if err := repository.Save(canceled, 1, point.NewPoint(0, 0, 0, 0)); err == nil {
+			t.Error("Save() should return error for canceled context")
+		}
+	})
+}
+
+func TestPointRepository(t *testing.T) {
+	testPointRepository(t, func(t *testing.T) point.PointRepository {
+		return db.NewPointRepository()
+	})
+}
+
+func TestPointRepositoryWithConfig(t *testing.T) {
+	ctx := context.Background()
+	repository := db.NewPointRepositoryWithConfig(100, 50)
+
+	// Saving one point does not affect the boundaries or state of another
+	if err := repository.Save(ctx, 1, &point.Point{X: 10, Y: 10, MaxX: 1000, MaxY: 1000}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	p, err := repository.Get(ctx, 2)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	// Default coordinates are clamped to the configured boundaries
+	expected := point.Point{X: 99, Y: 49, MaxX: 100, MaxY: 50}
+	if *p != expected {
+		t.Errorf("Get() unknown id = %+v, expected %+v", *p, expected)
+	}
+
+	if err := repository.Save(ctx, 2, &point.Point{X: 5, Y: 5}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	p, _ = repository.Get(ctx, 2)
+	expected = point.Point{X: 5, Y: 5, MaxX: 100, MaxY: 50}
+	if *p != expected {
+		t.Errorf("Get() after save = %+v, expected %+v", *p, expected)
+	}
+}
+
+func TestPostgresPointRepository(t *testing.T) {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migration, err := os.ReadFile("migrations/001_create_points.up.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := pool.Exec(ctx, string(migration)); err != nil {
+		t.Fatalf("failed to apply migration: %v", err)
+	}
+
+	testPointRepository(t, func(t *testing.T) point.PointRepository {
+		if _, err := pool.Exec(ctx, "TRUNCATE points"); err != nil {
+			t.Fatalf("failed to truncate points: %v", err)
+		}
+		return db.NewPostgresPointRepository(pool)
+	})
+}
+
+// newRedisPointRepository creates a Redis repository backed by an in-process miniredis server
+func newRedisPointRepository(t *testing.T) *db.RedisPointRepository {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return db.NewRedisPointRepository(client)
+}
+
+func TestRedisPointRepository(t *testing.T) {
+	testPointRepository(t, func(t *testing.T) point.PointRepository {
+		return newRedisPointRepository(t)
+	})
+}
+
+func TestRedisPointRepository_Move(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("clamps after each offset", func(t *testing.T) {
+		repository := newRedisPointRepository(t)
+		if err := repository.Save(ctx, 1, &point.Point{X: 2, Y: 2, MaxX: 10, MaxY: 10}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		offsets := []point.Offset{{DX: -5, DY: 20}, {DX: 3, DY: -1}}
+		before, after, err := repository.Move(ctx, 1, offsets)
+		if err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+
+		// Same result as moving the point in memory
+		expected := &point.Point{X: 2, Y: 2, MaxX: 10, MaxY: 10}
+		for _, offset := range offsets {
+			expected.Move(offset.DX, offset.DY)
+		}
+		if before.X != 2 || before.Y != 2 {
+			t.Errorf("Move() before = %+v, expected the saved position", before)
+		}
+		if *after != *expected {
+			t.Errorf("Move() after = %+v, expected %+v", after, expected)
+		}
+		if stored, _ := repository.Get(ctx, 1); *stored != *expected {
+			t.Errorf("Get() = %+v, expected moved point %+v", stored, expected)
+		}
+	})
+
+	t.Run("unsaved point starts at default", func(t *testing.T) {
+		repository := newRedisPointRepository(t)
+		before, after, err := repository.Move(ctx, 2, []point.Offset{{DX: 1, DY: 1}})
+		if err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		if *before != *point.NewPoint(0, 0, 0, 0) {
+			t.Errorf("Move() before = %+v, expected the default point", before)
+		}
+		if after.X != before.X+1 || after.Y != before.Y+1 {
+			t.Errorf("Move() after = %+v, expected the default point moved by 1", after)
+		}
+	})
+
+	t.Run("concurrent moves are not lost", func(t *testing.T) {
+		repository := newRedisPointRepository(t)
+		if err := repository.Save(ctx, 3, &point.Point{X: 0, Y: 0, MaxX: 1000, MaxY: 1000}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		const instances, moves = 10, 20
+		var wg sync.WaitGroup
+		for range instances {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range moves {
+					if _, _, err := repository.Move(ctx, 3, []point.Offset{{DX: 1, DY: 2}}); err != nil {
+						t.Errorf("Move() error = %v", err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		p, err := repository.Get(ctx, 3)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if p.X != instances*moves || p.Y != 2*instances*moves {
+			t.Errorf("Get() = %+v, expected every move to be applied", p)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shngxx/point/internal/domain/point"
+)
+
+// PostgresPointRepository implements the domain.PointRepository interface on top of PostgreSQL
+// Points are stored in the points table (see migrations/001_create_points.up.sql)
+type PostgresPointRepository struct {
+	pool *pgxpool.Pool
+	maxX int // Boundaries of new points
+	maxY int
+}
+
+// NewPostgresPointRepository creates a new PostgreSQL repository with default point boundaries
+func NewPostgresPointRepository(pool *pgxpool.Pool) *PostgresPointRepository {
+	return NewPostgresPointRepositoryWithConfig(pool, point.DefaultMaxX, point.DefaultMaxY)
+}
+
+// NewPostgresPointRepositoryWithConfig creates a new PostgreSQL repository whose new points get the given boundaries
+// If maxX or maxY equals 0, default boundaries are used
+func NewPostgresPointRepositoryWithConfig(pool *pgxpool.Pool, maxX, maxY int) *PostgresPointRepository {
+	defaults := point.NewPoint(0, 0, maxX, maxY)
+	return &PostgresPointRepository{
+		pool: pool,
+		maxX: defaults.MaxX,
+		maxY: defaults.MaxY,
+	}
+}
+
+// Get returns a point by identifier
+// A point that was never saved is returned with default coordinates and the repository boundaries
+func (r *PostgresPointRepository) Get(ctx context.Context, id int) (*point.Point, error) {
+	var p point.Point
+	err := r.pool.QueryRow(ctx,
+		`SELECT x, y, max_x, max_y FROM points WHERE id = $1`,
+		id,
+	).Scan(&p.X, &p.Y, &p.MaxX, &p.MaxY)
+	if errors.Is(err, pgx.ErrNoRows) {
+		p := point.NewPoint(0, 0, r.maxX, r.maxY)
+		// Default coordinates may lie outside small boundaries
+		p.Clamp()
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get point %d: %w", id, err)
+	}
+	return &p, nil
+}
+
+// Save saves a point by identifier
+// Inserts or updates the point in a single round-trip
+// Points outside their boundaries are rejected (see point.Point.Validate)
+func (r *PostgresPointRepository) Save(ctx context.Context, id int, p *point.Point) error {
+	if p == nil {
+		return fmt.Errorf("point cannot be nil")
+	}
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("save point %d: %w", id, err)
+	}
+
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO points (id, x, y, max_x, max_y) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET x = EXCLUDED.x, y = EXCLUDED.y, max_x = EXCLUDED.max_x, max_y = EXCLUDED.max_y`,
+		id, p.X, p.Y, p.MaxX, p.MaxY,
+	)
+	if err != nil {
+		return fmt.Errorf("save point %d: %w", id, err)
+	}
+	return nil
+}
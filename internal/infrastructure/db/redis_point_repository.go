@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shngxx/point/internal/domain/point"
+)
+
+// moveScript applies offsets to a point hash atomically and returns the point before and after
+// KEYS[1] is the point key; ARGV holds the default x, y, maxX, maxY followed by dx, dy pairs
+// Clamping after each offset mirrors point.Point.Move
+var moveScript = redis.NewScript(`
+local v = redis.call('HMGET', KEYS[1], 'x', 'y', 'maxX', 'maxY')
+local x, y, maxX, maxY
+if v[1] then
+	x, y, maxX, maxY = tonumber(v[1]), tonumber(v[2]), tonumber(v[3]), tonumber(v[4])
+else
+	x, y, maxX, maxY = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3]), tonumber(ARGV[4])
+end
+local oldX, oldY = x, y
+for i = 5, #ARGV, 2 do
+	x = x + tonumber(ARGV[i])
+	y = y + tonumber(ARGV[i + 1])
+	if x < 0 then x = 0 end
+	if x >= maxX then x = maxX - 1 end
+	if y < 0 then y = 0 end
+	if y >= maxY then y = maxY - 1 end
+end
+redis.call('HSET', KEYS[1], 'x', x, 'y', y, 'maxX', maxX, 'maxY', maxY)
+return {oldX, oldY, x, y, maxX, maxY}
+`)
+
+// RedisPointRepository implements the domain.PointRepository interface on top of Redis,
+// so that several application instances share point state
+// Each point is stored as a hash point:<id> with the fields x, y, maxX and maxY
+//
+// Consistency: Save overwrites the whole point (last write wins), so a Get/modify/Save cycle
+// on two instances at once can lose one of the updates. Move (point.Mover) applies offsets
+// in a single atomic script instead and should be used for concurrent movement.
+type RedisPointRepository struct {
+	client *redis.Client
+}
+
+// NewRedisPointRepository creates a new Redis repository
+func NewRedisPointRepository(client *redis.Client) *RedisPointRepository {
+	return &RedisPointRepository{
+		client: client,
+	}
+}
+
+// pointKey returns the Redis key of a point
+func pointKey(id int) string {
+	return "point:" + strconv.Itoa(id)
+}
+
+// Get returns a point by identifier
+// A point that was never saved is returned with default coordinates and boundaries
+func (r *RedisPointRepository) Get(ctx context.Context, id int) (*point.Point, error) {
+	values, err := r.client.HMGet(ctx, pointKey(id), "x", "y", "maxX", "maxY").Result()
+	if err != nil {
+		return nil, fmt.Errorf("get point %d: %w", id, err)
+	}
+	if values[0] == nil {
+		return point.NewPoint(0, 0, 0, 0), nil
+	}
+
+	coords := make([]int, len(values))
+	for i, value := range values {
+		s, _ := value.(string)
+		if coords[i], err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("get point %d: invalid stored value %q", id, s)
+		}
+	}
+	return &point.Point{X: coords[0], Y: coords[1], MaxX: coords[2], MaxY: coords[3]}, nil
+}
+
+// Save saves a point by identifier in a single round-trip
+// Points outside their boundaries are rejected (see point.Point.Validate)
+func (r *RedisPointRepository) Save(ctx context.Context, id int, p *point.Point) error {
+	if p == nil {
+		return fmt.Errorf("point cannot be nil")
+	}
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("save point %d: %w", id, err)
+	}
+
+	err := r.client.HSet(ctx, pointKey(id), "x", p.X, "y", p.Y, "maxX", p.MaxX, "maxY", p.MaxY).Err()
+	if err != nil {
+		return fmt.Errorf("save point %d: %w", id, err)
+	}
+	return nil
+}
+
+// Move applies offsets to a point atomically (see point.Mover)
+func (r *RedisPointRepository) Move(ctx context.Context, id int, offsets []point.Offset) (before, after *point.Point, err error) {
+	defaults := point.NewPoint(0, 0, 0, 0)
+	args := make([]any, 0, 4+2*len(offsets))
+	args = append(args, defaults.X, defaults.Y, defaults.MaxX, defaults.MaxY)
+	for _, offset := range offsets {
+		args = append(args, offset.DX, offset.DY)
+	}
+
+	result, err := moveScript.Run(ctx, r.client, []string{pointKey(id)}, args...).Int64Slice()
+	if err != nil {
+		return nil, nil, fmt.Errorf("move point %d: %w", id, err)
+	}
+
+	maxX, maxY := int(result[4]), int(result[5])
+	before = &point.Point{X: int(result[0]), Y: int(result[1]), MaxX: maxX, MaxY: maxY}
+	after = &point.Point{X: int(result[2]), Y: int(result[3]), MaxX: maxX, MaxY: maxY}
+	return before, after, nil
+}
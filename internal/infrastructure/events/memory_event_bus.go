@@ -0,0 +1,56 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/shngxx/point/internal/domain/point"
+)
+
+// MemoryEventBus implements the point.EventBus interface in memory
+// Every subscriber receives all published events through its own buffered channel
+type MemoryEventBus struct {
+	mu          sync.RWMutex
+	subscribers map[chan point.PointMovedEvent]struct{}
+}
+
+// NewMemoryEventBus creates a new in-memory event bus
+func NewMemoryEventBus() *MemoryEventBus {
+	return &MemoryEventBus{
+		subscribers: make(map[chan point.PointMovedEvent]struct{}),
+	}
+}
+
+// Publish sends an event to all subscribers
+// Events are dropped for subscribers whose buffer is full, so a slow subscriber never blocks movement
+func (b *MemoryEventBus) Publish(evt point.PointMovedEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- evt:
+		default:
+			// Subscriber is not keeping up, drop the event
+		}
+	}
+}
+
+// Subscribe returns a channel receiving published events and a function that cancels the subscription
+// The channel is closed on unsubscribe
+func (b *MemoryEventBus) Subscribe(buffer int) (events <-chan point.PointMovedEvent, unsubscribe func()) {
+	ch := make(chan point.PointMovedEvent, buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
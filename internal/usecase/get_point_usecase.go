@@ -2,11 +2,19 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/shngxx/point/internal/domain/point"
 )
 
+var (
+	// ErrInvalidID is returned for point identifiers that are not positive
+	ErrInvalidID = errors.New("invalid point id")
+	// ErrNotFound is returned when the repository has no point with the identifier
+	ErrNotFound = errors.New("point not found")
+)
+
 // GetPointUC implements the use case: getting point information
 type GetPointUC struct {
 	pointRepository point.PointRepository
@@ -20,24 +28,33 @@ func NewGetPointUC(repository point.PointRepository) *GetPointUC {
 }
 
 // PointInfo contains information about a point
+// MaxX and MaxY are the point's boundaries (the play area is 0..MaxX-1 by 0..MaxY-1)
 type PointInfo struct {
 	ID    int          `json:"id"`
 	Point *point.Point `json:"point"`
+	MaxX  int          `json:"maxX"`
+	MaxY  int          `json:"maxY"`
 }
 
 // GetPoint executes the use case: gets point information by ID
+// Returns ErrInvalidID or ErrNotFound (check with errors.Is) for client errors
 func (u *GetPointUC) GetPoint(ctx context.Context, id int) (*PointInfo, error) {
 	if id <= 0 {
-		return nil, fmt.Errorf("invalid point id: %d", id)
+		return nil, fmt.Errorf("%w: %d", ErrInvalidID, id)
 	}
 
 	p, err := u.pointRepository.Get(ctx, id)
+	if errors.Is(err, point.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get point: %w", err)
 	}
 
 	return &PointInfo{
 		ID:    id,
-		Point: &point.Point{X: p.X, Y: p.Y},
+		Point: p,
+		MaxX:  p.MaxX,
+		MaxY:  p.MaxY,
 	}, nil
 }
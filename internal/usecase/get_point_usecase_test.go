@@ -0,0 +1,70 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shngxx/point/internal/domain/point"
+	"github.com/shngxx/point/internal/infrastructure/db"
+	"github.com/shngxx/point/internal/usecase"
+)
+
+// TestGetPointUC_PreservesBoundaries tests that point boundaries survive a get→move→save round-trip
+func TestGetPointUC_PreservesBoundaries(t *testing.T) {
+	repo := db.NewPointRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := repo.Save(ctx, 1, &point.Point{X: 10, Y: 10, MaxX: 50, MaxY: 40}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	getUC := usecase.NewGetPointUC(repo)
+	info, err := getUC.GetPoint(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetPoint() error = %v", err)
+	}
+	if info.Point.MaxX != 50 || info.Point.MaxY != 40 {
+		t.Fatalf("GetPoint() boundaries = %dx%d, expected 50x40", info.Point.MaxX, info.Point.MaxY)
+	}
+
+	logger := zerolog.Nop()
+	moveUC := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+	})
+	sessionCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	session := moveUC.Init(sessionCtx, 1)
+	session.Push(usecase.MoveCommand{ID: 1, DX: 100, DY: 100})
+
+	select {
+	case pos := <-session.PositionChan():
+		if pos.X != 49 || pos.Y != 39 {
+			t.Errorf("Position = (%d, %d), expected clamping to (49, 39)", pos.X, pos.Y)
+		}
+	case err := <-session.ErrorChan():
+		t.Fatalf("Unexpected batch error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("No position update received")
+	}
+
+	// The moved position is saved when the session stops
+	stop()
+	for range session.PositionChan() {
+	}
+
+	info, err = getUC.GetPoint(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetPoint() error = %v", err)
+	}
+	expected := point.Point{X: 49, Y: 39, MaxX: 50, MaxY: 40}
+	if *info.Point != expected {
+		t.Errorf("GetPoint() after move = %+v, expected %+v", *info.Point, expected)
+	}
+	if err := info.Point.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RecordedMove is a move applied to a point, with the time it was applied
+type RecordedMove struct {
+	DX int       `json:"dx"`
+	DY int       `json:"dy"`
+	At time.Time `json:"at"`
+}
+
+// moveHistory is a ring buffer of the last moves applied to a point
+type moveHistory struct {
+	moves []RecordedMove
+	next  int  // Index of the next move to overwrite
+	full  bool // Whether the buffer has wrapped
+}
+
+// add appends a move, overwriting the oldest one once the buffer is full
+func (h *moveHistory) add(move RecordedMove) {
+	h.moves[h.next] = move
+	h.next = (h.next + 1) % len(h.moves)
+	h.full = h.full || h.next == 0
+}
+
+// list returns a copy of the recorded moves, oldest first
+func (h *moveHistory) list() []RecordedMove {
+	if !h.full {
+		return append([]RecordedMove(nil), h.moves[:h.next]...)
+	}
+	moves := make([]RecordedMove, 0, len(h.moves))
+	moves = append(moves, h.moves[h.next:]...)
+	return append(moves, h.moves[:h.next]...)
+}
+
+// moveRecorder records the moves applied to each point
+type moveRecorder struct {
+	mu     sync.Mutex
+	size   int
+	points map[int]*moveHistory
+}
+
+// newMoveRecorder creates a recorder keeping the last size moves of each point
+func newMoveRecorder(size int) *moveRecorder {
+	return &moveRecorder{
+		size:   size,
+		points: make(map[int]*moveHistory),
+	}
+}
+
+// record appends moves applied to a point at the given time
+func (r *moveRecorder) record(id int, commands []MoveCommand, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.points[id]
+	if !ok {
+		h = &moveHistory{moves: make([]RecordedMove, r.size)}
+		r.points[id] = h
+	}
+	for _, cmd := range commands {
+		h.add(RecordedMove{DX: cmd.DX, DY: cmd.DY, At: at})
+	}
+}
+
+// history returns the recorded moves of a point, oldest first
+func (r *moveRecorder) history(id int) []RecordedMove {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if h, ok := r.points[id]; ok {
+		return h.list()
+	}
+	return nil
+}
+
+// GetHistory returns the last moves applied to a point, oldest first
+// Returns nil if recording is disabled (MovePointConfig.HistorySize is 0)
+// In physics mode the recorded moves are the per-tick offsets, not the client commands
+func (u *MovePointUC) GetHistory(id int) []RecordedMove {
+	if u.recorder == nil {
+		return nil
+	}
+	return u.recorder.history(id)
+}
+
+// Replay re-applies recorded moves to a point, waiting between moves as long as
+// the original moves were apart divided by speed (e.g. 2 replays twice as fast)
+// Moves are applied like a batch (with a PointMovedEvent for every position change)
+// but are not recorded again. Blocks until all moves are applied or ctx is canceled.
+//
+// Example:
+//
+//	history := uc.GetHistory(1)
+//	err := uc.Replay(ctx, 1, history, 2)
+func (u *MovePointUC) Replay(ctx context.Context, id int, history []RecordedMove, speed float64) error {
+	if speed <= 0 {
+		return fmt.Errorf("invalid replay speed: %v", speed)
+	}
+
+	// Replayed moves are applied by the point's actor, in order with the moves of its sessions
+	actor := u.acquire(id)
+	actor.control <- joinMessage{}
+	defer u.release(actor, nil)
+
+	for i, move := range history {
+		if i > 0 {
+			if wait := time.Duration(float64(move.At.Sub(history[i-1].At)) / speed); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		oldX, oldY, p, err := actor.apply([]MoveCommand{{ID: id, DX: move.DX, DY: move.DY}})
+		if err != nil {
+			return fmt.Errorf("replay move %d of point %d: %w", i, id, err)
+		}
+		if p.X != oldX || p.Y != oldY {
+			u.publishMoved(id, oldX, oldY, p, 1)
+		}
+	}
+	return nil
+}
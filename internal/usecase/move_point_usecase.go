@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -15,173 +18,281 @@ type MoveCommand struct {
 	DY int
 }
 
+// TeleportCommand represents a command to move a point to absolute coordinates
+type TeleportCommand struct {
+	ID int
+	X  int
+	Y  int
+}
+
+// PauseCommand pauses applying move commands of a session
+type PauseCommand struct{}
+
+// ResumeCommand resumes applying move commands of a paused session
+type ResumeCommand struct{}
+
+// PausePolicy defines what happens to move commands received while a session is paused
+type PausePolicy int
+
+const (
+	// QueuePaused accumulates commands and applies them on resume (default)
+	QueuePaused PausePolicy = iota
+
+	// DropPaused discards commands received while paused
+	DropPaused
+)
+
+// ErrPaused is sent to a session whose teleport was rejected because the session is paused
+var ErrPaused = errors.New("point movement is paused")
+
+// finalSaveTimeout limits the save of unsaved changes when processing stops
+const finalSaveTimeout = 5 * time.Second
+
+// maxPausedCommands limits the number of commands queued while a session is paused
+const maxPausedCommands = 1000
+
 // MovePointConfig contains configuration for MovePointUC
 type MovePointConfig struct {
 	BatchInterval time.Duration // Batch processing interval (~60 FPS)
 	SaveInterval  time.Duration // Position save interval
+	MaxBackoff    time.Duration // Maximum batch delay after consecutive failures (default: 5s)
+	PausePolicy   PausePolicy   // Handling of commands received while paused (default: queue)
+
+	// PhysicsEnabled makes move commands set a velocity (cells per batch tick)
+	// that is integrated into the position every tick instead of applying instant offsets
+	PhysicsEnabled bool
+	Friction       float64 // Fraction of velocity lost every tick in physics mode, 0..1 (default: 0)
+
+	// HistorySize is the number of applied moves recorded per point for GetHistory and Replay
+	// Default: 0 (recording disabled)
+	HistorySize int
+}
+
+// friction returns the friction limited to 0..1
+func (c MovePointConfig) friction() float64 {
+	return min(max(c.Friction, 0), 1)
+}
+
+// maxBackoff returns the maximum batch backoff with default fallback
+func (c MovePointConfig) maxBackoff() time.Duration {
+	if c.MaxBackoff > 0 {
+		return c.MaxBackoff
+	}
+	return 5 * time.Second
 }
 
 // MovePointUC implements the use case: step-by-step point movement
 type MovePointUC struct {
 	pointRepository point.PointRepository
+	eventBus        point.EventBus
 	logger          *zerolog.Logger
 	config          MovePointConfig
+	recorder        *moveRecorder // nil if recording is disabled
+
+	// Actors of the points with sessions or replays in progress
+	actors   map[int]*pointActor
+	actorsMu sync.Mutex
 }
 
 // NewMovePointUC creates a new use case for step-by-step point movement
+// eventBus receives a PointMovedEvent for every position change (nil disables events)
 func NewMovePointUC(
 	repository point.PointRepository,
+	eventBus point.EventBus,
 	logger *zerolog.Logger,
 	config MovePointConfig,
 ) *MovePointUC {
-	return &MovePointUC{
+	u := &MovePointUC{
 		pointRepository: repository,
+		eventBus:        eventBus,
 		logger:          logger,
 		config:          config,
+		actors:          make(map[int]*pointActor),
 	}
+	if config.HistorySize > 0 {
+		u.recorder = newMoveRecorder(config.HistorySize)
+	}
+	return u
 }
 
-// ClientSession represents a client session with a separate command channel
+// ClientSession represents a client session of a point
+// Its commands are applied by the point's actor together with those of the point's other sessions
 type ClientSession struct {
-	moveChan     chan MoveCommand
+	actor        *pointActor
 	positionChan chan *point.Point
+	errorChan    chan error
+	done         chan struct{} // closed when the session has left its point
+	paused       atomic.Bool
 }
 
 // PositionChan returns a channel for receiving position updates
+// Every session of a point receives every position of the point, whichever session moved it
 func (s *ClientSession) PositionChan() <-chan *point.Point {
 	return s.positionChan
 }
 
-// Init starts a goroutine to process point movement
-// Called once when WebSocket connection is activated
+// ErrorChan returns a channel for receiving processing errors
+// Only the first error of a series of consecutive failures is sent
+func (s *ClientSession) ErrorChan() <-chan error {
+	return s.errorChan
+}
+
+// Init joins a client session to the point's actor, starting one if the point has none
+// Called once when WebSocket connection is activated; the session leaves when ctx is done
 // Returns a client session with channels for commands and position updates
+//
+// All sessions of a point share its actor (see pointActor), which keeps the position in memory:
+// batches move it without repository round-trips, and it is saved every SaveInterval,
+// on teleport and when the last session leaves. Repositories implementing point.Mover
+// are moved directly instead, since their state may be shared with other instances.
 func (u *MovePointUC) Init(ctx context.Context, id int) *ClientSession {
-	// Create a separate command channel for this client
-	moveChan := make(chan MoveCommand, 50)
-	positionChan := make(chan *point.Point, 5)
-
+	actor := u.acquire(id)
 	session := &ClientSession{
-		moveChan:     moveChan,
-		positionChan: positionChan,
+		actor:        actor,
+		positionChan: make(chan *point.Point, 5),
+		errorChan:    make(chan error, 1),
+		done:         make(chan struct{}),
 	}
+	actor.control <- joinMessage{session: session}
 
-	go u.processMoves(ctx, id, session)
+	go func() {
+		<-ctx.Done()
+		u.release(actor, session)
+	}()
 	return session
 }
 
-// Push adds a move command to the client channel
+// Push adds a move command to the point's queue
+// Commands pushed after the session has left (e.g. the connection closed) are discarded
 func (s *ClientSession) Push(cmd MoveCommand) {
 	select {
-	case s.moveChan <- cmd:
+	case <-s.done:
+		return
 	default:
-		// Channel is full, ignore command
 	}
-}
 
-// processMoves processes move commands in an infinite loop
-// session - client session with channels for commands and position updates
-func (u *MovePointUC) processMoves(ctx context.Context, id int, session *ClientSession) {
-	ticker := time.NewTicker(u.config.SaveInterval)
-	defer ticker.Stop()
-	defer close(session.positionChan)
-	defer close(session.moveChan)
-
-	// Timer for batching commands
-	batchTicker := time.NewTicker(u.config.BatchInterval)
-	defer batchTicker.Stop()
-
-	var pendingCommands []MoveCommand
-	lastSentPos := &point.Point{X: -1, Y: -1} // For tracking changes
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case cmd := <-session.moveChan:
-			// Accumulate commands for batching
-			pendingCommands = append(pendingCommands, cmd)
-		case <-batchTicker.C:
-			// Process accumulated commands in batch
-			if len(pendingCommands) > 0 {
-				if err := u.processBatch(ctx, id, session, pendingCommands, lastSentPos); err != nil {
-					u.logger.Error().Err(err).Msg("Error processing batch")
-					pendingCommands = pendingCommands[:0]
-					continue
-				}
-				pendingCommands = pendingCommands[:0] // Clear slice
-			}
-		case <-ticker.C:
-			// Periodically save point position
-			if err := u.savePoint(ctx, id); err != nil {
-				u.logger.Error().Err(err).Msg("Error saving point")
-				continue
-			}
-		}
+	select {
+	case s.actor.moves <- sessionMove{session: s, cmd: cmd}:
+	default:
+		// Queue is full, ignore command
 	}
 }
 
-// processBatch processes a batch of move commands
-func (u *MovePointUC) processBatch(ctx context.Context, id int, session *ClientSession, commands []MoveCommand, lastSentPos *point.Point) error {
-	p, err := u.pointRepository.Get(ctx, id)
-	if err != nil {
-		return err
-	}
+// Pause stops applying move commands until Resume is called
+// Commands pushed after Pause returns are queued or dropped according to the PausePolicy
+func (s *ClientSession) Pause() {
+	s.control(PauseCommand{})
+}
 
-	oldX, oldY := p.X, p.Y
+// Resume continues applying move commands of a paused session
+func (s *ClientSession) Resume() {
+	s.control(ResumeCommand{})
+}
 
-	// Apply all commands sequentially
-	// Boundaries are checked inside Move method from domain level
-	for _, cmd := range commands {
-		p.Move(cmd.DX, cmd.DY)
-	}
-	commandCount := len(commands)
+// Teleport moves the point to absolute coordinates (clamped to its boundaries)
+// Unlike move commands it is applied immediately, without waiting for the batch tick,
+// and discards move commands of the point that are still pending.
+// Rejected with ErrPaused (see ErrorChan) while the session is paused.
+func (s *ClientSession) Teleport(cmd TeleportCommand) {
+	s.control(cmd)
+}
 
-	// Save updated position
-	if err := u.pointRepository.Save(ctx, id, p); err != nil {
-		return err
+// Paused reports whether the session is paused
+func (s *ClientSession) Paused() bool {
+	return s.paused.Load()
+}
+
+// control delivers a control command to the point's actor
+// Does nothing if the session has already left
+func (s *ClientSession) control(cmd any) {
+	select {
+	case s.actor.control <- sessionControl{session: s, cmd: cmd}:
+	case <-s.done:
 	}
+}
+
+// acquire returns the actor of a point, starting one if the point has none
+func (u *MovePointUC) acquire(id int) *pointActor {
+	u.actorsMu.Lock()
+	defer u.actorsMu.Unlock()
 
-	// Send update only if position changed
-	if p.X != lastSentPos.X || p.Y != lastSentPos.Y {
-		lastSentPos.X = p.X
-		lastSentPos.Y = p.Y
-
-		// Log point movement
-		u.logger.Debug().
-			Int("id", id).
-			Int("oldX", oldX).
-			Int("newX", p.X).
-			Int("oldY", oldY).
-			Int("newY", p.Y).
-			Int("commands", commandCount).
-			Msg("Point moved")
-
-		select {
-		case session.positionChan <- &point.Point{X: p.X, Y: p.Y}:
-		default:
-			// Channel is full, ignore
-		}
+	actor, exists := u.actors[id]
+	if !exists {
+		actor = newPointActor(u, id)
+		u.actors[id] = actor
+		go actor.run()
 	}
+	actor.holders++
+	return actor
+}
 
-	return nil
+// release leaves an actor acquired for a session (nil for a replay)
+// Returns once the actor has handled the leave, so the last member's changes are saved
+func (u *MovePointUC) release(actor *pointActor, session *ClientSession) {
+	u.actorsMu.Lock()
+	actor.holders--
+	u.actorsMu.Unlock()
+
+	left := make(chan struct{})
+	actor.control <- leaveMessage{session: session, left: left}
+	<-left
 }
 
-// savePoint saves the current point position
-func (u *MovePointUC) savePoint(ctx context.Context, id int) error {
-	p, err := u.pointRepository.Get(ctx, id)
-	if err != nil {
-		return err
+// retire removes an actor that nobody holds from the registry
+// Returns false if the actor was acquired again in the meantime
+func (u *MovePointUC) retire(actor *pointActor) bool {
+	u.actorsMu.Lock()
+	defer u.actorsMu.Unlock()
+
+	if actor.holders > 0 {
+		return false
 	}
+	delete(u.actors, actor.id)
+	return true
+}
 
-	if err := u.pointRepository.Save(ctx, id, p); err != nil {
-		return err
+// backoff returns the batch delay after the given number of consecutive failures
+// The delay doubles with every failure, starting from BatchInterval, up to MaxBackoff
+func (u *MovePointUC) backoff(failures int) time.Duration {
+	delay := u.config.BatchInterval
+	maxDelay := u.config.maxBackoff()
+	for i := 0; i < failures && delay < maxDelay; i++ {
+		delay *= 2
 	}
+	return min(delay, maxDelay)
+}
 
-	u.logger.Debug().
-		Int("id", id).
-		Int("x", p.X).
-		Int("y", p.Y).
-		Msg("Point saved successfully")
+// sendError notifies the client about a processing error
+func sendError(session *ClientSession, err error) {
+	select {
+	case session.errorChan <- err:
+	default:
+		// Client has not consumed the previous error yet, ignore
+	}
+}
+
+// publishMoved publishes a PointMovedEvent if an event bus is configured
+func (u *MovePointUC) publishMoved(id, oldX, oldY int, p *point.Point, commandCount int) {
+	if u.eventBus == nil {
+		return
+	}
+	u.eventBus.Publish(point.PointMovedEvent{
+		ID:           id,
+		OldX:         oldX,
+		OldY:         oldY,
+		NewX:         p.X,
+		NewY:         p.Y,
+		CommandCount: commandCount,
+		At:           time.Now(),
+	})
+}
 
-	return nil
+// coalesce sums the offsets of move commands
+func coalesce(commands []MoveCommand) point.Offset {
+	var offset point.Offset
+	for _, cmd := range commands {
+		offset.DX += cmd.DX
+		offset.DY += cmd.DY
+	}
+	return offset
 }
@@ -0,0 +1,845 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shngxx/point/internal/domain/point"
+	"github.com/shngxx/point/internal/infrastructure/db"
+	"github.com/shngxx/point/internal/infrastructure/events"
+	"github.com/shngxx/point/internal/usecase"
+	applog "github.com/shngxx/point/pkg/log"
+)
+
+// failingRepository is a point repository whose Get always fails
+type failingRepository struct {
+	gets atomic.Int32
+}
+
+func (r *failingRepository) Get(ctx context.Context, id int) (*point.Point, error) {
+	r.gets.Add(1)
+	return nil, errors.New("repository unavailable")
+}
+
+func (r *failingRepository) Save(ctx context.Context, id int, p *point.Point) error {
+	return nil
+}
+
+// moverRepository is a point repository that applies moves atomically and fails on Save
+type moverRepository struct {
+	*db.PointRepository
+	moves atomic.Int32
+}
+
+func (r *moverRepository) Save(ctx context.Context, id int, p *point.Point) error {
+	return errors.New("read-modify-write used instead of Move")
+}
+
+func (r *moverRepository) Move(ctx context.Context, id int, offsets []point.Offset) (before, after *point.Point, err error) {
+	r.moves.Add(1)
+	before, _ = r.PointRepository.Get(ctx, id)
+	after = &point.Point{X: before.X, Y: before.Y, MaxX: before.MaxX, MaxY: before.MaxY}
+	for _, offset := range offsets {
+		after.Move(offset.DX, offset.DY)
+	}
+	return before, after, r.PointRepository.Save(ctx, id, after)
+}
+
+// savingMover is a point repository that applies moves atomically and counts saves
+type savingMover struct {
+	moverRepository
+	saves atomic.Int32
+}
+
+func (r *savingMover) Save(ctx context.Context, id int, p *point.Point) error {
+	r.saves.Add(1)
+	return r.PointRepository.Save(ctx, id, p)
+}
+
+// countingRepository is an in-memory point repository that counts reads and writes
+type countingRepository struct {
+	*db.PointRepository
+	gets  atomic.Int32
+	saves atomic.Int32
+}
+
+func (r *countingRepository) Get(ctx context.Context, id int) (*point.Point, error) {
+	r.gets.Add(1)
+	return r.PointRepository.Get(ctx, id)
+}
+
+func (r *countingRepository) Save(ctx context.Context, id int, p *point.Point) error {
+	r.saves.Add(1)
+	return r.PointRepository.Save(ctx, id, p)
+}
+
+// unavailableSaveRepository is an in-memory point repository whose Save fails while unavailable is set
+type unavailableSaveRepository struct {
+	*db.PointRepository
+	unavailable atomic.Bool
+}
+
+func (r *unavailableSaveRepository) Save(ctx context.Context, id int, p *point.Point) error {
+	if r.unavailable.Load() {
+		return errors.New("repository unavailable")
+	}
+	return r.PointRepository.Save(ctx, id, p)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use by the logger
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestMovePointUC_BatchFailureBackoff tests that a persistently failing repository
+// is retried with exponential backoff and reported only once
+func TestMovePointUC_BatchFailureBackoff(t *testing.T) {
+	repo := &failingRepository{}
+	var logs syncBuffer
+	logger := zerolog.New(&logs).Level(zerolog.InfoLevel)
+
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+		MaxBackoff:    20 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := uc.Init(ctx, 1)
+
+	// Keep pushing commands for 200ms (~200 batch ticks without backoff)
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+		time.Sleep(500 * time.Microsecond)
+	}
+	cancel()
+
+	// With backoff capped at 20ms there are at most ~15 attempts in 200ms
+	if gets := repo.gets.Load(); gets == 0 || gets > 20 {
+		t.Errorf("repository Get called %d times, expected between 1 and 20", gets)
+	}
+
+	if n := strings.Count(logs.String(), "Error processing batch"); n != 1 {
+		t.Errorf("batch error logged %d times, expected 1", n)
+	}
+
+	// The client receives a single error
+	errCount := 0
+	for err := range session.ErrorChan() {
+		if err != nil {
+			errCount++
+		}
+	}
+	if errCount != 1 {
+		t.Errorf("client received %d errors, expected 1", errCount)
+	}
+}
+
+// TestMovePointUC_PauseResume tests that a paused session applies no movement
+// and commands received while paused are handled according to the pause policy on resume
+func TestMovePointUC_PauseResume(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   usecase.PausePolicy
+		expected int // X after resume and one fresh command
+	}{
+		{"queue", usecase.QueuePaused, point.DefaultX + 3},
+		{"drop", usecase.DropPaused, point.DefaultX + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := db.NewPointRepository()
+			logger := zerolog.Nop()
+			uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+				BatchInterval: time.Millisecond,
+				SaveInterval:  time.Hour,
+				PausePolicy:   tt.policy,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			session := uc.Init(ctx, 1)
+
+			session.Pause()
+			session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+			session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+
+			// Give the processor plenty of batch ticks
+			time.Sleep(20 * time.Millisecond)
+			if !session.Paused() {
+				t.Error("Paused() = false, expected true")
+			}
+			if p, _ := repo.Get(ctx, 1); p.X != point.DefaultX {
+				t.Fatalf("X while paused = %d, expected %d", p.X, point.DefaultX)
+			}
+
+			session.Resume()
+			session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+
+			// Queued commands may be applied in an earlier batch than the fresh one
+			timeout := time.After(time.Second)
+			for x := point.DefaultX; x != tt.expected; {
+				select {
+				case pos := <-session.PositionChan():
+					x = pos.X
+				case <-timeout:
+					t.Fatalf("X after resume = %d, expected %d", x, tt.expected)
+				}
+			}
+			if session.Paused() {
+				t.Error("Paused() = true after Resume, expected false")
+			}
+		})
+	}
+}
+
+// TestMovePointUC_Mover tests that repositories implementing point.Mover move points atomically
+func TestMovePointUC_Mover(t *testing.T) {
+	repo := &moverRepository{PointRepository: db.NewPointRepository()}
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := uc.Init(ctx, 1)
+	session.Push(usecase.MoveCommand{ID: 1, DX: 2, DY: 1})
+
+	select {
+	case pos := <-session.PositionChan():
+		if pos.X != point.DefaultX+2 || pos.Y != point.DefaultY+1 {
+			t.Errorf("Position = %+v, expected the moved point", pos)
+		}
+	case err := <-session.ErrorChan():
+		t.Fatalf("Unexpected batch error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("No position update received")
+	}
+	if repo.moves.Load() == 0 {
+		t.Error("Expected Move to be used")
+	}
+}
+
+// TestMovePointUC_Coalesce tests that a batch moves the point by the sum of its commands:
+// in bounds this matches sequential application, and a wall reached mid-batch does not
+// swallow the movement away from it
+func TestMovePointUC_Coalesce(t *testing.T) {
+	tests := []struct {
+		name       string
+		commands   [][2]int
+		expected   [2]int
+		sequential bool // whether sequential application ends at the same position
+	}{
+		{"in bounds", [][2]int{{10, 5}, {-3, 2}, {1, -1}}, [2]int{point.DefaultX + 8, point.DefaultY + 6}, true},
+		// Sequentially the first command clamps at the right wall and the second moves away from it
+		{"wall", [][2]int{{point.DefaultMaxX, 0}, {-100, 0}}, [2]int{point.DefaultMaxX - 1, point.DefaultY}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Sequential application for comparison
+			sequential := point.NewPoint(0, 0, 0, 0)
+			for _, cmd := range tt.commands {
+				sequential.Move(cmd[0], cmd[1])
+			}
+
+			repo := db.NewPointRepository()
+			logger := zerolog.Nop()
+			uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+				BatchInterval: 50 * time.Millisecond,
+				SaveInterval:  time.Hour,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			session := uc.Init(ctx, 1)
+			for _, cmd := range tt.commands {
+				session.Push(usecase.MoveCommand{ID: 1, DX: cmd[0], DY: cmd[1]})
+			}
+
+			select {
+			case pos := <-session.PositionChan():
+				if pos.X != tt.expected[0] || pos.Y != tt.expected[1] {
+					t.Errorf("Position = {X:%d Y:%d}, expected {X:%d Y:%d} (sequential {X:%d Y:%d})",
+						pos.X, pos.Y, tt.expected[0], tt.expected[1], sequential.X, sequential.Y)
+				}
+				if same := sequential.X == tt.expected[0] && sequential.Y == tt.expected[1]; same != tt.sequential {
+					t.Errorf("sequential {X:%d Y:%d} matches coalesced = %v, expected %v", sequential.X, sequential.Y, same, tt.sequential)
+				}
+			case err := <-session.ErrorChan():
+				t.Fatalf("Unexpected batch error: %v", err)
+			case <-time.After(time.Second):
+				t.Fatal("No position update received")
+			}
+		})
+	}
+}
+
+// TestMovePointUC_Teleport tests that teleporting out of bounds clamps the point
+// and the position is pushed without waiting for the batch tick
+func TestMovePointUC_Teleport(t *testing.T) {
+	tests := []struct {
+		name      string
+		x, y      int
+		expectedX int
+		expectedY int
+	}{
+		{"inside", 10, 20, 10, 20},
+		{"beyond max", point.DefaultMaxX + 100, point.DefaultMaxY, point.DefaultMaxX - 1, point.DefaultMaxY - 1},
+		{"negative", -5, -1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := db.NewPointRepository()
+			logger := zerolog.Nop()
+			uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+				BatchInterval: time.Hour,
+				SaveInterval:  time.Hour,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			session := uc.Init(ctx, 1)
+			session.Teleport(usecase.TeleportCommand{ID: 1, X: tt.x, Y: tt.y})
+
+			select {
+			case pos := <-session.PositionChan():
+				if pos.X != tt.expectedX || pos.Y != tt.expectedY {
+					t.Errorf("Position = (%d, %d), expected (%d, %d)", pos.X, pos.Y, tt.expectedX, tt.expectedY)
+				}
+			case err := <-session.ErrorChan():
+				t.Fatalf("Unexpected teleport error: %v", err)
+			case <-time.After(time.Second):
+				t.Fatal("No position update received")
+			}
+
+			if p, _ := repo.Get(ctx, 1); p.X != tt.expectedX || p.Y != tt.expectedY {
+				t.Errorf("Saved point = (%d, %d), expected (%d, %d)", p.X, p.Y, tt.expectedX, tt.expectedY)
+			}
+		})
+	}
+}
+
+// TestMovePointUC_TeleportSaveFailure tests that a teleport whose save failed
+// is saved when the last session leaves
+func TestMovePointUC_TeleportSaveFailure(t *testing.T) {
+	repo := &unavailableSaveRepository{PointRepository: db.NewPointRepository()}
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Hour,
+		SaveInterval:  time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := uc.Init(ctx, 1)
+	repo.unavailable.Store(true)
+	session.Teleport(usecase.TeleportCommand{ID: 1, X: 10, Y: 20})
+
+	select {
+	case err := <-session.ErrorChan():
+		if err == nil {
+			t.Fatal("Teleport error = nil, expected the save error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No teleport error received")
+	}
+
+	repo.unavailable.Store(false)
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		p, _ := repo.Get(context.Background(), 1)
+		if p.X == 10 && p.Y == 20 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Saved point = (%d, %d), expected (10, 20)", p.X, p.Y)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestMovePointUC_TeleportPaused tests that a teleport of a paused session is rejected with ErrPaused
+func TestMovePointUC_TeleportPaused(t *testing.T) {
+	repo := db.NewPointRepository()
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Hour,
+		SaveInterval:  time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := uc.Init(ctx, 1)
+	session.Pause()
+	session.Teleport(usecase.TeleportCommand{ID: 1, X: 10, Y: 20})
+
+	select {
+	case err := <-session.ErrorChan():
+		if !errors.Is(err, usecase.ErrPaused) {
+			t.Errorf("Teleport error = %v, expected %v", err, usecase.ErrPaused)
+		}
+	case pos := <-session.PositionChan():
+		t.Fatalf("Paused session teleported to %+v", pos)
+	case <-time.After(time.Second):
+		t.Fatal("No teleport error received")
+	}
+
+	if p, _ := repo.Get(ctx, 1); p.X != point.DefaultX || p.Y != point.DefaultY {
+		t.Errorf("Saved point = (%d, %d), expected the default position", p.X, p.Y)
+	}
+}
+
+// TestMovePointUC_Events tests that every position change publishes a PointMovedEvent
+func TestMovePointUC_Events(t *testing.T) {
+	repo := db.NewPointRepository()
+	bus := events.NewMemoryEventBus()
+	moved, unsubscribe := bus.Subscribe(10)
+	defer unsubscribe()
+
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, bus, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := uc.Init(ctx, 1)
+
+	steps := []struct {
+		cmd      usecase.MoveCommand
+		expected *point.PointMovedEvent // nil if the position does not change
+	}{
+		{usecase.MoveCommand{ID: 1, DX: 1}, &point.PointMovedEvent{ID: 1, OldX: point.DefaultX, OldY: point.DefaultY, NewX: point.DefaultX + 1, NewY: point.DefaultY, CommandCount: 1}},
+		{usecase.MoveCommand{ID: 1, DY: -2}, &point.PointMovedEvent{ID: 1, OldX: point.DefaultX + 1, OldY: point.DefaultY, NewX: point.DefaultX + 1, NewY: point.DefaultY - 2, CommandCount: 1}},
+		{usecase.MoveCommand{ID: 1, DX: -1000}, &point.PointMovedEvent{ID: 1, OldX: point.DefaultX + 1, OldY: point.DefaultY - 2, NewX: 0, NewY: point.DefaultY - 2, CommandCount: 1}},
+		{usecase.MoveCommand{ID: 1, DX: -1}, nil}, // Blocked by the boundary
+	}
+
+	for i, step := range steps {
+		session.Push(step.cmd)
+
+		if step.expected == nil {
+			select {
+			case evt := <-moved:
+				t.Fatalf("Step %d: unexpected event %+v", i, evt)
+			case <-time.After(20 * time.Millisecond):
+			}
+			continue
+		}
+
+		select {
+		case evt := <-moved:
+			if evt.At.IsZero() {
+				t.Errorf("Step %d: event time is not set", i)
+			}
+			evt.At = time.Time{}
+			if evt != *step.expected {
+				t.Errorf("Step %d: event = %+v, expected %+v", i, evt, *step.expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Step %d: no event received", i)
+		}
+	}
+}
+
+// TestMovePointUC_Physics tests that in physics mode a move sets a velocity that is integrated
+// every tick, slowed down by friction and stopped by a wall
+func TestMovePointUC_Physics(t *testing.T) {
+	tests := []struct {
+		name     string
+		friction float64
+		dx       int
+		expected []int // X after each tick until the point stops
+	}{
+		// 410x600 boundaries: the point starts at X=400 and hits the wall at X=409
+		{"constant velocity until the wall", 0, 4, []int{404, 408, 409}},
+		{"friction until the wall", 0.5, 8, []int{408, 409}},
+		{"friction stops the point before the wall", 0.5, 4, []int{404, 406, 407}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := db.NewPointRepositoryWithConfig(410, 600)
+			logger := zerolog.Nop()
+			uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+				BatchInterval:  time.Millisecond,
+				SaveInterval:   time.Hour,
+				PhysicsEnabled: true,
+				Friction:       tt.friction,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			session := uc.Init(ctx, 1)
+			session.Push(usecase.MoveCommand{ID: 1, DX: tt.dx})
+
+			for i, expected := range tt.expected {
+				select {
+				case pos := <-session.PositionChan():
+					if pos.X != expected || pos.Y != point.DefaultY {
+						t.Fatalf("Tick %d: position = (%d, %d), expected (%d, %d)", i+1, pos.X, pos.Y, expected, point.DefaultY)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("Tick %d: no position update received", i+1)
+				}
+			}
+
+			// The point has stopped
+			select {
+			case pos := <-session.PositionChan():
+				t.Fatalf("Unexpected position (%d, %d) after the point stopped", pos.X, pos.Y)
+			case <-time.After(30 * time.Millisecond):
+			}
+		})
+	}
+}
+
+// TestMovePointUC_SaveOnlyChanges tests that the point is saved only when its position changed
+// and unsaved changes are saved when processing stops, and points of point.Mover repositories are never saved
+func TestMovePointUC_SaveOnlyChanges(t *testing.T) {
+	repo := &countingRepository{PointRepository: db.NewPointRepository()}
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  5 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := uc.Init(ctx, 1)
+
+	// Nothing changed, nothing to save
+	time.Sleep(20 * time.Millisecond)
+	if saves := repo.saves.Load(); saves != 0 {
+		t.Fatalf("Saves without changes = %d, expected 0", saves)
+	}
+
+	// One change is saved once, however many save ticks pass
+	session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+	<-session.PositionChan()
+	time.Sleep(30 * time.Millisecond)
+	if saves := repo.saves.Load(); saves != 1 {
+		t.Fatalf("Saves after one change = %d, expected 1", saves)
+	}
+
+	// A change made right before stopping is saved on shutdown
+	repo = &countingRepository{PointRepository: db.NewPointRepository()}
+	uc = usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+	})
+	stopCtx, stop := context.WithCancel(context.Background())
+	session = uc.Init(stopCtx, 1)
+	session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+	<-session.PositionChan()
+	stop()
+	for range session.PositionChan() {
+		// Wait for processing to stop
+	}
+	if saves := repo.saves.Load(); saves != 1 {
+		t.Errorf("Saves on shutdown = %d, expected 1", saves)
+	}
+
+	// Moves of point.Mover repositories are already persisted: saving them again
+	// would overwrite the moves of other instances
+	mover := &savingMover{moverRepository: moverRepository{PointRepository: db.NewPointRepository()}}
+	uc = usecase.NewMovePointUC(mover, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  5 * time.Millisecond,
+	})
+	moverCtx, stopMover := context.WithCancel(context.Background())
+	session = uc.Init(moverCtx, 1)
+	session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+	<-session.PositionChan()
+	time.Sleep(20 * time.Millisecond)
+	stopMover()
+	for range session.PositionChan() {
+		// Wait for processing to stop
+	}
+	if saves := mover.saves.Load(); saves != 0 || mover.moves.Load() != 1 {
+		t.Errorf("Mover saves = %d, moves = %d, expected 0 saves and 1 move", saves, mover.moves.Load())
+	}
+}
+
+// TestMovePointUC_ActorLogger tests that the point's actor, shared by all its sessions,
+// logs with the use case logger and the point ID rather than the request-scoped logger
+// of the session that started it
+func TestMovePointUC_ActorLogger(t *testing.T) {
+	var baseLogs, requestLogs syncBuffer
+	base := zerolog.New(&baseLogs)
+	requestLogger := zerolog.New(&requestLogs).With().Str("request_id", "req-42").Logger()
+
+	uc := usecase.NewMovePointUC(db.NewPointRepository(), nil, &base, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(applog.WithContext(context.Background(), &requestLogger))
+	defer cancel()
+	session := uc.Init(ctx, 1)
+
+	session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+	select {
+	case <-session.PositionChan():
+	case <-time.After(time.Second):
+		t.Fatal("No position received")
+	}
+	cancel()
+	for range session.ErrorChan() {
+	}
+
+	if !strings.Contains(baseLogs.String(), `"id":1`) || !strings.Contains(baseLogs.String(), "Point moved") {
+		t.Errorf("Base logs = %q, expected the move with the point ID", baseLogs.String())
+	}
+	if strings.Contains(baseLogs.String(), "req-42") || requestLogs.String() != "" {
+		t.Errorf("Request logs = %q, expected no actor logs with the request logger", requestLogs.String())
+	}
+}
+
+// TestMovePointUC_Replay tests recording applied moves and replaying them to the same position
+func TestMovePointUC_Replay(t *testing.T) {
+	repo := db.NewPointRepositoryWithConfig(100, 100)
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+		HistorySize:   3,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := repo.Save(ctx, 1, &point.Point{X: 50, Y: 50, MaxX: 100, MaxY: 100}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	session := uc.Init(ctx, 1)
+
+	// One move per batch, 20ms apart; the first two fall out of the 3-move history
+	moves := []usecase.MoveCommand{{ID: 1, DX: 9}, {ID: 1, DY: 9}, {ID: 1, DX: 5}, {ID: 1, DX: 60}, {ID: 1, DY: -3}}
+	for _, move := range moves {
+		session.Push(move)
+		select {
+		case <-session.PositionChan():
+		case <-time.After(time.Second):
+			t.Fatal("No position received")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	for range session.ErrorChan() {
+	}
+
+	history := uc.GetHistory(1)
+	if len(history) != 3 || history[0].DX != 5 || history[1].DX != 60 || history[2].DY != -3 {
+		t.Fatalf("History = %+v, expected the last 3 moves", history)
+	}
+	recorded, _ := repo.Get(context.Background(), 1)
+
+	// Replay from the position before the recorded moves
+	if err := repo.Save(context.Background(), 1, &point.Point{X: 59, Y: 59, MaxX: 100, MaxY: 100}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	start := time.Now()
+	if err := uc.Replay(context.Background(), 1, history, 2); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	elapsed := time.Since(start)
+
+	replayed, _ := repo.Get(context.Background(), 1)
+	if *replayed != *recorded {
+		t.Errorf("Replayed position = %+v, expected %+v", *replayed, *recorded)
+	}
+	// The original moves were >= 40ms apart in total, replayed at twice the speed
+	if original := history[2].At.Sub(history[0].At); elapsed < original/2 || elapsed > original {
+		t.Errorf("Replay took %v, expected about %v", elapsed, original/2)
+	}
+
+	if err := uc.Replay(context.Background(), 1, history, 0); err == nil {
+		t.Error("Replay() with speed 0 should return an error")
+	}
+	if uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{}); uc.GetHistory(1) != nil {
+		t.Error("GetHistory() without HistorySize should return nil")
+	}
+}
+
+// TestMovePointUC_PushAfterCancel tests that pushing to a session whose processing
+// has stopped discards the commands instead of panicking
+func TestMovePointUC_PushAfterCancel(t *testing.T) {
+	repo := db.NewPointRepository()
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := uc.Init(ctx, 1)
+
+	// Pushes racing with the shutdown
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+			}
+		}()
+	}
+	cancel()
+	wg.Wait()
+
+	// Processing has stopped once the position channel is closed
+	for range session.PositionChan() {
+	}
+	session.Push(usecase.MoveCommand{ID: 1, DX: 1})
+}
+
+// TestMovePointUC_SaveInterval tests that batches move the point in memory: sessions of a point
+// share its position, which is read once and saved about once per save interval, not per batch
+func TestMovePointUC_SaveInterval(t *testing.T) {
+	repo := &countingRepository{PointRepository: db.NewPointRepository()}
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sessions := []*usecase.ClientSession{uc.Init(ctx, 1), uc.Init(ctx, 1)}
+
+	// Keep both sessions moving the point for 200ms (~200 batch ticks, 4 save ticks)
+	pushes := 0
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		sessions[pushes%2].Push(usecase.MoveCommand{ID: 1, DX: 1})
+		pushes++
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for _, session := range sessions {
+		for range session.PositionChan() {
+			// Wait for processing to stop
+		}
+	}
+
+	if gets := repo.gets.Load(); gets != 1 {
+		t.Errorf("repository Get called %d times, expected 1", gets)
+	}
+	// One save per save tick plus one when the sessions stop
+	if saves := repo.saves.Load(); saves == 0 || saves > 6 {
+		t.Errorf("repository Save called %d times for %d moves, expected 1 to 6", saves, pushes)
+	}
+
+	// Both sessions moved the same point, and its final position was saved
+	expectedX := min(point.DefaultX+pushes, point.DefaultMaxX-1)
+	if p, _ := repo.PointRepository.Get(context.Background(), 1); p.X != expectedX {
+		t.Errorf("saved X = %d, expected %d", p.X, expectedX)
+	}
+}
+
+// TestMovePointUC_ConcurrentSessions tests that sessions moving the same point concurrently
+// are serialized by the point's actor: no move is lost and every session sees the other's moves
+func TestMovePointUC_ConcurrentSessions(t *testing.T) {
+	repo := db.NewPointRepositoryWithConfig(1000, 1000)
+	logger := zerolog.Nop()
+	uc := usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+		BatchInterval: time.Millisecond,
+		SaveInterval:  time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	horizontal, vertical := uc.Init(ctx, 1), uc.Init(ctx, 1)
+
+	// Record the last position each session receives
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	last := make([]point.Point, 2)
+	for i, session := range []*usecase.ClientSession{horizontal, vertical} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pos := range session.PositionChan() {
+				mu.Lock()
+				last[i] = *pos
+				mu.Unlock()
+			}
+		}()
+	}
+
+	const moves = 100
+	var pushers sync.WaitGroup
+	for _, push := range []func(){
+		func() { horizontal.Push(usecase.MoveCommand{ID: 1, DX: 1}) },
+		func() { vertical.Push(usecase.MoveCommand{ID: 1, DY: 1}) },
+	} {
+		pushers.Add(1)
+		go func() {
+			defer pushers.Done()
+			for range moves {
+				push()
+			}
+		}()
+	}
+	pushers.Wait()
+
+	expected := point.Point{X: point.DefaultX + moves, Y: point.DefaultY + moves, MaxX: 1000, MaxY: 1000}
+	final := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, pos := range last {
+			if pos.X != expected.X || pos.Y != expected.Y {
+				return false
+			}
+		}
+		return true
+	}
+	for deadline := time.Now().Add(time.Second); !final() && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+
+	// Both sessions received the final position, including the other session's moves
+	for i, pos := range last {
+		if pos.X != expected.X || pos.Y != expected.Y {
+			t.Errorf("session %d last position = (%d, %d), expected (%d, %d)", i, pos.X, pos.Y, expected.X, expected.Y)
+		}
+	}
+	// The position saved when the sessions left includes every move
+	if p, _ := repo.Get(context.Background(), 1); *p != expected {
+		t.Errorf("saved point = %+v, expected %+v", *p, expected)
+	}
+}
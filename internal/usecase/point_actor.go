@@ -0,0 +1,462 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shngxx/point/internal/domain/point"
+)
+
+// actorMoveBuffer is the number of move commands queued for a point
+// Commands pushed while the queue is full are dropped
+const actorMoveBuffer = 256
+
+// repositoryTimeout limits each repository call of an actor,
+// so a hung repository cannot block the actor and the sessions waiting on it
+const repositoryTimeout = 5 * time.Second
+
+// pointActor owns a point while it has sessions: its goroutine is the only one changing the point.
+// It applies the commands of all sessions of the point in order and sends the resulting positions
+// to each of them, so concurrent sessions never overwrite each other's changes.
+type pointActor struct {
+	uc      *MovePointUC
+	id      int
+	logger  zerolog.Logger   // Use case logger with the point ID
+	moves   chan sessionMove // Move commands of all sessions
+	control chan any         // joinMessage, leaveMessage, sessionControl or applyMessage
+	holders int              // Sessions and replays holding the actor, guarded by MovePointUC.actorsMu
+
+	// State owned by the actor goroutine
+	sessions    map[*ClientSession]*sessionState
+	members     int          // Joined sessions and replays
+	point       *point.Point // Cached point, nil until loaded (unused for point.Mover)
+	lastSentPos point.Point  // For tracking changes
+	dirty       bool         // Whether the cached position changed since the last save
+	failures    int          // Consecutive batch failures, used for exponential backoff
+}
+
+// sessionState is the state of a session kept by its point's actor
+type sessionState struct {
+	pending []MoveCommand
+	motion  velocity // Used in physics mode
+}
+
+// sessionMove is a move command pushed by a session
+type sessionMove struct {
+	session *ClientSession
+	cmd     MoveCommand
+}
+
+// sessionControl is a PauseCommand, ResumeCommand or TeleportCommand of a session
+type sessionControl struct {
+	session *ClientSession
+	cmd     any
+}
+
+// joinMessage adds a session (nil for a replay) to the actor
+type joinMessage struct {
+	session *ClientSession
+}
+
+// leaveMessage removes a session (nil for a replay) from the actor
+// left is closed once the member has left (and its changes are saved if it was the last one)
+type leaveMessage struct {
+	session *ClientSession
+	left    chan struct{}
+}
+
+// applyMessage applies move commands outside of a session (see Replay)
+type applyMessage struct {
+	commands []MoveCommand
+	reply    chan applyResult
+}
+
+// applyResult is the outcome of an applyMessage
+type applyResult struct {
+	oldX, oldY int
+	point      *point.Point
+	err        error
+}
+
+// newPointActor creates the actor of a point; run starts it
+// It is shared by the sessions of the point, so it logs through the use case logger
+// rather than the request-scoped logger of any of them
+func newPointActor(u *MovePointUC, id int) *pointActor {
+	return &pointActor{
+		uc:          u,
+		id:          id,
+		logger:      u.logger.With().Int("id", id).Logger(),
+		moves:       make(chan sessionMove, actorMoveBuffer),
+		control:     make(chan any),
+		sessions:    make(map[*ClientSession]*sessionState),
+		lastSentPos: point.Point{X: -1, Y: -1},
+	}
+}
+
+// run processes the commands of the point's sessions until the last member leaves
+// moves is never closed: Push may race with the actor stopping and must not send on a closed channel
+func (a *pointActor) run() {
+	u := a.uc
+	saveTicker := time.NewTicker(u.config.SaveInterval)
+	defer saveTicker.Stop()
+
+	// Timer for batching commands
+	batchTicker := time.NewTicker(u.config.BatchInterval)
+	defer batchTicker.Stop()
+
+	for {
+		select {
+		case msg := <-a.control:
+			switch msg := msg.(type) {
+			case joinMessage:
+				a.members++
+				if msg.session != nil {
+					a.sessions[msg.session] = &sessionState{}
+				}
+			case leaveMessage:
+				stopped := a.leave(msg.session)
+				close(msg.left)
+				if stopped {
+					return
+				}
+			case sessionControl:
+				a.handleControl(msg.session, msg.cmd)
+			case applyMessage:
+				oldX, oldY, p, err := a.applyCommands(msg.commands)
+				if err == nil && (p.X != oldX || p.Y != oldY) {
+					a.markDirty()
+					a.broadcast(p)
+				}
+				msg.reply <- applyResult{oldX: oldX, oldY: oldY, point: p, err: err}
+			}
+		case move := <-a.moves:
+			state, ok := a.sessions[move.session]
+			if !ok {
+				// The session has left
+				continue
+			}
+			if move.session.paused.Load() &&
+				(u.config.PausePolicy == DropPaused || len(state.pending) >= maxPausedCommands) {
+				continue
+			}
+			// Accumulate commands for batching
+			state.pending = append(state.pending, move.cmd)
+		case <-batchTicker.C:
+			a.batch(batchTicker)
+		case <-saveTicker.C:
+			// Periodically save point position if it changed
+			if !a.dirty {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), repositoryTimeout)
+			err := a.save(ctx)
+			cancel()
+			if err != nil {
+				a.logger.Error().Err(err).Msg("Error saving point")
+				continue
+			}
+			a.dirty = false
+		}
+	}
+}
+
+// leave removes a member from the actor and reports whether the actor has stopped
+// With the last member gone the unsaved changes are saved and the actor stops,
+// unless the point was acquired again in the meantime
+func (a *pointActor) leave(session *ClientSession) bool {
+	a.members--
+	delete(a.sessions, session)
+
+	stopped := false
+	if a.members == 0 {
+		// Persist the last changes before stopping
+		if a.dirty {
+			saveCtx, cancel := context.WithTimeout(context.Background(), finalSaveTimeout)
+			if err := a.save(saveCtx); err != nil {
+				a.logger.Error().Err(err).Msg("Error saving point on shutdown")
+			} else {
+				a.dirty = false
+			}
+			cancel()
+		}
+		stopped = a.uc.retire(a)
+	}
+
+	// The channels are closed once the point is saved, so a closed session observes the saved state
+	if session != nil {
+		close(session.done)
+		close(session.positionChan)
+		close(session.errorChan)
+	}
+	return stopped
+}
+
+// handleControl applies a control command of a session
+func (a *pointActor) handleControl(session *ClientSession, cmd any) {
+	if _, ok := a.sessions[session]; !ok {
+		return
+	}
+
+	switch cmd := cmd.(type) {
+	case PauseCommand:
+		if !session.paused.Swap(true) {
+			a.logger.Info().Msg("Point movement paused")
+		}
+	case ResumeCommand:
+		if session.paused.Swap(false) {
+			a.logger.Info().Msg("Point movement resumed")
+		}
+	case TeleportCommand:
+		if session.paused.Load() {
+			sendError(session, ErrPaused)
+			return
+		}
+		if err := a.teleport(cmd); err != nil {
+			a.logger.Error().Err(err).Msg("Error teleporting point")
+			sendError(session, err)
+		}
+	}
+}
+
+// batch applies the pending commands of the sessions that are not paused as a single batch
+// (paused sessions keep their queued commands until they resume)
+func (a *pointActor) batch(batchTicker *time.Ticker) {
+	u := a.uc
+	var commands []MoveCommand
+	for session, state := range a.sessions {
+		if session.paused.Load() {
+			continue
+		}
+		if u.config.PhysicsEnabled {
+			// Commands set the velocity, the batch moves the point by one tick of it
+			commands = append(commands, state.motion.tick(a.id, state.pending, u.config.friction())...)
+		} else {
+			commands = append(commands, state.pending...)
+		}
+		state.pending = state.pending[:0] // Clear slice
+	}
+	if len(commands) == 0 {
+		return
+	}
+
+	p, err := a.processBatch(commands)
+	if err != nil {
+		a.failures++
+		delay := u.backoff(a.failures)
+		a.reportBatchError(err, delay)
+		// Pause batch processing with increasing delay
+		batchTicker.Reset(delay)
+		return
+	}
+	if a.failures > 0 {
+		a.logger.Info().Int("failures", a.failures).Msg("Batch processing recovered")
+		a.failures = 0
+		batchTicker.Reset(u.config.BatchInterval)
+	}
+	if u.config.PhysicsEnabled {
+		for _, state := range a.sessions {
+			state.motion.stopAtWalls(p)
+		}
+	}
+}
+
+// reportBatchError logs a batch failure and notifies the clients
+// Only the first failure of a series is logged at error level and sent to the clients,
+// subsequent ones are logged at debug level to avoid flooding logs
+func (a *pointActor) reportBatchError(err error, delay time.Duration) {
+	if a.failures > 1 {
+		a.logger.Debug().
+			Err(err).
+			Int("failures", a.failures).
+			Dur("retryIn", delay).
+			Msg("Error processing batch")
+		return
+	}
+
+	a.logger.Error().Err(err).Dur("retryIn", delay).Msg("Error processing batch")
+	for session := range a.sessions {
+		sendError(session, err)
+	}
+}
+
+// processBatch applies a batch of move commands and sends the new position to the sessions
+// Returns the moved point
+func (a *pointActor) processBatch(commands []MoveCommand) (*point.Point, error) {
+	u := a.uc
+	oldX, oldY, p, err := a.applyCommands(commands)
+	if err != nil {
+		return nil, err
+	}
+	commandCount := len(commands)
+	if u.recorder != nil {
+		u.recorder.record(a.id, commands, time.Now())
+	}
+
+	if p.X != oldX || p.Y != oldY {
+		a.markDirty()
+		u.publishMoved(a.id, oldX, oldY, p, commandCount)
+	}
+
+	// Send update only if position changed
+	if p.X != a.lastSentPos.X || p.Y != a.lastSentPos.Y {
+		// Log point movement
+		a.logger.Debug().
+			Int("oldX", oldX).
+			Int("newX", p.X).
+			Int("oldY", oldY).
+			Int("newY", p.Y).
+			Int("commands", commandCount).
+			Msg("Point moved")
+
+		a.broadcast(p)
+	}
+
+	return p, nil
+}
+
+// teleport moves the point to absolute coordinates, saves it and immediately sends the new position
+func (a *pointActor) teleport(cmd TeleportCommand) error {
+	// Pending moves are relative to the position before the teleport
+	for _, state := range a.sessions {
+		state.pending = state.pending[:0]
+		state.motion = velocity{}
+	}
+
+	oldX, oldY, p, err := a.change(func(p *point.Point) {
+		p.Teleport(cmd.X, cmd.Y)
+	})
+	if err != nil {
+		return err
+	}
+	if a.point != nil {
+		// Teleports of a cached point are saved right away rather than on the save interval;
+		// the point stays dirty until then, so a failed save is retried by the next one
+		a.markDirty()
+		ctx, cancel := context.WithTimeout(context.Background(), repositoryTimeout)
+		err := a.save(ctx)
+		cancel()
+		if err != nil {
+			return err
+		}
+		a.dirty = false
+	}
+
+	a.logger.Debug().
+		Int("oldX", oldX).
+		Int("newX", p.X).
+		Int("oldY", oldY).
+		Int("newY", p.Y).
+		Msg("Point teleported")
+	a.uc.publishMoved(a.id, oldX, oldY, p, 1)
+
+	a.broadcast(p)
+	return nil
+}
+
+// broadcast sends a position to every session of the point
+func (a *pointActor) broadcast(p *point.Point) {
+	a.lastSentPos = point.Point{X: p.X, Y: p.Y}
+	for session := range a.sessions {
+		select {
+		case session.positionChan <- &point.Point{X: p.X, Y: p.Y}:
+		default:
+			// Channel is full, ignore
+		}
+	}
+}
+
+// apply applies move commands outside of a session and returns the old coordinates and the moved point
+func (a *pointActor) apply(commands []MoveCommand) (oldX, oldY int, p *point.Point, err error) {
+	reply := make(chan applyResult, 1)
+	a.control <- applyMessage{commands: commands, reply: reply}
+	result := <-reply
+	return result.oldX, result.oldY, result.point, result.err
+}
+
+// applyCommands applies move commands to the point and returns the old coordinates and the moved point
+// The commands are coalesced into a single offset clamped once, so a point pushed against a wall
+// mid-batch keeps the rest of the batch's movement
+// Repositories implementing point.Mover apply it atomically, others through change
+func (a *pointActor) applyCommands(commands []MoveCommand) (oldX, oldY int, p *point.Point, err error) {
+	offset := coalesce(commands)
+
+	if mover, ok := a.uc.pointRepository.(point.Mover); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), repositoryTimeout)
+		defer cancel()
+		before, after, err := mover.Move(ctx, a.id, []point.Offset{offset})
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		return before.X, before.Y, after, nil
+	}
+
+	// Boundaries are checked inside Move method from domain level
+	return a.change(func(p *point.Point) {
+		p.Move(offset.DX, offset.DY)
+	})
+}
+
+// change applies fn to the point and returns the old coordinates and a copy of the changed point
+// The point is loaded on first use and changed in memory (see save); points of repositories
+// implementing point.Mover are read and written right away instead
+func (a *pointActor) change(fn func(p *point.Point)) (oldX, oldY int, p *point.Point, err error) {
+	repository := a.uc.pointRepository
+	ctx, cancel := context.WithTimeout(context.Background(), repositoryTimeout)
+	defer cancel()
+
+	if _, ok := repository.(point.Mover); ok {
+		p, err := repository.Get(ctx, a.id)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		oldX, oldY = p.X, p.Y
+		fn(p)
+		if err := repository.Save(ctx, a.id, p); err != nil {
+			return 0, 0, nil, err
+		}
+		return oldX, oldY, p, nil
+	}
+
+	if a.point == nil {
+		loaded, err := repository.Get(ctx, a.id)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		a.point = loaded
+	}
+
+	oldX, oldY = a.point.X, a.point.Y
+	fn(a.point)
+	changed := *a.point
+	return oldX, oldY, &changed, nil
+}
+
+// markDirty marks the cached position as changed since the last save
+// Points of repositories implementing point.Mover are never cached: their moves are already
+// persisted, and saving them again would overwrite moves made by other instances
+func (a *pointActor) markDirty() {
+	if a.point != nil {
+		a.dirty = true
+	}
+}
+
+// save saves the cached point position
+// Does nothing if the point is not cached (see markDirty)
+func (a *pointActor) save(ctx context.Context) error {
+	if a.point == nil {
+		return nil
+	}
+	p := *a.point
+
+	if err := a.uc.pointRepository.Save(ctx, a.id, &p); err != nil {
+		return err
+	}
+
+	a.logger.Debug().
+		Int("x", p.X).
+		Int("y", p.Y).
+		Msg("Point saved successfully")
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"math"
+
+	"github.com/shngxx/point/internal/domain/point"
+)
+
+// minSpeed is the speed (cells per tick) below which a moving point stops
+const minSpeed = 0.01
+
+// velocity is the motion state of a point in physics mode
+type velocity struct {
+	x, y       float64 // Cells per batch tick
+	remX, remY float64 // Fractional movement not applied yet
+}
+
+// tick updates the velocity from move commands and returns the move for the current tick
+// The sum of the commands becomes the new velocity; without commands the point keeps moving.
+// Friction is applied after the move, so it slows down the next tick.
+func (v *velocity) tick(id int, commands []MoveCommand, friction float64) []MoveCommand {
+	if len(commands) > 0 {
+		v.x, v.y = 0, 0
+		for _, cmd := range commands {
+			v.x += float64(cmd.DX)
+			v.y += float64(cmd.DY)
+		}
+	}
+	if v.x == 0 && v.y == 0 {
+		return nil
+	}
+
+	// Whole cells are applied now, the remainder is carried over to the next ticks
+	dx, remX := math.Modf(v.x + v.remX)
+	dy, remY := math.Modf(v.y + v.remY)
+	v.remX, v.remY = remX, remY
+
+	v.x = slow(v.x, friction)
+	v.y = slow(v.y, friction)
+	if v.x == 0 && v.y == 0 {
+		v.remX, v.remY = 0, 0
+	}
+
+	if dx == 0 && dy == 0 {
+		return nil
+	}
+	return []MoveCommand{{ID: id, DX: int(dx), DY: int(dy)}}
+}
+
+// stopAtWalls zeroes the velocity components pointing into a boundary the point touches
+func (v *velocity) stopAtWalls(p *point.Point) {
+	if (p.X == 0 && v.x < 0) || (p.X == p.MaxX-1 && v.x > 0) {
+		v.x, v.remX = 0, 0
+	}
+	if (p.Y == 0 && v.y < 0) || (p.Y == p.MaxY-1 && v.y > 0) {
+		v.y, v.remY = 0, 0
+	}
+}
+
+// slow applies friction to a velocity component, stopping it below minSpeed
+func slow(speed, friction float64) float64 {
+	speed *= 1 - friction
+	if math.Abs(speed) < minSpeed {
+		return 0
+	}
+	return speed
+}
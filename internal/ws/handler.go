@@ -3,15 +3,30 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strconv"
 	"sync"
 
+	"github.com/gofiber/websocket/v2"
 	"github.com/rs/zerolog"
 	"github.com/shngxx/point/internal/domain/point"
 	"github.com/shngxx/point/internal/usecase"
 	wsmanager "github.com/shngxx/point/pkg/ws"
+	"github.com/shngxx/point/pkg/ws/hooks"
+	wsmiddleware "github.com/shngxx/point/pkg/ws/middleware"
 )
 
+// PointIDKey is the upgrade request query parameter selecting the point a connection controls,
+// and the connection metadata key it is stored under (e.g. /ws?point_id=5)
+const PointIDKey = "point_id"
+
+// ErrInvalidPointID is returned for connections whose point_id is not a positive integer
+var ErrInvalidPointID = errors.New("invalid point_id")
+
+// ErrPauseForbidden is returned for pause and resume commands of connections not allowed to send them
+var ErrPauseForbidden = errors.New("not allowed to pause or resume the point")
+
 // GetPointService defines the interface for getting point information
 type GetPointService interface {
 	GetPoint(ctx context.Context, id int) (*usecase.PointInfo, error)
@@ -30,97 +45,211 @@ type MoveMessage struct {
 	DY int `json:"dy,omitempty"`
 }
 
+// TeleportMessage represents a message from the client to move the point to absolute coordinates
+type TeleportMessage struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
 // PositionMessage represents a position message for the client
 type PositionMessage struct {
 	X int `json:"x"`
 	Y int `json:"y"`
 }
 
+// StateMessage notifies clients about a change of the point movement state
+type StateMessage struct {
+	Type   string `json:"type"` // Always "state"
+	Paused bool   `json:"paused"`
+}
+
+// HandlerConfig contains configuration for Handler
+type HandlerConfig struct {
+	PositionFormat PositionFormat // Position serialization format (default: object)
+	MaxStep        int            // Largest |dx| or |dy| of a move command (default: 0, unlimited)
+	StepPolicy     StepPolicy     // Move commands over MaxStep: clamp or reject (default: clamp)
+
+	// AuthorizePause reports whether a connection may pause and resume its point,
+	// which affects every session of the point
+	// Default: only authenticated connections, with "user_id" metadata (see middleware.Auth)
+	AuthorizePause func(conn *wsmanager.Connection, pointID int) bool
+}
+
+// authorizePause reports whether a connection may pause and resume its point
+func (c HandlerConfig) authorizePause(conn *wsmanager.Connection, pointID int) bool {
+	if c.AuthorizePause != nil {
+		return c.AuthorizePause(conn, pointID)
+	}
+	userID, ok := conn.GetMetadata(wsmiddleware.UserIDKey)
+	return ok && userID != ""
+}
+
 // Handler handles WebSocket connections using pkg/ws.Manager
 type Handler struct {
 	manager          *wsmanager.Manager
 	getPointService  GetPointService
 	movePointService MovePointService
 	logger           *zerolog.Logger
+	config           HandlerConfig
 	sessions         map[*wsmanager.Connection]*usecase.ClientSession
+	pausedPoints     map[int]bool
 	sessionsMu       sync.RWMutex
+	pauseMu          sync.Mutex // Serializes pause state changes, held while sessions are paused or resumed
+
+	// Position outboxes of the connections watching each point
+	watchers   map[int]map[*wsmanager.Connection]chan *point.Point
+	watchersMu sync.RWMutex
 }
 
+// watcherBuffer is the number of positions queued for a watching connection
+// Positions are dropped while the buffer is full (the next one supersedes them)
+const watcherBuffer = 16
+
 // NewHandler creates a new WebSocket handler
 func NewHandler(
 	manager *wsmanager.Manager,
 	getPointService GetPointService,
 	movePointService MovePointService,
 	logger *zerolog.Logger,
-) *Handler {
+	config HandlerConfig,
+) (*Handler, error) {
+	if err := config.PositionFormat.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.StepPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
 	h := &Handler{
 		manager:          manager,
 		getPointService:  getPointService,
 		movePointService: movePointService,
 		logger:           logger,
+		config:           config,
 		sessions:         make(map[*wsmanager.Connection]*usecase.ClientSession),
+		pausedPoints:     make(map[int]bool),
+		watchers:         make(map[int]map[*wsmanager.Connection]chan *point.Point),
 	}
 
 	// Register message handlers
 	h.registerHandlers()
 
-	return h
+	// Every connection watches its point and joins it with a session,
+	// which receives every position of the point, so spectators see live movement
+	manager.AddHook(hooks.OnConnect, func(conn hooks.ConnectionInterface, data ...any) error {
+		if c, ok := conn.(*wsmanager.Connection); ok {
+			if err := bindPoint(c); err != nil {
+				return err
+			}
+			h.watch(c)
+			h.getOrCreateSession(c)
+		}
+		return nil
+	})
+
+	return h, nil
 }
 
 // registerHandlers registers message handlers with the manager
 func (h *Handler) registerHandlers() {
 	// Handle move commands
-	h.manager.HandleMessage("move", h.handleMove)
+	h.manager.HandleMessage("move", func(conn *wsmanager.Connection, msg *wsmanager.Message) error {
+		moveMsg, err := decodeMove(msg)
+		if err != nil {
+			return err
+		}
+		return h.handleMove(conn, moveMsg)
+	})
+
+	// Handle teleport commands
+	wsmanager.HandleTyped(h.manager, "teleport", h.handleTeleport)
+
+	// Handle movement pause/resume for the connection's point (see HandlerConfig.AuthorizePause)
+	h.manager.HandleMessage("pause", func(conn *wsmanager.Connection, msg *wsmanager.Message) error {
+		pointID := pointIDOf(conn)
+		if !h.config.authorizePause(conn, pointID) {
+			return ErrPauseForbidden
+		}
+		h.PausePoint(pointID)
+		return nil
+	})
+	h.manager.HandleMessage("resume", func(conn *wsmanager.Connection, msg *wsmanager.Message) error {
+		pointID := pointIDOf(conn)
+		if !h.config.authorizePause(conn, pointID) {
+			return ErrPauseForbidden
+		}
+		h.ResumePoint(pointID)
+		return nil
+	})
 }
 
-// handleMove handles move commands from the client
-func (h *Handler) handleMove(conn *wsmanager.Connection, msg *wsmanager.Message) error {
-	var moveMsg MoveMessage
+// bindPoint stores the point ID from the upgrade request query in connection metadata
+// Connections without point_id control the default point; an invalid point_id
+// closes the connection with a policy violation (1008)
+func bindPoint(conn *wsmanager.Connection) error {
+	wc := conn.Conn()
+	if wc == nil {
+		return nil
+	}
+	raw := wc.Query(PointIDKey)
+	if raw == "" {
+		return nil
+	}
 
-	// Try to parse from data field first
-	if len(msg.Data) > 0 {
-		if err := json.Unmarshal(msg.Data, &moveMsg); err != nil {
-			// If data field parsing fails, try parsing the whole message
-			// This handles the case when frontend sends {action: 'move', dx: ..., dy: ...}
-			var fullMsg struct {
-				Action string `json:"action"`
-				DX     int    `json:"dx"`
-				DY     int    `json:"dy"`
-			}
-			// Re-parse the original message
-			msgBytes, _ := json.Marshal(msg)
-			if err := json.Unmarshal(msgBytes, &fullMsg); err == nil {
-				moveMsg.DX = fullMsg.DX
-				moveMsg.DY = fullMsg.DY
-			} else {
-				return err
-			}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		conn.CloseWithCode(websocket.ClosePolicyViolation, ErrInvalidPointID.Error())
+		return fmt.Errorf("%w: %q", ErrInvalidPointID, raw)
+	}
+	conn.SetMetadata(PointIDKey, id)
+	return nil
+}
+
+// pointIDOf returns the point ID from connection metadata or the default point
+func pointIDOf(conn *wsmanager.Connection) int {
+	if pointIDVal, ok := conn.GetMetadata(PointIDKey); ok {
+		if id, ok := pointIDVal.(int); ok {
+			return id
 		}
-	} else {
-		// If data is empty, try to get dx/dy from message directly
-		// This is a fallback for messages like {action: 'move', dx: 10, dy: 0}
-		var fullMsg map[string]any
-		msgBytes, _ := json.Marshal(msg)
-		if err := json.Unmarshal(msgBytes, &fullMsg); err == nil {
-			if dx, ok := fullMsg["dx"].(float64); ok {
-				moveMsg.DX = int(dx)
-			}
-			if dy, ok := fullMsg["dy"].(float64); ok {
-				moveMsg.DY = int(dy)
-			}
+	}
+	return 1
+}
+
+// pointRoom returns the room ID of a point
+func pointRoom(pointID int) string {
+	return "point_" + strconv.Itoa(pointID)
+}
+
+// decodeMove decodes a move command sent as {"action":"move","data":{"dx":..,"dy":..}}
+// or, without data, with the offsets at the top level: {"action":"move","dx":..,"dy":..}
+func decodeMove(msg *wsmanager.Message) (MoveMessage, error) {
+	var moveMsg MoveMessage
+	payload := msg.Data
+	if len(payload) == 0 {
+		payload = msg.Raw()
+	}
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &moveMsg); err != nil {
+			return MoveMessage{}, &wsmanager.Error{Code: "INVALID_PAYLOAD", Message: "Invalid move payload: " + err.Error()}
 		}
 	}
+	return moveMsg, nil
+}
+
+// handleMove handles move commands from the client
+// Oversized steps are clamped or rejected according to the step policy;
+// a rejected command is answered with an error and fires the OnError hook
+func (h *Handler) handleMove(conn *wsmanager.Connection, moveMsg MoveMessage) error {
+	moveMsg, err := limitStep(moveMsg, h.config.MaxStep, h.config.StepPolicy)
+	if err != nil {
+		return err
+	}
 
 	// Get or create session for this connection
 	session := h.getOrCreateSession(conn)
 
 	// Get point ID from connection metadata or use default
-	pointID := 1
-	if pointIDVal, ok := conn.GetMetadata("point_id"); ok {
-		if id, ok := pointIDVal.(int); ok {
-			pointID = id
-		}
-	}
+	pointID := pointIDOf(conn)
 
 	// If there's a move command, add it to the client channel
 	if moveMsg.DX != 0 || moveMsg.DY != 0 {
@@ -134,41 +263,52 @@ func (h *Handler) handleMove(conn *wsmanager.Connection, msg *wsmanager.Message)
 	return nil
 }
 
+// handleTeleport handles teleport commands from the client
+// The new position is sent immediately, without waiting for the batch tick
+func (h *Handler) handleTeleport(conn *wsmanager.Connection, teleportMsg TeleportMessage) error {
+	session := h.getOrCreateSession(conn)
+	session.Teleport(usecase.TeleportCommand{
+		ID: pointIDOf(conn),
+		X:  teleportMsg.X,
+		Y:  teleportMsg.Y,
+	})
+	return nil
+}
+
 // getOrCreateSession gets or creates a session for a connection
 func (h *Handler) getOrCreateSession(conn *wsmanager.Connection) *usecase.ClientSession {
 	h.sessionsMu.Lock()
-	defer h.sessionsMu.Unlock()
-
 	session, exists := h.sessions[conn]
-	if !exists {
-		// Get point ID from connection metadata or use default
-		pointID := 1
-		if pointIDVal, ok := conn.GetMetadata("point_id"); ok {
-			if id, ok := pointIDVal.(int); ok {
-				pointID = id
-			}
-		}
+	if exists {
+		h.sessionsMu.Unlock()
+		return session
+	}
+
+	// Get point ID from connection metadata or use default
+	pointID := pointIDOf(conn)
 
-		// Initialize point movement processing
-		session = h.movePointService.Init(conn.Context(), pointID)
-		h.sessions[conn] = session
+	// Initialize point movement processing
+	session = h.movePointService.Init(conn.Context(), pointID)
+	h.sessions[conn] = session
+	h.sessionsMu.Unlock()
 
-		// Start goroutine to send position updates
-		go h.sendPositionUpdates(conn, session, pointID)
+	// Sessions joining a frozen point start paused
+	// Pausing waits for the point's actor, so it is done without holding sessionsMu
+	h.pauseMu.Lock()
+	if h.IsPointPaused(pointID) {
+		session.Pause()
 	}
+	h.pauseMu.Unlock()
+
+	// Start goroutine to send position updates
+	go h.sendPositionUpdates(conn, session, pointID)
 
 	return session
 }
 
-// sendPositionUpdates sends position updates from the session to the connection
+// sendPositionUpdates forwards the positions of a session's point and the session's errors
+// to the session's connection (every session of a point receives all of its positions)
 func (h *Handler) sendPositionUpdates(conn *wsmanager.Connection, session *usecase.ClientSession, pointID int) {
-	roomID := "point_" + strconv.Itoa(pointID)
-
-	// Join room for this point
-	if err := h.manager.JoinRoom(conn, roomID); err != nil {
-		h.logger.Error().Str("room", roomID).Err(err).Msg("Failed to join room")
-	}
-
 	for {
 		select {
 		case <-conn.Context().Done():
@@ -182,18 +322,100 @@ func (h *Handler) sendPositionUpdates(conn *wsmanager.Connection, session *useca
 				// Channel closed
 				return
 			}
-			h.sendPosition(conn, pos)
+			h.deliver(pointID, conn, pos)
+		case err := <-session.ErrorChan():
+			if err == nil {
+				// Channel closed
+				return
+			}
+			h.sendError(conn, err)
 		}
 	}
 }
 
-// sendPosition sends position to a connection
-func (h *Handler) sendPosition(conn *wsmanager.Connection, pos *point.Point) {
-	msg := PositionMessage{
-		X: pos.X,
-		Y: pos.Y,
+// watch joins a connection to its point's room and sends it the point's positions until it closes
+func (h *Handler) watch(conn *wsmanager.Connection) {
+	pointID := pointIDOf(conn)
+	roomID := pointRoom(pointID)
+	if err := h.manager.JoinRoom(conn, roomID); err != nil {
+		h.logger.Error().Str("room", roomID).Err(err).Msg("Failed to join room")
+	}
+
+	outbox := make(chan *point.Point, watcherBuffer)
+	h.watchersMu.Lock()
+	if h.watchers[pointID] == nil {
+		h.watchers[pointID] = make(map[*wsmanager.Connection]chan *point.Point)
+	}
+	h.watchers[pointID][conn] = outbox
+	h.watchersMu.Unlock()
+
+	go func() {
+		defer func() {
+			h.watchersMu.Lock()
+			delete(h.watchers[pointID], conn)
+			if len(h.watchers[pointID]) == 0 {
+				delete(h.watchers, pointID)
+			}
+			h.watchersMu.Unlock()
+		}()
+
+		// Each connection has its own encoder (delta encoding is stateful)
+		encoder := NewPositionEncoder(h.config.PositionFormat)
+		var last *point.Point
+		for {
+			select {
+			case <-conn.Context().Done():
+				return
+			case pos := <-outbox:
+				// Broadcasts (e.g. BroadcastPosition) may repeat a position the session sent
+				if last != nil && *last == *pos {
+					continue
+				}
+				last = pos
+				h.sendPosition(conn, encoder, pos)
+			}
+		}
+	}()
+}
+
+// deliver queues a position for a connection watching a point
+func (h *Handler) deliver(pointID int, conn *wsmanager.Connection, pos *point.Point) {
+	h.watchersMu.RLock()
+	defer h.watchersMu.RUnlock()
+
+	if outbox, ok := h.watchers[pointID][conn]; ok {
+		select {
+		case outbox <- pos:
+		default:
+			// The connection is not keeping up, drop the position
+		}
+	}
+}
+
+// fanOut queues a position for every connection watching a point
+func (h *Handler) fanOut(pointID int, pos *point.Point) {
+	h.watchersMu.RLock()
+	defer h.watchersMu.RUnlock()
+
+	for _, outbox := range h.watchers[pointID] {
+		select {
+		case outbox <- pos:
+		default:
+			// The connection is not keeping up, drop the position
+		}
+	}
+}
+
+// sendError sends a processing error to a connection
+func (h *Handler) sendError(conn *wsmanager.Connection, err error) {
+	if err := conn.WriteJSON(map[string]any{"error": err.Error()}); err != nil {
+		h.logger.Error().Err(err).Msg("WebSocket send error")
 	}
-	if err := conn.WriteJSON(msg); err != nil {
+}
+
+// sendPosition sends position to a connection
+func (h *Handler) sendPosition(conn *wsmanager.Connection, encoder PositionEncoder, pos *point.Point) {
+	if err := conn.WriteJSON(encoder.Encode(pos)); err != nil {
 		h.logger.Error().Err(err).Msg("WebSocket send error")
 	}
 }
@@ -207,14 +429,91 @@ func (h *Handler) BroadcastPosition(ctx context.Context, pointID int) {
 		return
 	}
 
-	roomID := "point_" + strconv.Itoa(pointID)
-	msg := PositionMessage{
-		X: pointInfo.Point.X,
-		Y: pointInfo.Point.Y,
+	h.broadcastPoint(pointID, pointInfo.Point)
+}
+
+// BroadcastEvents broadcasts the new position of every moved point to the point's room
+// until ctx is canceled or the events channel is closed. Unlike BroadcastPosition it needs no
+// repository read, so it can push positions from a point.EventBus subscription.
+//
+// Example:
+//
+//	events, unsubscribe := bus.Subscribe(100)
+//	defer unsubscribe()
+//	go handler.BroadcastEvents(ctx, events)
+func (h *Handler) BroadcastEvents(ctx context.Context, events <-chan point.PointMovedEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+			h.broadcastPoint(evt.ID, &point.Point{X: evt.NewX, Y: evt.NewY})
+		}
+	}
+}
+
+// broadcastPoint sends a point position to every connection watching the point
+func (h *Handler) broadcastPoint(pointID int, p *point.Point) {
+	h.fanOut(pointID, &point.Point{X: p.X, Y: p.Y})
+}
+
+// PausePoint pauses movement processing of all sessions of a point
+// without closing them and notifies the point's room
+func (h *Handler) PausePoint(pointID int) {
+	h.setPaused(pointID, true)
+}
+
+// ResumePoint resumes movement processing of a paused point and notifies the point's room
+func (h *Handler) ResumePoint(pointID int) {
+	h.setPaused(pointID, false)
+}
+
+// IsPointPaused reports whether movement processing of a point is paused
+func (h *Handler) IsPointPaused(pointID int) bool {
+	h.sessionsMu.RLock()
+	defer h.sessionsMu.RUnlock()
+	return h.pausedPoints[pointID]
+}
+
+// setPaused changes the movement state of a point and broadcasts the change
+// Pausing a session waits for the point's actor, so the sessions are collected under sessionsMu
+// and paused or resumed after releasing it; pauseMu keeps concurrent changes in order
+func (h *Handler) setPaused(pointID int, paused bool) {
+	h.pauseMu.Lock()
+	defer h.pauseMu.Unlock()
+
+	h.sessionsMu.Lock()
+	if h.pausedPoints[pointID] == paused {
+		h.sessionsMu.Unlock()
+		return
+	}
+	if paused {
+		h.pausedPoints[pointID] = true
+	} else {
+		delete(h.pausedPoints, pointID)
+	}
+	var sessions []*usecase.ClientSession
+	for conn, session := range h.sessions {
+		if pointIDOf(conn) == pointID {
+			sessions = append(sessions, session)
+		}
+	}
+	h.sessionsMu.Unlock()
+
+	for _, session := range sessions {
+		if paused {
+			session.Pause()
+		} else {
+			session.Resume()
+		}
 	}
 
-	if err := h.manager.BroadcastToRoom(roomID, msg); err != nil {
-		h.logger.Error().Str("room", roomID).Err(err).Msg("Error broadcasting position")
+	roomID := pointRoom(pointID)
+	if err := h.manager.BroadcastToRoom(roomID, StateMessage{Type: "state", Paused: paused}); err != nil {
+		h.logger.Debug().Str("room", roomID).Err(err).Msg("No clients to notify about point state")
 	}
 }
 
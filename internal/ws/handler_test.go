@@ -0,0 +1,267 @@
+package ws
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/rs/zerolog"
+	"github.com/shngxx/point/internal/domain/point"
+	"github.com/shngxx/point/internal/infrastructure/db"
+	"github.com/shngxx/point/internal/usecase"
+	wsmanager "github.com/shngxx/point/pkg/ws"
+)
+
+// newTestHandler serves a Handler backed by an in-memory repository and returns a connected client
+func newTestHandler(t *testing.T) (*Handler, *fastws.Conn) {
+	t.Helper()
+
+	h, url := serveTestHandler(t)
+	return h, dialTestHandler(t, url)
+}
+
+// dialTestHandler connects a client to a served Handler
+func dialTestHandler(t *testing.T, url string) *fastws.Conn {
+	t.Helper()
+
+	client, _, err := fastws.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// serveTestHandler serves a Handler backed by an in-memory repository and returns its WebSocket URL
+func serveTestHandler(t *testing.T) (*Handler, string) {
+	t.Helper()
+
+	return serveTestHandlerWithConfig(t, HandlerConfig{})
+}
+
+// serveTestHandlerWithConfig serves a Handler with the given configuration, like serveTestHandler
+func serveTestHandlerWithConfig(t *testing.T, config HandlerConfig) (*Handler, string) {
+	t.Helper()
+
+	logger := zerolog.Nop()
+	repo := db.NewPointRepository()
+	manager := wsmanager.NewManager()
+	h, err := NewHandler(
+		manager,
+		usecase.NewGetPointUC(repo),
+		usecase.NewMovePointUC(repo, nil, &logger, usecase.MovePointConfig{
+			BatchInterval: time.Millisecond,
+			SaveInterval:  time.Hour,
+		}),
+		&logger,
+		config,
+	)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws", websocket.New(manager.HandleConnection))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	go app.Listener(ln)
+
+	t.Cleanup(func() {
+		manager.Shutdown()
+		app.Shutdown()
+	})
+
+	return h, "ws://" + ln.Addr().String() + "/ws"
+}
+
+// TestHandler_Move tests that typed move payloads move the point
+// and malformed payloads are answered with an error
+func TestHandler_Move(t *testing.T) {
+	_, client := newTestHandler(t)
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	if err := client.WriteMessage(fastws.TextMessage, []byte(`{"action":"move","data":{"dx":"left"}}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	var resp map[string]any
+	if err := client.ReadJSON(&resp); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if msg, _ := resp["error"].(string); !strings.HasPrefix(msg, "Invalid move payload") {
+		t.Errorf("response to malformed payload = %v, expected invalid payload error", resp)
+	}
+
+	if err := client.WriteMessage(fastws.TextMessage, []byte(`{"action":"move","data":{"dx":5,"dy":-2}}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	var pos PositionMessage
+	if err := client.ReadJSON(&pos); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if pos.X != point.DefaultX+5 || pos.Y != point.DefaultY-2 {
+		t.Errorf("position = %+v, expected {X:%d Y:%d}", pos, point.DefaultX+5, point.DefaultY-2)
+	}
+}
+
+// TestHandler_MoveTopLevel tests that move commands with the offsets
+// at the top level of the message, without data, still move the point
+func TestHandler_MoveTopLevel(t *testing.T) {
+	_, client := newTestHandler(t)
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	if err := client.WriteMessage(fastws.TextMessage, []byte(`{"action":"move","dx":-4,"dy":7}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	var pos PositionMessage
+	if err := client.ReadJSON(&pos); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if pos.X != point.DefaultX-4 || pos.Y != point.DefaultY+7 {
+		t.Errorf("position = %+v, expected {X:%d Y:%d}", pos, point.DefaultX-4, point.DefaultY+7)
+	}
+}
+
+// TestHandler_MoveBroadcast tests that a move is seen by every connection on the point,
+// including spectators that never moved
+func TestHandler_MoveBroadcast(t *testing.T) {
+	h, url := serveTestHandler(t)
+	mover := dialTestHandler(t, url)
+	spectator := dialTestHandler(t, url)
+
+	// Wait until both connections watch the point
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		h.watchersMu.RLock()
+		watching := len(h.watchers[1])
+		h.watchersMu.RUnlock()
+		if watching == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("watching connections = %d, expected 2", watching)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := mover.WriteMessage(fastws.TextMessage, []byte(`{"action":"move","data":{"dx":3,"dy":4}}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+
+	for name, client := range map[string]*fastws.Conn{"mover": mover, "spectator": spectator} {
+		client.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var pos PositionMessage
+		if err := client.ReadJSON(&pos); err != nil {
+			t.Fatalf("%s ReadJSON() error = %v", name, err)
+		}
+		if pos.X != point.DefaultX+3 || pos.Y != point.DefaultY+4 {
+			t.Errorf("%s position = %+v, expected {X:%d Y:%d}", name, pos, point.DefaultX+3, point.DefaultY+4)
+		}
+	}
+}
+
+// TestHandler_PointIDQuery tests that ?point_id= selects the point a connection moves and watches,
+// and that an invalid point_id is rejected with a policy violation
+func TestHandler_PointIDQuery(t *testing.T) {
+	h, url := serveTestHandler(t)
+	mover := dialTestHandler(t, url+"?point_id=5")
+	spectator := dialTestHandler(t, url+"?point_id=5")
+	other := dialTestHandler(t, url)
+
+	// Wait until the connections watch their points
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		h.watchersMu.RLock()
+		onFive, onOne := len(h.watchers[5]), len(h.watchers[1])
+		h.watchersMu.RUnlock()
+		if onFive == 2 && onOne == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("watching connections = %d on point 5, %d on point 1, expected 2 and 1", onFive, onOne)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := mover.WriteMessage(fastws.TextMessage, []byte(`{"action":"move","data":{"dx":3,"dy":4}}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+
+	spectator.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var pos PositionMessage
+	if err := spectator.ReadJSON(&pos); err != nil {
+		t.Fatalf("spectator ReadJSON() error = %v", err)
+	}
+	if pos.X != point.DefaultX+3 || pos.Y != point.DefaultY+4 {
+		t.Errorf("point 5 position = %+v, expected {X:%d Y:%d}", pos, point.DefaultX+3, point.DefaultY+4)
+	}
+
+	// The default point did not move
+	other.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, data, err := other.ReadMessage(); err == nil {
+		t.Errorf("point 1 received %s, expected no update", data)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		h, url := serveTestHandler(t)
+		for _, query := range []string{"?point_id=0", "?point_id=-1", "?point_id=abc"} {
+			client := dialTestHandler(t, url+query)
+			client.SetReadDeadline(time.Now().Add(2 * time.Second))
+			if _, _, err := client.ReadMessage(); !fastws.IsCloseError(err, fastws.ClosePolicyViolation) {
+				t.Errorf("%s read error = %v, expected policy violation close (1008)", query, err)
+			}
+		}
+
+		// Rejected connections are unregistered
+		deadline := time.Now().Add(2 * time.Second)
+		for h.Manager().GetConnectionCount() != 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("GetConnectionCount() = %d, expected 0", h.Manager().GetConnectionCount())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
+
+// TestHandler_PauseAuthorization tests that only authorized connections may pause a point
+func TestHandler_PauseAuthorization(t *testing.T) {
+	h, client := newTestHandler(t)
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	// Unauthenticated connections are not allowed by default
+	if err := client.WriteMessage(fastws.TextMessage, []byte(`{"action":"pause"}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	var resp map[string]any
+	if err := client.ReadJSON(&resp); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if resp["error"] != ErrPauseForbidden.Error() {
+		t.Errorf("response to pause = %v, expected %q", resp, ErrPauseForbidden)
+	}
+	if h.IsPointPaused(1) {
+		t.Error("IsPointPaused(1) = true after a forbidden pause")
+	}
+
+	// Connections allowed by AuthorizePause pause the point for everyone
+	h, url := serveTestHandlerWithConfig(t, HandlerConfig{
+		AuthorizePause: func(conn *wsmanager.Connection, pointID int) bool { return pointID == 1 },
+	})
+	client = dialTestHandler(t, url)
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := client.WriteMessage(fastws.TextMessage, []byte(`{"action":"pause"}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	var state StateMessage
+	if err := client.ReadJSON(&state); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if state.Type != "state" || !state.Paused || !h.IsPointPaused(1) {
+		t.Errorf("state = %+v, IsPointPaused(1) = %v, expected the point paused", state, h.IsPointPaused(1))
+	}
+}
@@ -0,0 +1,84 @@
+package ws
+
+import (
+	"fmt"
+
+	"github.com/shngxx/point/internal/domain/point"
+)
+
+// PositionFormat defines how position updates are serialized for the client
+//
+// Client contract per format:
+//   - "object" (default): every frame is {"x":10,"y":20}
+//   - "array": every frame is [10,20]
+//   - "delta": the first frame is an absolute {"x":10,"y":20}, subsequent frames are
+//     relative offsets [dx,dy] from the previously sent position.
+//     An object frame always resets the client's base position.
+type PositionFormat string
+
+const (
+	// PositionFormatObject serializes position as {"x":..,"y":..}
+	PositionFormatObject PositionFormat = "object"
+	// PositionFormatArray serializes position as [x,y]
+	PositionFormatArray PositionFormat = "array"
+	// PositionFormatDelta serializes position as [dx,dy] relative to the last sent position
+	PositionFormatDelta PositionFormat = "delta"
+)
+
+// Validate checks that the format is supported (empty means default)
+func (f PositionFormat) Validate() error {
+	switch f {
+	case "", PositionFormatObject, PositionFormatArray, PositionFormatDelta:
+		return nil
+	default:
+		return fmt.Errorf("unknown position format: %q", f)
+	}
+}
+
+// PositionEncoder converts a position into its wire representation
+// Encoders may be stateful, so each connection uses its own encoder
+type PositionEncoder interface {
+	Encode(pos *point.Point) any
+}
+
+// NewPositionEncoder creates a position encoder for the given format
+// Unknown or empty formats fall back to PositionFormatObject
+func NewPositionEncoder(format PositionFormat) PositionEncoder {
+	switch format {
+	case PositionFormatArray:
+		return arrayEncoder{}
+	case PositionFormatDelta:
+		return &deltaEncoder{}
+	default:
+		return objectEncoder{}
+	}
+}
+
+// objectEncoder encodes position as PositionMessage
+type objectEncoder struct{}
+
+func (objectEncoder) Encode(pos *point.Point) any {
+	return PositionMessage{X: pos.X, Y: pos.Y}
+}
+
+// arrayEncoder encodes position as [x,y]
+type arrayEncoder struct{}
+
+func (arrayEncoder) Encode(pos *point.Point) any {
+	return [2]int{pos.X, pos.Y}
+}
+
+// deltaEncoder encodes position as [dx,dy] relative to the last encoded position
+type deltaEncoder struct {
+	last *point.Point
+}
+
+func (e *deltaEncoder) Encode(pos *point.Point) any {
+	if e.last == nil {
+		e.last = &point.Point{X: pos.X, Y: pos.Y}
+		return PositionMessage{X: pos.X, Y: pos.Y}
+	}
+	delta := [2]int{pos.X - e.last.X, pos.Y - e.last.Y}
+	e.last.X, e.last.Y = pos.X, pos.Y
+	return delta
+}
@@ -0,0 +1,52 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shngxx/point/internal/domain/point"
+)
+
+// TestPositionEncoder tests the wire output of each position format for a sample movement
+func TestPositionEncoder(t *testing.T) {
+	movement := []*point.Point{
+		{X: 400, Y: 300},
+		{X: 401, Y: 300},
+		{X: 405, Y: 298},
+	}
+
+	tests := []struct {
+		format   PositionFormat
+		expected []string
+	}{
+		{PositionFormatObject, []string{`{"x":400,"y":300}`, `{"x":401,"y":300}`, `{"x":405,"y":298}`}},
+		{"", []string{`{"x":400,"y":300}`, `{"x":401,"y":300}`, `{"x":405,"y":298}`}},
+		{PositionFormatArray, []string{`[400,300]`, `[401,300]`, `[405,298]`}},
+		{PositionFormatDelta, []string{`{"x":400,"y":300}`, `[1,0]`, `[4,-2]`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			encoder := NewPositionEncoder(tt.format)
+			for i, pos := range movement {
+				data, err := json.Marshal(encoder.Encode(pos))
+				if err != nil {
+					t.Fatalf("json.Marshal() error = %v", err)
+				}
+				if string(data) != tt.expected[i] {
+					t.Errorf("frame %d = %s, expected %s", i, data, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestPositionFormatValidate tests rejection of unknown formats
+func TestPositionFormatValidate(t *testing.T) {
+	if err := PositionFormat("protobuf").Validate(); err == nil {
+		t.Error("Validate() should return error for unknown format")
+	}
+	if err := PositionFormatDelta.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
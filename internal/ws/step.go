@@ -0,0 +1,52 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStepTooLarge is returned for move commands over the maximum step when oversized steps are rejected
+var ErrStepTooLarge = errors.New("move step too large")
+
+// StepPolicy defines what happens to move commands whose |dx| or |dy| exceeds the maximum step
+type StepPolicy string
+
+const (
+	// StepClamp reduces oversized offsets to the maximum step, keeping their direction
+	StepClamp StepPolicy = "clamp"
+	// StepReject drops oversized commands and answers the client with an error
+	StepReject StepPolicy = "reject"
+)
+
+// Validate checks that the policy is supported (empty means default)
+func (p StepPolicy) Validate() error {
+	switch p {
+	case "", StepClamp, StepReject:
+		return nil
+	default:
+		return fmt.Errorf("unknown step policy: %q", p)
+	}
+}
+
+// limitStep applies the maximum step to a move command
+// maxStep <= 0 disables the limit
+func limitStep(msg MoveMessage, maxStep int, policy StepPolicy) (MoveMessage, error) {
+	if maxStep <= 0 || (abs(msg.DX) <= maxStep && abs(msg.DY) <= maxStep) {
+		return msg, nil
+	}
+	if policy == StepReject {
+		return MoveMessage{}, fmt.Errorf("%w: (%d, %d) exceeds %d", ErrStepTooLarge, msg.DX, msg.DY, maxStep)
+	}
+	return MoveMessage{
+		DX: max(-maxStep, min(msg.DX, maxStep)),
+		DY: max(-maxStep, min(msg.DY, maxStep)),
+	}, nil
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,49 @@
+package ws
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLimitStep tests in-range, clamped and rejected move commands
+func TestLimitStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      MoveMessage
+		maxStep  int
+		policy   StepPolicy
+		expected MoveMessage
+		wantErr  error
+	}{
+		{"in range", MoveMessage{DX: 5, DY: -5}, 5, StepClamp, MoveMessage{DX: 5, DY: -5}, nil},
+		{"unlimited", MoveMessage{DX: 1000000}, 0, StepReject, MoveMessage{DX: 1000000}, nil},
+		{"clamped", MoveMessage{DX: 1000000, DY: -3}, 5, StepClamp, MoveMessage{DX: 5, DY: -3}, nil},
+		{"clamped negative", MoveMessage{DX: 2, DY: -1000000}, 5, "", MoveMessage{DX: 2, DY: -5}, nil},
+		{"rejected", MoveMessage{DX: 1000000}, 5, StepReject, MoveMessage{}, ErrStepTooLarge},
+		{"in range with reject", MoveMessage{DX: -5, DY: 5}, 5, StepReject, MoveMessage{DX: -5, DY: 5}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := limitStep(tt.msg, tt.maxStep, tt.policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("limitStep() error = %v, expected %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("limitStep() = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestStepPolicy_Validate tests that unknown step policies are rejected
+func TestStepPolicy_Validate(t *testing.T) {
+	for _, policy := range []StepPolicy{"", StepClamp, StepReject} {
+		if err := policy.Validate(); err != nil {
+			t.Errorf("Validate(%q) error = %v", policy, err)
+		}
+	}
+	if err := StepPolicy("teleport").Validate(); err == nil {
+		t.Error("Validate(\"teleport\") error = nil, expected error")
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// durationType is the reflect.Type of time.Duration
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyDefaults sets the values of `default:"..."` struct tags of target's fields
+// for keys that are absent from the loaded configuration (file and environment).
+// prefix is the koanf path of target (empty for the root).
+//
+// Defaults are parsed according to the field type: strings as is, integers, floats and bools
+// with strconv, time.Duration with time.ParseDuration (e.g. "30s").
+//
+// Example:
+//
+//	type ServerConfig struct {
+//	    Port         int           `koanf:"port" default:"8080"`
+//	    ReadTimeout  time.Duration `koanf:"readTimeout" default:"10s"`
+//	}
+func applyDefaults(k *koanf.Koanf, prefix string, target any) error {
+	typ := reflect.TypeOf(target)
+	if typ == nil || typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	// Environment variables are loaded as lowercase keys, so keys are compared case-insensitively
+	present := make(map[string]bool)
+	for _, key := range k.Keys() {
+		present[strings.ToLower(key)] = true
+	}
+
+	return setDefaults(k, present, prefix, typ.Elem())
+}
+
+// setDefaults sets the defaults of the fields of a struct type at the koanf path prefix
+func setDefaults(k *koanf.Koanf, present map[string]bool, prefix string, typ reflect.Type) error {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("koanf"), ","); tag != "" {
+			name = tag
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		// Nested structures are processed recursively
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			if err := setDefaults(k, present, key, field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def, ok := field.Tag.Lookup("default")
+		if !ok || present[strings.ToLower(key)] {
+			continue
+		}
+
+		value, err := parseDefault(def, field.Type)
+		if err != nil {
+			return fmt.Errorf("invalid default for %s: %w", key, err)
+		}
+		if err := k.Set(key, value); err != nil {
+			return fmt.Errorf("error setting default for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// parseDefault parses a default value according to the field type
+func parseDefault(def string, typ reflect.Type) (any, error) {
+	if typ == durationType {
+		return time.ParseDuration(def)
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return def, nil
+	case reflect.Bool:
+		return strconv.ParseBool(def)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(def, 10, typ.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(def, 10, typ.Bits())
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(def, typ.Bits())
+	default:
+		return nil, fmt.Errorf("unsupported field type %v", typ)
+	}
+}
@@ -1,34 +1,152 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/parsers/toml"
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
 )
 
-// getDefaultConfigPath returns the path to config.yaml next to the executable.
-// If the executable path cannot be determined, it falls back to "config.yaml" in the current directory.
+// ConfigPathEnv is the environment variable that overrides the default config file path
+const ConfigPathEnv = "APP_CONFIG"
+
+var (
+	searchPaths   []string // custom default config search paths, nil = built-in list
+	searchPathsMu sync.RWMutex
+)
+
+// SetDefaultSearchPaths replaces the list of paths searched for the default config file
+// (used by LoadDefault and the other *Default functions). Paths are tried in order and the
+// first existing file is used; $APP_CONFIG still takes precedence when set.
+// Passing nil restores the built-in list (see DefaultSearchPaths).
+func SetDefaultSearchPaths(paths []string) {
+	searchPathsMu.Lock()
+	defer searchPathsMu.Unlock()
+	searchPaths = slices.Clone(paths)
+}
+
+// DefaultSearchPaths returns the paths searched for the default config file, in order.
+// Unless replaced with SetDefaultSearchPaths, these are:
+//  1. ./config.yaml (current directory, e.g. for go run)
+//  2. <executable dir>/config.yaml
+//  3. /etc/<executable name>/config.yaml
+func DefaultSearchPaths() []string {
+	searchPathsMu.RLock()
+	defer searchPathsMu.RUnlock()
+	if searchPaths != nil {
+		return slices.Clone(searchPaths)
+	}
+
+	paths := []string{"config.yaml"}
+	if execPath, err := os.Executable(); err == nil {
+		paths = append(paths,
+			filepath.Join(filepath.Dir(execPath), "config.yaml"),
+			filepath.Join("/etc", filepath.Base(execPath), "config.yaml"),
+		)
+	}
+	return paths
+}
+
+// getDefaultConfigPath returns the path of the default config file: $APP_CONFIG if set,
+// otherwise the first existing file of DefaultSearchPaths.
+// If none exists, the first search path is returned so that errors name the file that was expected.
 func getDefaultConfigPath() string {
-	execPath, err := os.Executable()
-	if err != nil {
-		// Fallback to current directory if executable path cannot be determined
-		return "config.yaml"
+	if configPath := os.Getenv(ConfigPathEnv); configPath != "" {
+		return configPath
+	}
+
+	paths := DefaultSearchPaths()
+	for _, configPath := range paths {
+		if info, err := os.Stat(configPath); err == nil && !info.IsDir() {
+			return configPath
+		}
+	}
+	if len(paths) > 0 {
+		return paths[0]
 	}
-	execDir := filepath.Dir(execPath)
-	return filepath.Join(execDir, "config.yaml")
+	return "config.yaml"
 }
 
-// Load loads configuration from a YAML file with override via environment variables.
+// parserFor returns the parser matching the config file extension:
+// .json -> JSON, .toml -> TOML, anything else (.yaml, .yml, unknown) -> YAML
+func parserFor(configPath string) koanf.Parser {
+	switch strings.ToLower(filepath.Ext(configPath)) {
+	case ".json":
+		return json.Parser()
+	case ".toml":
+		return toml.Parser()
+	default:
+		return yaml.Parser()
+	}
+}
+
+// unmarshal unmarshals the configuration at path into target.
+// Besides koanf's default conversions, time.Duration fields accept duration strings ("30s", "1m30s")
+// and plain numbers, which are interpreted as seconds for compatibility with integer second fields.
+func unmarshal(k *koanf.Koanf, path string, target any) error {
+	return k.UnmarshalWithConf(path, target, koanf.UnmarshalConf{
+		DecoderConfig: &mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				secondsToDurationHookFunc(),
+				mapstructure.StringToTimeDurationHookFunc(),
+				mapstructure.TextUnmarshallerHookFunc(),
+			),
+			WeaklyTypedInput: true,
+		},
+	})
+}
+
+// secondsToDurationHookFunc converts numbers (and numeric strings, e.g. from environment variables)
+// to time.Duration as seconds
+func secondsToDurationHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if to != durationType || from == durationType {
+			return data, nil
+		}
+
+		var seconds float64
+		switch from.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			seconds = float64(reflect.ValueOf(data).Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			seconds = float64(reflect.ValueOf(data).Uint())
+		case reflect.Float32, reflect.Float64:
+			seconds = reflect.ValueOf(data).Float()
+		case reflect.String:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(data.(string)), 64)
+			if err != nil {
+				// Not a number, e.g. "30s"
+				return data, nil
+			}
+			seconds = parsed
+		default:
+			return data, nil
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+}
+
+// Load loads configuration from a YAML, JSON or TOML file with override via environment variables.
+// The format is detected from the file extension (.yaml/.yml, .json, .toml); unknown extensions are parsed as YAML.
 // Environment variables are automatically determined from the configuration structure.
 //
 // Parameters:
-//   - configPath: path to the YAML configuration file
+//   - configPath: path to the configuration file
 //   - target: pointer to the structure into which the configuration will be loaded
 //
 // Example:
@@ -44,17 +162,18 @@ func Load(configPath string, target any) error {
 	return LoadWithPrefix(configPath, target, "")
 }
 
-// LoadWithPrefix loads configuration from a YAML file with override via environment variables,
+// LoadWithPrefix loads configuration from a YAML, JSON or TOML file (see Load) with override via environment variables,
 // using the specified prefix for environment variables.
 //
 // Parameters:
-//   - configPath: path to the YAML configuration file
+//   - configPath: path to the configuration file
 //   - target: pointer to the structure into which the configuration will be loaded
 //   - envPrefix: prefix for environment variables (e.g., "APP" for APP_HOST, APP_PORT)
 //
 // Override hierarchy (from lowest to highest):
-//  1. YAML file
-//  2. Environment variables
+//  1. `default:"..."` struct tags (e.g. `default:"8080"`, `default:"30s"` for time.Duration)
+//  2. Configuration file
+//  3. Environment variables
 //
 // Environment variables are formed as follows:
 //   - Nested structures are separated by "_"
@@ -74,13 +193,83 @@ func Load(configPath string, target any) error {
 //	// Override via: APP_SERVER_HOST, APP_SERVER_PORT
 //	err := config.LoadWithPrefix("config.yaml", &cfg, "APP")
 func LoadWithPrefix(configPath string, target any, envPrefix string) error {
+	return loadWithParser(configPath, target, envPrefix, parserFor(configPath))
+}
+
+// LoadWithParser loads configuration from a file using the given parser, regardless of
+// the file extension, with override via environment variables (see Load).
+//
+// Example:
+//
+//	var cfg Config
+//	err := config.LoadWithParser("config.conf", &cfg, json.Parser())
+func LoadWithParser(configPath string, target any, parser koanf.Parser) error {
+	return loadWithParser(configPath, target, "", parser)
+}
+
+// loadWithParser loads configuration from a file using the given parser,
+// overriding it with environment variables with the given prefix
+func loadWithParser(configPath string, target any, envPrefix string, parser koanf.Parser) error {
 	k := koanf.New(".")
 
-	// 1. Load configuration from YAML file
-	if err := k.Load(file.Provider(configPath), yaml.Parser()); err != nil {
+	// 1. Load configuration from file
+	if err := k.Load(file.Provider(configPath), parser); err != nil {
 		return fmt.Errorf("error loading configuration from file %s: %w", configPath, err)
 	}
 
+	// 2-4. Override with environment variables, apply defaults and unmarshal
+	return unmarshalWithEnv(k, target, envPrefix)
+}
+
+// LoadLayered loads configuration from several files in order, so that keys in later files
+// override the same keys in earlier ones (e.g. a base config.yaml and an environment-specific
+// overlay), then applies environment variable overrides (see Load).
+// Each file may be YAML, JSON or TOML, detected from its extension.
+// Fails if any of the files does not exist.
+//
+// Example:
+//
+//	var cfg Config
+//	err := config.LoadLayered(&cfg, "config.yaml", "config.production.yaml")
+func LoadLayered(target any, paths ...string) error {
+	return loadLayered(target, paths, false)
+}
+
+// LoadLayeredOptional is like LoadLayered, but skips files that do not exist.
+// Files that exist but cannot be parsed still fail.
+//
+// Example:
+//
+//	var cfg Config
+//	// config.local.yaml is only present on developer machines
+//	err := config.LoadLayeredOptional(&cfg, "config.yaml", "config.local.yaml")
+func LoadLayeredOptional(target any, paths ...string) error {
+	return loadLayered(target, paths, true)
+}
+
+// loadLayered loads the files into one koanf instance in order, skipping missing ones if optional
+func loadLayered(target any, paths []string, optional bool) error {
+	k := koanf.New(".")
+
+	// 1. Load configuration files, later files override earlier keys
+	for _, configPath := range paths {
+		if optional {
+			if _, err := os.Stat(configPath); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+		if err := k.Load(file.Provider(configPath), parserFor(configPath)); err != nil {
+			return fmt.Errorf("error loading configuration from file %s: %w", configPath, err)
+		}
+	}
+
+	// 2-4. Override with environment variables, apply defaults and unmarshal
+	return unmarshalWithEnv(k, target, "")
+}
+
+// unmarshalWithEnv overrides the loaded configuration with environment variables
+// with the given prefix, applies struct tag defaults and unmarshals it into target
+func unmarshalWithEnv(k *koanf.Koanf, target any, envPrefix string) error {
 	// 2. Override with values from environment variables
 	// Variable format: PREFIX_KEY1_KEY2 (where . is replaced with _)
 	// Callback function to transform environment variable names into configuration keys
@@ -97,15 +286,20 @@ func LoadWithPrefix(configPath string, target any, envPrefix string) error {
 		return fmt.Errorf("error loading environment variables: %w", err)
 	}
 
-	// 3. Unmarshal configuration into target structure
-	if err := k.Unmarshal("", target); err != nil {
+	// 3. Apply struct tag defaults for keys absent from both file and environment
+	if err := applyDefaults(k, "", target); err != nil {
+		return err
+	}
+
+	// 4. Unmarshal configuration into target structure
+	if err := unmarshal(k, "", target); err != nil {
 		return fmt.Errorf("error deserializing configuration: %w", err)
 	}
 
 	return nil
 }
 
-// LoadDefault loads configuration from the default config.yaml file (next to the executable)
+// LoadDefault loads configuration from the default config file ($APP_CONFIG or the first existing DefaultSearchPaths entry)
 // with override via environment variables.
 // Environment variables are automatically determined from the configuration structure.
 // Panics if configuration cannot be loaded.
@@ -126,7 +320,12 @@ func LoadDefault(target any) {
 	LoadWithPrefixDefault(target, "")
 }
 
-// LoadWithPrefixDefault loads configuration from the default config.yaml file (next to the executable)
+// TryLoadDefault is like LoadDefault, but returns an error instead of panicking.
+func TryLoadDefault(target any) error {
+	return TryLoadWithPrefixDefault(target, "")
+}
+
+// LoadWithPrefixDefault loads configuration from the default config file ($APP_CONFIG or the first existing DefaultSearchPaths entry)
 // with override via environment variables, using the specified prefix for environment variables.
 // Panics if configuration cannot be loaded.
 //
@@ -135,8 +334,9 @@ func LoadDefault(target any) {
 //   - envPrefix: prefix for environment variables (e.g., "APP_" for APP_HOST, APP_PORT)
 //
 // Override hierarchy (from lowest to highest):
-//  1. YAML file
-//  2. Environment variables
+//  1. `default:"..."` struct tags
+//  2. Configuration file
+//  3. Environment variables
 //
 // Environment variables are formed as follows:
 //   - Nested structures are separated by "_"
@@ -156,17 +356,29 @@ func LoadDefault(target any) {
 //	// Override via: APP_SERVER_HOST, APP_SERVER_PORT
 //	config.LoadWithPrefixDefault(&cfg, "APP_")
 func LoadWithPrefixDefault(target any, envPrefix string) {
-	configPath := getDefaultConfigPath()
-	if err := LoadWithPrefix(configPath, target, envPrefix); err != nil {
-		panic(fmt.Sprintf("failed to load configuration: %v", err))
+	if configPath, err := loadWithPrefixDefault(target, envPrefix); err != nil {
+		panic(fmt.Sprintf("failed to load configuration from %s: %v", configPath, err))
 	}
 }
 
-// LoadSection loads a specific section from a YAML file with override via environment variables.
+// TryLoadWithPrefixDefault is like LoadWithPrefixDefault, but returns an error instead of panicking.
+func TryLoadWithPrefixDefault(target any, envPrefix string) error {
+	_, err := loadWithPrefixDefault(target, envPrefix)
+	return err
+}
+
+// loadWithPrefixDefault loads configuration from the default config file
+// and returns the path of the file it attempted
+func loadWithPrefixDefault(target any, envPrefix string) (string, error) {
+	configPath := getDefaultConfigPath()
+	return configPath, LoadWithPrefix(configPath, target, envPrefix)
+}
+
+// LoadSection loads a specific section from a YAML, JSON or TOML file (see Load) with override via environment variables.
 // Useful when configurations for multiple services are stored in one YAML file.
 //
 // Parameters:
-//   - configPath: path to the YAML configuration file
+//   - configPath: path to the configuration file
 //   - section: section name in the YAML file (e.g., "database", "redis")
 //   - target: pointer to the structure into which the configuration will be loaded
 //   - envPrefix: prefix for environment variables
@@ -193,8 +405,8 @@ func LoadWithPrefixDefault(target any, envPrefix string) {
 func LoadSection(configPath string, section string, target any, envPrefix string) error {
 	k := koanf.New(".")
 
-	// 1. Load configuration from YAML file
-	if err := k.Load(file.Provider(configPath), yaml.Parser()); err != nil {
+	// 1. Load configuration from file
+	if err := k.Load(file.Provider(configPath), parserFor(configPath)); err != nil {
 		return fmt.Errorf("error loading configuration from file %s: %w", configPath, err)
 	}
 
@@ -217,15 +429,20 @@ func LoadSection(configPath string, section string, target any, envPrefix string
 		}
 	}
 
-	// 3. Unmarshal specific section into target structure
-	if err := k.Unmarshal(section, target); err != nil {
+	// 3. Apply struct tag defaults for keys absent from both file and environment
+	if err := applyDefaults(k, section, target); err != nil {
+		return err
+	}
+
+	// 4. Unmarshal specific section into target structure
+	if err := unmarshal(k, section, target); err != nil {
 		return fmt.Errorf("error deserializing section '%s': %w", section, err)
 	}
 
 	return nil
 }
 
-// LoadSectionDefault loads a specific section from the default config.yaml file (next to the executable)
+// LoadSectionDefault loads a specific section from the default config file ($APP_CONFIG or the first existing DefaultSearchPaths entry)
 // with override via environment variables.
 // Useful when configurations for multiple services are stored in one YAML file.
 //
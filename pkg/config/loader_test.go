@@ -1,9 +1,15 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/knadh/koanf/parsers/json"
 )
 
 // TestLoad tests basic configuration loading from YAML
@@ -301,3 +307,426 @@ invalid yaml structure
 		t.Error("Load() should return error for invalid YAML")
 	}
 }
+
+// formatsConfig is the configuration loaded from equivalent files in every format
+type formatsConfig struct {
+	Name   string `koanf:"name"`
+	Server struct {
+		Host    string   `koanf:"host"`
+		Port    int      `koanf:"port"`
+		Origins []string `koanf:"origins"`
+	} `koanf:"server"`
+	Debug bool `koanf:"debug"`
+}
+
+// formatsContent holds equivalent configuration in every supported format
+var formatsContent = map[string]string{
+	"config.yaml": `
+name: point
+debug: true
+server:
+  host: localhost
+  port: 8080
+  origins: [a.example, b.example]
+`,
+	"config.json": `{
+  "name": "point",
+  "debug": true,
+  "server": {"host": "localhost", "port": 8080, "origins": ["a.example", "b.example"]}
+}`,
+	"config.toml": `
+name = "point"
+debug = true
+
+[server]
+host = "localhost"
+port = 8080
+origins = ["a.example", "b.example"]
+`,
+}
+
+// TestLoadFormats tests that the parser is detected from the file extension
+func TestLoadFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configs := make(map[string]formatsConfig)
+	for name, content := range formatsContent {
+		configPath := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+
+		var cfg formatsConfig
+		if err := Load(configPath, &cfg); err != nil {
+			t.Fatalf("Load(%s) error = %v", name, err)
+		}
+		configs[name] = cfg
+	}
+
+	yamlCfg := configs["config.yaml"]
+	if yamlCfg.Name != "point" || yamlCfg.Server.Port != 8080 || len(yamlCfg.Server.Origins) != 2 || !yamlCfg.Debug {
+		t.Fatalf("Unexpected YAML config: %+v", yamlCfg)
+	}
+	for _, name := range []string{"config.json", "config.toml"} {
+		if !reflect.DeepEqual(configs[name], yamlCfg) {
+			t.Errorf("Load(%s) = %+v, expected %+v", name, configs[name], yamlCfg)
+		}
+	}
+}
+
+// TestLoadWithParser tests loading with an explicit parser regardless of the extension
+func TestLoadWithParser(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.conf")
+
+	if err := os.WriteFile(configPath, []byte(formatsContent["config.json"]), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	var cfg formatsConfig
+	if err := LoadWithParser(configPath, &cfg, json.Parser()); err != nil {
+		t.Fatalf("LoadWithParser() error = %v", err)
+	}
+	if cfg.Server.Host != "localhost" || cfg.Server.Port != 8080 {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+// TestLoadLayered tests that later files override keys of earlier ones
+func TestLoadLayered(t *testing.T) {
+	tmpDir := t.TempDir()
+	basePath := filepath.Join(tmpDir, "config.yaml")
+	overlayPath := filepath.Join(tmpDir, "config.production.json")
+
+	baseContent := `
+name: point
+server:
+  host: localhost
+  port: 8080
+`
+	overlayContent := `{"server": {"port": 9090}}`
+	if err := os.WriteFile(basePath, []byte(baseContent), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(overlayPath, []byte(overlayContent), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	var cfg formatsConfig
+	if err := LoadLayered(&cfg, basePath, overlayPath); err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Server.Port = %v, expected 9090 from overlay", cfg.Server.Port)
+	}
+	if cfg.Name != "point" || cfg.Server.Host != "localhost" {
+		t.Errorf("Expected keys missing from overlay to keep base values, got %+v", cfg)
+	}
+
+	missingPath := filepath.Join(tmpDir, "config.local.yaml")
+	if err := LoadLayered(&cfg, basePath, missingPath); err == nil {
+		t.Error("LoadLayered() should return error for non-existent file")
+	}
+
+	cfg = formatsConfig{}
+	if err := LoadLayeredOptional(&cfg, basePath, missingPath, overlayPath); err != nil {
+		t.Fatalf("LoadLayeredOptional() error = %v", err)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Server.Port = %v, expected 9090 from overlay", cfg.Server.Port)
+	}
+
+	invalidPath := filepath.Join(tmpDir, "invalid.json")
+	if err := os.WriteFile(invalidPath, []byte(`{"server": `), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := LoadLayeredOptional(&cfg, basePath, invalidPath); err == nil {
+		t.Error("LoadLayeredOptional() should return error for invalid file")
+	}
+}
+
+// TestLoadDefaults tests precedence of struct tag defaults, file values and environment variables
+func TestLoadDefaults(t *testing.T) {
+	type Config struct {
+		Name   string `koanf:"name" default:"point"`
+		Server struct {
+			Port        int           `koanf:"port" default:"8080"`
+			ReadTimeout time.Duration `koanf:"readTimeout" default:"30s"`
+			Debug       bool          `koanf:"debug" default:"true"`
+		} `koanf:"server"`
+		Ratio float64 `koanf:"ratio" default:"0.5"`
+	}
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	yamlContent := `
+server:
+  port: 9090
+  debug: false
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	t.Setenv("DEFAULTS_SERVER_READTIMEOUT", "5s")
+
+	var cfg Config
+	if err := LoadWithPrefix(configPath, &cfg, "DEFAULTS_"); err != nil {
+		t.Fatalf("LoadWithPrefix() error = %v", err)
+	}
+
+	// Absent from file and environment
+	if cfg.Name != "point" {
+		t.Errorf("Name = %v, expected default point", cfg.Name)
+	}
+	if cfg.Ratio != 0.5 {
+		t.Errorf("Ratio = %v, expected default 0.5", cfg.Ratio)
+	}
+	// Present in file
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Server.Port = %v, expected 9090 from file", cfg.Server.Port)
+	}
+	if cfg.Server.Debug {
+		t.Errorf("Server.Debug = %v, expected false from file", cfg.Server.Debug)
+	}
+	// Present in environment
+	if cfg.Server.ReadTimeout != 5*time.Second {
+		t.Errorf("Server.ReadTimeout = %v, expected 5s from environment", cfg.Server.ReadTimeout)
+	}
+}
+
+// TestLoadInvalidDefault tests handling of a default that does not match the field type
+func TestLoadInvalidDefault(t *testing.T) {
+	type Config struct {
+		Port int `koanf:"port" default:"eighty"`
+	}
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("host: localhost\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	var cfg Config
+	if err := Load(configPath, &cfg); err == nil {
+		t.Error("Load() should return error for invalid default")
+	}
+}
+
+// TestLoadAndValidate tests validation of values from file and environment variables
+func TestLoadAndValidate(t *testing.T) {
+	type Config struct {
+		Server struct {
+			Host string `koanf:"host" validate:"required"`
+			Port int    `koanf:"port" validate:"gte=1,lte=65535"`
+		} `koanf:"server"`
+	}
+
+	load := func(t *testing.T, yamlContent string) error {
+		t.Helper()
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		var cfg Config
+		return LoadAndValidate(configPath, &cfg)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		if err := load(t, "server:\n  host: localhost\n  port: 8080\n"); err != nil {
+			t.Errorf("LoadAndValidate() error = %v", err)
+		}
+	})
+
+	t.Run("invalid in file", func(t *testing.T) {
+		err := load(t, "server:\n  port: -1\n")
+		if err == nil {
+			t.Fatal("LoadAndValidate() should return error for out-of-range port")
+		}
+		// Every invalid field is reported
+		for _, want := range []string{`server.port: value -1 does not satisfy "gte=1"`, `server.host: value "" does not satisfy "required"`} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("LoadAndValidate() error = %v, expected it to contain %q", err, want)
+			}
+		}
+	})
+
+	t.Run("invalid in environment", func(t *testing.T) {
+		t.Setenv("SERVER_PORT", "70000")
+		err := load(t, "server:\n  host: localhost\n  port: 8080\n")
+		if err == nil || !strings.Contains(err.Error(), `server.port: value 70000 does not satisfy "lte=65535"`) {
+			t.Errorf("LoadAndValidate() error = %v, expected out-of-range port from environment", err)
+		}
+	})
+}
+
+// TestWatch tests that rewriting the file reloads the configuration and fires the callback
+func TestWatch(t *testing.T) {
+	type Config struct {
+		Point struct {
+			BatchInterval int `koanf:"batchInterval"`
+		} `koanf:"point"`
+		Level string `koanf:"level"`
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("point:\n  batchInterval: 100\nlevel: info\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	var cfg Atomic[Config]
+	changes := make(chan error, 10)
+	stop, err := Watch(configPath, &cfg, func(err error) {
+		changes <- err
+	})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer stop()
+
+	if got := cfg.Load(); got.Point.BatchInterval != 100 || got.Level != "info" {
+		t.Fatalf("Unexpected initial config: %+v", got)
+	}
+
+	if err := os.WriteFile(configPath, []byte("point:\n  batchInterval: 250\nlevel: debug\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite test file: %v", err)
+	}
+
+	select {
+	case err := <-changes:
+		if err != nil {
+			t.Fatalf("onChange() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onChange was not called after the file was rewritten")
+	}
+
+	if got := cfg.Load(); got.Point.BatchInterval != 250 || got.Level != "debug" {
+		t.Errorf("Expected reloaded config, got %+v", got)
+	}
+}
+
+// TestLoadDurations tests unmarshaling of duration strings and plain seconds into time.Duration
+func TestLoadDurations(t *testing.T) {
+	type Config struct {
+		ReadTimeout     time.Duration `koanf:"readTimeout"`
+		WriteTimeout    time.Duration `koanf:"writeTimeout"`
+		IdleTimeout     time.Duration `koanf:"idleTimeout"`
+		ShutdownTimeout time.Duration `koanf:"shutdownTimeout"`
+		Unset           time.Duration `koanf:"unset"`
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	yamlContent := `
+readTimeout: 1m30s
+writeTimeout: 500ms
+idleTimeout: 120
+unset:
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	t.Setenv("DURATIONS_SHUTDOWNTIMEOUT", "15")
+
+	var cfg Config
+	if err := LoadWithPrefix(configPath, &cfg, "DURATIONS_"); err != nil {
+		t.Fatalf("LoadWithPrefix() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      time.Duration
+		expected time.Duration
+	}{
+		{"duration string", cfg.ReadTimeout, 90 * time.Second},
+		{"sub-second duration string", cfg.WriteTimeout, 500 * time.Millisecond},
+		{"plain integer seconds", cfg.IdleTimeout, 120 * time.Second},
+		{"integer seconds from environment", cfg.ShutdownTimeout, 15 * time.Second},
+		{"empty value", cfg.Unset, 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %v, expected %v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+// TestLoadDefaultMissingFile tests error and panic reporting when the default config file is missing
+func TestLoadDefaultMissingFile(t *testing.T) {
+	type Config struct {
+		Host string `koanf:"host"`
+	}
+
+	// The test binary has no config.yaml next to it
+	configPath := getDefaultConfigPath()
+
+	var cfg Config
+	if err := TryLoadDefault(&cfg); err == nil {
+		t.Error("TryLoadDefault() should return error for missing default config file")
+	}
+	if err := TryLoadWithPrefixDefault(&cfg, "APP_"); err == nil {
+		t.Error("TryLoadWithPrefixDefault() should return error for missing default config file")
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("LoadDefault() should panic for missing default config file")
+		}
+		if msg := fmt.Sprint(r); !strings.Contains(msg, "failed to load configuration from "+configPath) {
+			t.Errorf("Panic message %q should contain the config path %s", msg, configPath)
+		}
+	}()
+	LoadDefault(&cfg)
+}
+
+// TestDefaultConfigPath tests the $APP_CONFIG override and the search path fallback chain
+func TestDefaultConfigPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "first", "config.yaml")
+	second := filepath.Join(tmpDir, "second", "config.yaml")
+	third := filepath.Join(tmpDir, "third", "config.yaml")
+	for _, configPath := range []string{second, third} {
+		if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+		if err := os.WriteFile(configPath, []byte("host: "+filepath.Base(filepath.Dir(configPath))+"\n"), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	SetDefaultSearchPaths([]string{first, second, third})
+	defer SetDefaultSearchPaths(nil)
+
+	type Config struct {
+		Host string `koanf:"host"`
+	}
+
+	t.Run("first existing search path", func(t *testing.T) {
+		var cfg Config
+		if err := TryLoadDefault(&cfg); err != nil {
+			t.Fatalf("TryLoadDefault() error = %v", err)
+		}
+		if cfg.Host != "second" {
+			t.Errorf("Host = %v, expected config from the first existing path", cfg.Host)
+		}
+	})
+
+	t.Run("env override", func(t *testing.T) {
+		t.Setenv(ConfigPathEnv, third)
+		var cfg Config
+		if err := TryLoadDefault(&cfg); err != nil {
+			t.Fatalf("TryLoadDefault() error = %v", err)
+		}
+		if cfg.Host != "third" {
+			t.Errorf("Host = %v, expected config from $%s", cfg.Host, ConfigPathEnv)
+		}
+	})
+
+	t.Run("none exists", func(t *testing.T) {
+		SetDefaultSearchPaths([]string{first})
+		if got := getDefaultConfigPath(); got != first {
+			t.Errorf("getDefaultConfigPath() = %v, expected first search path %v", got, first)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Base64Bytes is a []byte that is decoded from a base64 string during unmarshalling.
+// Useful for binary secrets (e.g. signing keys) stored in YAML or environment variables.
+// Both standard and URL-safe alphabets are accepted, with or without padding.
+//
+// Example:
+//
+//	type AuthConfig struct {
+//	    SigningKey config.Base64Bytes `koanf:"signingKey"`
+//	}
+type Base64Bytes []byte
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *Base64Bytes) UnmarshalText(text []byte) error {
+	s := string(text)
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	} {
+		if decoded, err := enc.DecodeString(s); err == nil {
+			*b = decoded
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid base64 value")
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (b Base64Bytes) MarshalText() ([]byte, error) {
+	return []byte(base64.StdEncoding.EncodeToString(b)), nil
+}
+
+// HexBytes is a []byte that is decoded from a hex string during unmarshalling.
+//
+// Example:
+//
+//	type AuthConfig struct {
+//	    SigningKey config.HexBytes `koanf:"signingKey"`
+//	}
+type HexBytes []byte
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *HexBytes) UnmarshalText(text []byte) error {
+	decoded, err := hex.DecodeString(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid hex value: %w", err)
+	}
+	*b = decoded
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (b HexBytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadBinaryValues tests decoding base64 and hex values into bytes
+func TestLoadBinaryValues(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	yamlContent := `
+signingKey: c2VjcmV0LWtleQ==
+hexKey: deadbeef
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	type Config struct {
+		SigningKey Base64Bytes `koanf:"signingKey"`
+		HexKey     HexBytes    `koanf:"hexKey"`
+	}
+
+	var cfg Config
+	if err := Load(configPath, &cfg); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !bytes.Equal(cfg.SigningKey, []byte("secret-key")) {
+		t.Errorf("SigningKey = %q, expected secret-key", cfg.SigningKey)
+	}
+	if !bytes.Equal(cfg.HexKey, []byte{0xde, 0xad, 0xbe, 0xef}) {
+		t.Errorf("HexKey = %x, expected deadbeef", cfg.HexKey)
+	}
+}
+
+// TestLoadMalformedBinaryValues tests rejection of invalid encodings
+func TestLoadMalformedBinaryValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		yaml   string
+		target any
+	}{
+		{
+			name: "base64",
+			yaml: "key: not*valid*base64\n",
+			target: &struct {
+				Key Base64Bytes `koanf:"key"`
+			}{},
+		},
+		{
+			name: "hex",
+			yaml: "key: xyz\n",
+			target: &struct {
+				Key HexBytes `koanf:"key"`
+			}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.yaml), 0644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			if err := Load(configPath, tt.target); err == nil {
+				t.Error("Load() should return error for malformed value")
+			}
+		})
+	}
+}
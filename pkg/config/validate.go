@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shngxx/point/pkg/internal/fielderr"
+)
+
+// validate validates configuration structures, naming fields by their koanf keys
+var validate = newValidator()
+
+// newValidator creates a validator that reports fields by their koanf keys (e.g. "server.port")
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if name, _, _ := strings.Cut(field.Tag.Get("koanf"), ","); name != "" {
+			return name
+		}
+		return field.Name
+	})
+	return v
+}
+
+// Validate validates a loaded configuration structure using `validate:"..."` struct tags
+// (see github.com/go-playground/validator for the available rules).
+// Returns an error listing every invalid field, or nil.
+//
+// Example:
+//
+//	type ServerConfig struct {
+//	    Host string `koanf:"host" validate:"required"`
+//	    Port int    `koanf:"port" validate:"gte=1,lte=65535"`
+//	}
+func Validate(target any) error {
+	err := validate.Struct(target)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return fmt.Errorf("error validating configuration: %w", err)
+	}
+
+	errs := make([]error, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		errs = append(errs, fmt.Errorf("%s: value %#v does not satisfy %q", fielderr.Path(fieldErr), fieldErr.Value(), fielderr.Rule(fieldErr)))
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+}
+
+// LoadAndValidate loads configuration like Load and validates it with Validate.
+//
+// Example:
+//
+//	var cfg Config
+//	err := config.LoadAndValidate("config.yaml", &cfg)
+func LoadAndValidate(configPath string, target any) error {
+	if err := Load(configPath, target); err != nil {
+		return err
+	}
+	return Validate(target)
+}
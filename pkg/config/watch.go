@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/knadh/koanf/providers/file"
+)
+
+// reloadDelay is how long Watch waits after a file change before reloading,
+// so that a file being rewritten is read once it is complete
+const reloadDelay = 50 * time.Millisecond
+
+// Atomic holds the latest version of a configuration and is safe for concurrent use.
+// Pass it to Watch to let readers always see a complete snapshot of a reloaded configuration.
+//
+// Example:
+//
+//	var cfg config.Atomic[Config]
+//	stop, err := config.Watch("config.yaml", &cfg, nil)
+//	...
+//	interval := cfg.Load().Point.BatchInterval
+type Atomic[T any] struct {
+	value atomic.Pointer[T]
+}
+
+// Load returns the latest configuration (the zero value if none was stored)
+func (a *Atomic[T]) Load() T {
+	if v := a.value.Load(); v != nil {
+		return *v
+	}
+	var zero T
+	return zero
+}
+
+// Store replaces the configuration
+func (a *Atomic[T]) Store(v T) {
+	a.value.Store(&v)
+}
+
+// newSnapshot returns a pointer to a new configuration to load into
+func (a *Atomic[T]) newSnapshot() any {
+	return new(T)
+}
+
+// storeSnapshot replaces the configuration with a loaded snapshot
+func (a *Atomic[T]) storeSnapshot(snapshot any) {
+	a.value.Store(snapshot.(*T))
+}
+
+// snapshotStore is implemented by Atomic
+type snapshotStore interface {
+	newSnapshot() any
+	storeSnapshot(snapshot any)
+}
+
+// Watch loads configuration from a file into target (see Load) and reloads it whenever the file changes.
+// onChange (optional) is called after each reload with nil or the reload error; on error target keeps
+// the previous configuration. Watching stops if the file is removed (onChange receives the error).
+//
+// Each reload is loaded into a fresh value and then copied into target, so keys removed from the file
+// do not keep their old values. If target is an *Atomic[T], the new configuration is stored atomically
+// and readers always see a consistent snapshot. Otherwise target is overwritten in place and the caller
+// must synchronize access to it (e.g. read it only from onChange).
+//
+// Returns a function that stops watching, or an error if the initial load fails.
+//
+// Example:
+//
+//	var cfg config.Atomic[Config]
+//	stop, err := config.Watch("config.yaml", &cfg, func(err error) {
+//	    if err != nil {
+//	        log.Printf("config reload failed: %v", err)
+//	    }
+//	})
+//	defer stop()
+func Watch(configPath string, target any, onChange func(error)) (stop func() error, err error) {
+	typ := reflect.TypeOf(target)
+	if typ == nil || typ.Kind() != reflect.Pointer {
+		return nil, fmt.Errorf("config.Watch: target must be a pointer, got %v", typ)
+	}
+
+	var mu sync.Mutex // serializes reloads
+	reload := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if store, ok := target.(snapshotStore); ok {
+			snapshot := store.newSnapshot()
+			if err := Load(configPath, snapshot); err != nil {
+				return err
+			}
+			store.storeSnapshot(snapshot)
+			return nil
+		}
+
+		fresh := reflect.New(typ.Elem())
+		if err := Load(configPath, fresh.Interface()); err != nil {
+			return err
+		}
+		reflect.ValueOf(target).Elem().Set(fresh.Elem())
+		return nil
+	}
+
+	if err := reload(); err != nil {
+		return nil, err
+	}
+
+	notify := func(err error) {
+		if onChange != nil {
+			onChange(err)
+		}
+	}
+
+	// Rewriting a file fires several events, reload once they settle
+	var timerMu sync.Mutex
+	var timer *time.Timer
+	provider := file.Provider(configPath)
+	err = provider.Watch(func(_ any, err error) {
+		if err != nil {
+			notify(fmt.Errorf("error watching configuration file %s: %w", configPath, err))
+			return
+		}
+		timerMu.Lock()
+		defer timerMu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(reloadDelay, func() {
+			notify(reload())
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error watching configuration file %s: %w", configPath, err)
+	}
+
+	return func() error {
+		timerMu.Lock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timerMu.Unlock()
+		return provider.Unwatch()
+	}, nil
+}
@@ -1,9 +1,12 @@
 package di
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -13,7 +16,54 @@ type Container struct {
 	mu         sync.RWMutex
 	services   map[reflect.Type]any
 	singletons map[reflect.Type]any
+	supplied   map[reflect.Type]bool // types registered via Supply
 	providers  []providerInfo
+	groups     map[string]*valueGroup          // value groups registered via ProvideGroup
+	cleanups   []func() error                  // run in reverse order by Shutdown
+	owners     map[reflect.Type]*providerInfo  // constructor currently providing each type
+	named      map[namedKey]any                // values registered via SupplyNamed
+	inflight   map[*providerInfo]*providerCall // constructors being called
+	bindings   map[reflect.Type]reflect.Type   // interface -> implementation registered via Bind
+}
+
+// namedKey identifies a value registered via SupplyNamed
+type namedKey struct {
+	typ  reflect.Type
+	name string
+}
+
+// In marks a parameter struct whose fields are injected individually.
+// Embed it in a struct and accept the struct as a constructor (or Invoke) parameter:
+// fields tagged with `name:"..."` receive values registered via SupplyNamed,
+// other exported fields are resolved by type as usual.
+//
+// Example:
+//
+//	type ServersParams struct {
+//		di.In
+//		Public   http.Config `name:"public"`
+//		Internal http.Config `name:"internal"`
+//		Logger   *zerolog.Logger
+//	}
+type In struct{}
+
+// inType is the reflect.Type of In
+var inType = reflect.TypeOf(In{})
+
+// Disposable is implemented by services that release resources on shutdown.
+// Values created by a constructor registered via Provide or ProvideGroup that implement it
+// are closed by Container.Shutdown. Any io.Closer satisfies it.
+type Disposable interface {
+	Close() error
+}
+
+// valueGroup is a named collection of values of one type, resolved as a slice
+type valueGroup struct {
+	name     string
+	elemType reflect.Type
+	members  []providerInfo
+	values   any           // constructed slice, nil until first resolved
+	call     *providerCall // construction in progress, nil if none
 }
 
 // providerInfo stores information about a constructor
@@ -25,12 +75,23 @@ type providerInfo struct {
 	returnsError    bool // indicates whether the constructor returns error as the last value
 }
 
+// providerFactory creates a value via a constructor registered with Provide.
+// chain holds the types currently being constructed by the calling goroutine
+// and is used to detect circular dependencies.
+type providerFactory func(chain []reflect.Type) any
+
 // NewContainer creates a new DI container
 func NewContainer() *Container {
 	return &Container{
 		services:   make(map[reflect.Type]any),
 		singletons: make(map[reflect.Type]any),
+		supplied:   make(map[reflect.Type]bool),
 		providers:  make([]providerInfo, 0),
+		groups:     make(map[string]*valueGroup),
+		owners:     make(map[reflect.Type]*providerInfo),
+		named:      make(map[namedKey]any),
+		inflight:   make(map[*providerInfo]*providerCall),
+		bindings:   make(map[reflect.Type]reflect.Type),
 	}
 }
 
@@ -50,6 +111,16 @@ func (c *Container) RegisterSingleton(serviceType reflect.Type, instance any) {
 
 // resolve retrieves a service from the container (private method)
 func (c *Container) resolve(serviceType reflect.Type) (any, error) {
+	return c.resolveChain(serviceType, nil)
+}
+
+// resolveChain retrieves a service while the types in chain are being constructed (private method)
+func (c *Container) resolveChain(serviceType reflect.Type, chain []reflect.Type) (any, error) {
+	// Parameter structs are assembled from their fields
+	if isParamStruct(serviceType) {
+		return c.resolveParamStruct(serviceType, chain)
+	}
+
 	c.mu.RLock()
 
 	// Check singleton
@@ -65,54 +136,124 @@ func (c *Container) resolve(serviceType reflect.Type) (any, error) {
 	if !ok {
 		// If an interface is requested, try to find an implementation
 		if serviceType.Kind() == reflect.Interface {
-			return c.resolveInterface(serviceType)
+			return c.resolveInterface(serviceType, chain)
+		}
+		// Slices of a value group's element type resolve to the group's values
+		if serviceType.Kind() == reflect.Slice {
+			if values, ok := c.resolveGroup(serviceType, chain); ok {
+				return values, nil
+			}
+		}
+		// Collections are never assembled implicitly from their element types
+		if kind := serviceType.Kind(); kind == reflect.Slice || kind == reflect.Map {
+			return nil, fmt.Errorf("service of type %v is not registered (register a value group for %v or provide a constructor returning it)", serviceType, serviceType)
 		}
 		return nil, fmt.Errorf("service of type %v is not registered (use container.Supply() or container.Provide() to register it)", serviceType)
 	}
 
 	// Call factory
-	factoryFunc := factory.(func() any)
-	return factoryFunc(), nil
+	return callFactory(factory, chain), nil
 }
 
-// resolveInterface attempts to find an interface implementation among registered types (private method)
-func (c *Container) resolveInterface(interfaceType reflect.Type) (any, error) {
-	c.mu.RLock()
+// callFactory calls a factory registered via Register or Provide
+func callFactory(factory any, chain []reflect.Type) any {
+	if f, ok := factory.(providerFactory); ok {
+		return f(chain)
+	}
+	return factory.(func() any)()
+}
 
-	// Search among singletons
-	for implType, instance := range c.singletons {
-		if implType.Implements(interfaceType) {
+// resolveInterface resolves an interface to its bound implementation (see Bind), or to the
+// only registered type implementing it (private method)
+func (c *Container) resolveInterface(interfaceType reflect.Type, chain []reflect.Type) (any, error) {
+	c.mu.RLock()
+	implType, bound := c.bindings[interfaceType]
+	if !bound {
+		implTypes := c.implementations(interfaceType)
+		switch len(implTypes) {
+		case 0:
+			c.mu.RUnlock()
+			return nil, fmt.Errorf("no implementation found for interface %v (register a type that implements this interface using container.Supply() or container.Provide())", interfaceType)
+		case 1:
+			implType = implTypes[0]
+		default:
 			c.mu.RUnlock()
-			return instance, nil
+			return nil, fmt.Errorf("interface %v has several implementations (%s), choose one with container.Bind()", interfaceType, typeNames(implTypes))
 		}
 	}
+	c.mu.RUnlock()
 
-	// Search among registered services
-	var factory func() any
-	var found bool
-	for implType, f := range c.services {
-		if implType.Implements(interfaceType) {
-			factory = f.(func() any)
-			found = true
-			break
+	// Resolve the implementation outside of lock
+	return c.resolveChain(implType, chain)
+}
+
+// implementations returns the registered types implementing an interface, sorted by name (private method).
+// Must be called with c.mu held.
+func (c *Container) implementations(interfaceType reflect.Type) []reflect.Type {
+	seen := make(map[reflect.Type]bool)
+	var implTypes []reflect.Type
+	for _, types := range []map[reflect.Type]any{c.singletons, c.services} {
+		for implType := range types {
+			// A provided value is cached as a singleton, so a type can be in both maps
+			if !seen[implType] && implType.Implements(interfaceType) {
+				seen[implType] = true
+				implTypes = append(implTypes, implType)
+			}
 		}
 	}
-	c.mu.RUnlock()
+	sort.Slice(implTypes, func(i, j int) bool {
+		return implTypes[i].String() < implTypes[j].String()
+	})
+	return implTypes
+}
 
-	if !found {
-		return nil, fmt.Errorf("no implementation found for interface %v (register a type that implements this interface using container.Supply() or container.Provide())", interfaceType)
+// typeNames returns a comma-separated list of type names
+func typeNames(types []reflect.Type) string {
+	names := make([]string, len(types))
+	for i, typ := range types {
+		names[i] = typ.String()
 	}
+	return strings.Join(names, ", ")
+}
 
-	// Call factory outside of lock
-	instance := factory()
-	return instance, nil
+// Bind makes an interface resolve to a registered implementation type.
+// Without a binding, an interface resolves to the only registered type implementing it,
+// and resolving an interface with several implementations is an error.
+// Both arguments are typed nil pointers: the interface as a pointer to it,
+// the implementation as a value of its type.
+//
+// Example:
+//   - container.Bind((*point.PointRepository)(nil), (*db.PointRepository)(nil))
+//
+// Panics on errors.
+func (c *Container) Bind(ifacePtr any, implType any) {
+	ptrType := reflect.TypeOf(ifacePtr)
+	if ptrType == nil || ptrType.Kind() != reflect.Pointer || ptrType.Elem().Kind() != reflect.Interface {
+		panic(fmt.Errorf("Bind: %v is not a pointer to an interface, use (*Interface)(nil)", ptrType))
+	}
+	interfaceType := ptrType.Elem()
+
+	typ := reflect.TypeOf(implType)
+	if typ == nil {
+		panic(fmt.Errorf("Bind: implementation of %v cannot be untyped nil", interfaceType))
+	}
+	if !typ.Implements(interfaceType) {
+		panic(fmt.Errorf("Bind: %v does not implement %v", typ, interfaceType))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if bound, exists := c.bindings[interfaceType]; exists {
+		panic(fmt.Errorf("Bind: %v is already bound to %v", interfaceType, bound))
+	}
+	c.bindings[interfaceType] = typ
 }
 
 // mustResolve retrieves a service from the container, panics on error (private method)
 func (c *Container) mustResolve(serviceType reflect.Type) any {
 	instance, err := c.resolve(serviceType)
 	if err != nil {
-		panic(err)
+		panic(resolveFailure{err})
 	}
 	return instance
 }
@@ -126,6 +267,88 @@ func MustResolve[T any](container *Container) T {
 	return instance.(T)
 }
 
+// Resolve retrieves a service from the container by type.
+// Unlike MustResolve, it returns an error instead of panicking when the service
+// is not registered or cannot be constructed (e.g. a constructor returned an error).
+// Panics of constructors themselves (e.g. a nil dereference) are not recovered.
+func Resolve[T any](container *Container) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = resolveError(r)
+		}
+	}()
+
+	typ := reflect.TypeOf(&result).Elem()
+	instance, err := container.resolve(typ)
+	if err != nil {
+		return result, err
+	}
+	return instance.(T), nil
+}
+
+// Invoke calls fn with its parameters resolved from the container.
+// fn may return an error as its last value, which Invoke returns; other results are discarded.
+//
+// Example:
+//   - container.Invoke(func(server *http.Server, handler *ws.Handler) { ... })
+//
+// Unlike Provide, Invoke returns errors instead of panicking: resolution failures
+// are wrapped with the parameter index and type, as for constructors.
+func (c *Container) Invoke(fn any) error {
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return fmt.Errorf("Invoke: expected a function, got %v", fnType)
+	}
+
+	fnName := getFunctionName(fn)
+	if fnName == "" {
+		fnName = "function"
+	}
+
+	args, err := c.invokeArgs(fnType, fnName)
+	if err != nil {
+		return err
+	}
+
+	// Panics of fn itself are not recovered
+	results := reflect.ValueOf(fn).Call(args)
+
+	// Return the error if fn returns it as the last value
+	errorInterface := reflect.TypeOf((*error)(nil)).Elem()
+	if numOut := fnType.NumOut(); numOut > 0 && fnType.Out(numOut-1).Implements(errorInterface) {
+		if errorValue := results[numOut-1]; !errorValue.IsNil() {
+			return errorValue.Interface().(error)
+		}
+	}
+	return nil
+}
+
+// invokeArgs resolves the arguments of a function called by Invoke
+func (c *Container) invokeArgs(fnType reflect.Type, fnName string) (args []reflect.Value, err error) {
+	// Constructors called while resolving report failures by panicking
+	defer func() {
+		if r := recover(); r != nil {
+			err = resolveError(r)
+		}
+	}()
+
+	numIn := fnType.NumIn()
+	args = make([]reflect.Value, numIn)
+	for i := range numIn {
+		paramType := fnType.In(i)
+		instance, err := c.resolve(paramType)
+		if err != nil {
+			paramName := fmt.Sprintf("parameter #%d", i+1)
+			if numIn == 1 {
+				paramName = "parameter"
+			}
+			return nil, fmt.Errorf("Invoke: %s requires %s of type %v, but: %w", fnName, paramName, paramType, err)
+		}
+		args[i] = reflect.ValueOf(instance)
+	}
+	return args, nil
+}
+
 // Supply registers ready values as singletons in the container.
 // Unlike Provide, Supply accepts values directly, not constructors.
 // Used for configuration, constants, and other ready values.
@@ -135,6 +358,8 @@ func MustResolve[T any](container *Container) T {
 //   - container.Supply(appConfig, redisConfig, serverConfig)
 //
 // Values are registered by their type and available for injection into constructors.
+// A type cannot be both supplied and provided by a constructor, since the supplied
+// value would silently shadow the constructor.
 // Panics on errors.
 func (c *Container) Supply(values ...any) {
 	c.mu.Lock()
@@ -157,9 +382,221 @@ func (c *Container) Supply(values ...any) {
 			panic(fmt.Errorf("Supply: value of type %v is already registered", valueType))
 		}
 
+		// Check that no constructor provides this type (the supplied value would shadow it)
+		if name, ok := c.providerNameFor(valueType); ok {
+			panic(fmt.Errorf("Supply: value %#v of type %v conflicts with constructor %s registered via Provide", value, valueType, name))
+		}
+
 		// Register value as singleton
 		c.singletons[valueType] = value
+		c.supplied[valueType] = true
+	}
+}
+
+// Validate checks that every parameter of every registered constructor can be resolved,
+// without calling any constructor. A parameter is resolvable if its type is registered
+// (via Supply, Provide or Register), is an interface with a registered implementation,
+// is a slice of a value group's element type, or is a parameter struct (see In) whose
+// fields are resolvable.
+// Returns an error listing every unsatisfiable constructor parameter, or nil.
+// Call it after registration to fail fast with the complete list of missing dependencies.
+func (c *Container) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Constructors replaced for all their types by OverrideProvider are never called
+	var constructors []*providerInfo
+	seen := make(map[*providerInfo]bool)
+	for i := range c.providers {
+		for _, rt := range c.providers[i].returnTypes {
+			if owner := c.owners[rt]; owner != nil && !seen[owner] {
+				seen[owner] = true
+				constructors = append(constructors, owner)
+			}
+		}
+	}
+	// Group members are checked in group name order for a stable report
+	groupNames := make([]string, 0, len(c.groups))
+	for name := range c.groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		for i := range c.groups[name].members {
+			constructors = append(constructors, &c.groups[name].members[i])
+		}
+	}
+
+	var errs []error
+	for _, info := range constructors {
+		for i, paramType := range info.paramTypes {
+			problem := c.unresolvable(paramType)
+			if problem == "" {
+				continue
+			}
+			paramName := fmt.Sprintf("parameter #%d", i+1)
+			if len(info.paramTypes) == 1 {
+				paramName = "parameter"
+			}
+			errs = append(errs, fmt.Errorf("%s requires %s of type %v, but %s", info.constructorName, paramName, paramType, problem))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unsatisfied dependencies:\n%w", errors.Join(errs...))
+}
+
+// unresolvable describes why a type cannot be resolved, or returns "" if it can (private method).
+// Must be called with c.mu held.
+func (c *Container) unresolvable(typ reflect.Type) string {
+	if isParamStruct(typ) {
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			if field.Anonymous && field.Type == inType {
+				continue
+			}
+			if name, ok := field.Tag.Lookup("name"); ok {
+				if _, exists := c.named[namedKey{typ: field.Type, name: name}]; !exists {
+					return fmt.Sprintf("field %s: %v named %q is not registered", field.Name, field.Type, name)
+				}
+				continue
+			}
+			if problem := c.unresolvable(field.Type); problem != "" {
+				return fmt.Sprintf("field %s: %s", field.Name, problem)
+			}
+		}
+		return ""
+	}
+
+	if _, ok := c.singletons[typ]; ok {
+		return ""
+	}
+	if _, ok := c.services[typ]; ok {
+		return ""
+	}
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		if implType, ok := c.bindings[typ]; ok {
+			if problem := c.unresolvable(implType); problem != "" {
+				return fmt.Sprintf("its bound implementation %v: %s", implType, problem)
+			}
+			return ""
+		}
+		switch implTypes := c.implementations(typ); len(implTypes) {
+		case 0:
+			return "no implementation is registered"
+		case 1:
+			return ""
+		default:
+			return fmt.Sprintf("several implementations are registered (%s), choose one with Bind", typeNames(implTypes))
+		}
+	case reflect.Slice:
+		for _, g := range c.groups {
+			if g.elemType == typ.Elem() {
+				return ""
+			}
+		}
+	}
+	return "it is not registered"
+}
+
+// SupplyNamed registers a ready value under a name.
+// Named values live apart from values registered by type: a type may have any number
+// of named values plus one unnamed registration (via Supply or Provide), and resolving
+// by type never returns a named value. Named values are resolved with ResolveNamed
+// or injected into fields of a parameter struct (see In) tagged with `name:"..."`.
+//
+// Example:
+//   - container.SupplyNamed("public", http.Config{Port: 8080})
+//   - container.SupplyNamed("internal", http.Config{Port: 9090})
+//
+// Panics on errors.
+func (c *Container) SupplyNamed(name string, value any) {
+	if name == "" {
+		panic(fmt.Errorf("SupplyNamed: name cannot be empty, use Supply for unnamed values"))
+	}
+	if value == nil {
+		panic(fmt.Errorf("SupplyNamed: value cannot be nil"))
+	}
+
+	valueType := reflect.TypeOf(value)
+	if valueType.Kind() == reflect.Func {
+		panic(fmt.Errorf("SupplyNamed: cannot accept functions"))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := namedKey{typ: valueType, name: name}
+	if _, exists := c.named[key]; exists {
+		panic(fmt.Errorf("SupplyNamed: value of type %v named %q is already registered", valueType, name))
+	}
+	c.named[key] = value
+}
+
+// resolveNamed retrieves a value registered via SupplyNamed (private method)
+func (c *Container) resolveNamed(serviceType reflect.Type, name string) (any, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	instance, ok := c.named[namedKey{typ: serviceType, name: name}]
+	if !ok {
+		return nil, fmt.Errorf("service of type %v named %q is not registered (use container.SupplyNamed() to register it)", serviceType, name)
+	}
+	return instance, nil
+}
+
+// ResolveNamed retrieves a value registered via SupplyNamed by type and name.
+func ResolveNamed[T any](container *Container, name string) (result T, err error) {
+	typ := reflect.TypeOf(&result).Elem()
+	instance, err := container.resolveNamed(typ, name)
+	if err != nil {
+		return result, err
+	}
+	return instance.(T), nil
+}
+
+// isParamStruct reports whether typ is a struct embedding In
+func isParamStruct(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type == inType {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveParamStruct creates a parameter struct, resolving each of its fields (private method)
+func (c *Container) resolveParamStruct(structType reflect.Type, chain []reflect.Type) (any, error) {
+	value := reflect.New(structType).Elem()
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if field.Anonymous && field.Type == inType {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("field %s of %v must be exported to be injected", field.Name, structType)
+		}
+
+		var instance any
+		var err error
+		if name, ok := field.Tag.Lookup("name"); ok {
+			instance, err = c.resolveNamed(field.Type, name)
+		} else {
+			instance, err = c.resolveChain(field.Type, chain)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %v: %w", field.Name, structType, err)
+		}
+		value.Field(i).Set(reflect.ValueOf(instance))
 	}
+	return value.Interface(), nil
 }
 
 // Provide registers constructors for automatic dependency creation.
@@ -173,6 +610,8 @@ func (c *Container) Supply(values ...any) {
 //
 // Registration order doesn't matter. Constructors are called only if their types are needed.
 // Results are cached (singleton within the container).
+// Results implementing Disposable are closed by Shutdown.
+// Returning a type that was already registered via Supply is reported as a conflict.
 // Panics on errors.
 func (c *Container) Provide(constructors ...any) {
 	for _, constructor := range constructors {
@@ -185,9 +624,86 @@ func (c *Container) provideOne(constructor any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	info := newProviderInfo("Provide", constructor)
+	constructorName, returnTypes := info.constructorName, info.returnTypes
+
+	// Check that none of the provided types was already supplied as a ready value
+	// (the supplied singleton would silently shadow this constructor)
+	for _, returnType := range returnTypes {
+		if c.supplied[returnType] {
+			panic(fmt.Errorf("Provide: constructor %s returns %v, which conflicts with value %#v registered via Supply",
+				constructorName, returnType, c.singletons[returnType]))
+		}
+	}
+
+	// Save constructor information
+	c.providers = append(c.providers, info)
+	c.registerProvider(&info)
+}
+
+// registerProvider registers factories for each return type of a constructor
+// Must be called with c.mu held
+func (c *Container) registerProvider(info *providerInfo) {
+	for idx, returnType := range info.returnTypes {
+		// Create closure for each type (copy index and type to local variables)
+		rt := returnType
+		index := idx
+		c.services[rt] = providerFactory(func(chain []reflect.Type) any {
+			return c.invokeProviderForType(info, index, rt, chain)
+		})
+		c.owners[rt] = info
+	}
+}
+
+// Override replaces the registration of the value's type with the value.
+// Any constructor or value registered for the type and any cached instance are discarded,
+// so the next resolve returns the value. Services already constructed with the previous
+// instance keep it, so override before resolving dependents.
+//
+// Override is intended for tests, e.g. replacing a real dependency with a mock.
+// Panics on errors.
+func (c *Container) Override(value any) {
+	if value == nil {
+		panic(fmt.Errorf("Override: value cannot be nil"))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	valueType := reflect.TypeOf(value)
+	delete(c.services, valueType)
+	delete(c.owners, valueType)
+	c.singletons[valueType] = value
+	c.supplied[valueType] = true
+}
+
+// OverrideProvider replaces the registrations of the constructor's return types with the constructor.
+// Any constructor or value registered for these types and any cached instances are discarded,
+// so the next resolve calls the new constructor. To replace the implementation resolved for an
+// interface, return the interface type itself, e.g. func() point.PointRepository { return mock }.
+//
+// OverrideProvider is intended for tests, e.g. replacing a real dependency with a mock.
+// Panics on errors.
+func (c *Container) OverrideProvider(constructor any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info := newProviderInfo("OverrideProvider", constructor)
+	for _, returnType := range info.returnTypes {
+		delete(c.singletons, returnType)
+		delete(c.supplied, returnType)
+	}
+
+	c.providers = append(c.providers, info)
+	c.registerProvider(&info)
+}
+
+// newProviderInfo analyzes a constructor passed to Provide or ProvideGroup (caller)
+// Panics if it is not a valid constructor
+func newProviderInfo(caller string, constructor any) providerInfo {
 	constructorType := reflect.TypeOf(constructor)
 	if constructorType.Kind() != reflect.Func {
-		panic(fmt.Errorf("Provide: constructor must be a function"))
+		panic(fmt.Errorf("%s: constructor must be a function", caller))
 	}
 
 	// Analyze parameters (dependencies)
@@ -200,7 +716,7 @@ func (c *Container) provideOne(constructor any) {
 	// Analyze return values (provided services)
 	numOut := constructorType.NumOut()
 	if numOut == 0 {
-		panic(fmt.Errorf("Provide: constructor must return at least one value"))
+		panic(fmt.Errorf("%s: constructor must return at least one value", caller))
 	}
 
 	// Check if error is returned as the last value
@@ -222,7 +738,7 @@ func (c *Container) provideOne(constructor any) {
 	}
 
 	if len(returnTypes) == 0 {
-		panic(fmt.Errorf("Provide: constructor must return at least one non-error type"))
+		panic(fmt.Errorf("%s: constructor must return at least one non-error type", caller))
 	}
 
 	// Get constructor name for better error messages
@@ -236,30 +752,33 @@ func (c *Container) provideOne(constructor any) {
 		}
 	}
 
-	// Save constructor information
-	info := providerInfo{
+	return providerInfo{
 		constructor:     reflect.ValueOf(constructor),
 		constructorName: constructorName,
 		paramTypes:      paramTypes,
 		returnTypes:     returnTypes,
 		returnsError:    returnsError,
 	}
-	c.providers = append(c.providers, info)
+}
 
-	// Register factories for each return type
-	for idx, returnType := range returnTypes {
-		// Create closure for each type (copy index and type to local variables)
-		rt := returnType
-		index := idx
-		c.services[rt] = func() any {
-			return c.invokeProviderForType(info, index, rt)
+// providerNameFor returns the name of the constructor that provides the given type (private method).
+// Must be called with c.mu held.
+func (c *Container) providerNameFor(typ reflect.Type) (string, bool) {
+	for _, info := range c.providers {
+		for _, rt := range info.returnTypes {
+			if rt == typ {
+				return info.constructorName, true
+			}
 		}
 	}
+	return "", false
 }
 
-// invokeProviderForType invokes the constructor and returns a value of the required type
-func (c *Container) invokeProviderForType(info providerInfo, returnIndex int, returnType reflect.Type) any {
-	// Double-checked locking for thread-safe singleton creation
+// invokeProviderForType invokes the constructor and returns a value of the required type.
+// The constructor is called at most once at a time: concurrent callers wait for the
+// in-flight call instead of calling it again, so each singleton is built exactly once.
+// c.mu is never held while dependencies are resolved or the constructor runs.
+func (c *Container) invokeProviderForType(info *providerInfo, returnIndex int, returnType reflect.Type, chain []reflect.Type) any {
 	c.mu.RLock()
 	if instance, ok := c.singletons[returnType]; ok {
 		c.mu.RUnlock()
@@ -267,37 +786,128 @@ func (c *Container) invokeProviderForType(info providerInfo, returnIndex int, re
 	}
 	c.mu.RUnlock()
 
-	// Lock for writing to create
-	c.mu.Lock()
+	// A constructor that is already being called by this goroutine would wait for itself
+	for i, typ := range chain {
+		if slices.Contains(info.returnTypes, typ) {
+			panic(resolveFailure{fmt.Errorf("circular dependency detected: %s", formatChain(append(chain[i:len(chain):len(chain)], returnType)))})
+		}
+	}
 
-	// Check again (in case another thread already created it)
+	c.mu.Lock()
 	if instance, ok := c.singletons[returnType]; ok {
 		c.mu.Unlock()
 		return instance
 	}
+	if call, ok := c.inflight[info]; ok {
+		c.mu.Unlock()
+		return call.wait(returnIndex)
+	}
+	call := &providerCall{done: make(chan struct{})}
+	c.inflight[info] = call
+	c.mu.Unlock()
+
+	// Release waiters even if the constructor fails; the next resolve calls it again
+	defer func() {
+		if r := recover(); r != nil {
+			call.failure = r
+		}
+		c.mu.Lock()
+		delete(c.inflight, info)
+		c.mu.Unlock()
+		close(call.done)
+		if call.failure != nil {
+			panic(call.failure)
+		}
+	}()
+
+	results := c.callConstructor(*info, returnType, chain)
 
-	// Resolve dependencies (temporarily unlock mutex)
+	c.mu.Lock()
+	// Register all return values as singletons
+	for i, result := range results {
+		rt := info.returnTypes[i]
+		// Skip types that were overridden by another registration
+		if c.owners[rt] != info {
+			continue
+		}
+		if _, exists := c.singletons[rt]; !exists {
+			c.singletons[rt] = result.Interface()
+			c.registerDisposable(result)
+		}
+	}
+	c.mu.Unlock()
+
+	call.results = results
+	return results[returnIndex].Interface()
+}
+
+// resolveFailure is the panic value of the container's own resolution failures
+// (unregistered services, circular dependencies, constructors returning errors),
+// which Resolve and Invoke turn into errors. Other panics, e.g. a nil dereference
+// in a constructor, are bugs and propagate unchanged.
+type resolveFailure struct {
+	error
+}
+
+// Unwrap returns the resolution error
+func (f resolveFailure) Unwrap() error {
+	return f.error
+}
+
+// resolveError returns the error of a recovered resolveFailure and re-panics any other value
+func resolveError(r any) error {
+	if failure, ok := r.(resolveFailure); ok {
+		return failure.error
+	}
+	panic(r)
+}
+
+// providerCall is an in-flight constructor call that other goroutines can wait for
+type providerCall struct {
+	done    chan struct{}
+	results []reflect.Value
+	failure any // recovered panic of the constructor call
+}
+
+// wait waits for the call to finish and returns its result at index
+// Panics with the call's failure if the constructor failed
+func (call *providerCall) wait(index int) any {
+	<-call.done
+	if call.failure != nil {
+		panic(call.failure)
+	}
+	return call.results[index].Interface()
+}
+
+// callConstructor resolves the constructor's dependencies and calls it, returning its results
+// without the error. target is the type being constructed, used for error messages.
+// chain holds the types being constructed by the calling goroutine; requesting one of them
+// again means the constructors depend on each other, which is reported instead of recursing forever.
+// Must be called without c.mu held. Panics on errors.
+func (c *Container) callConstructor(info providerInfo, target reflect.Type, chain []reflect.Type) []reflect.Value {
+	// Detect circular dependencies
+	for i, typ := range chain {
+		if typ == target {
+			panic(resolveFailure{fmt.Errorf("circular dependency detected: %s", formatChain(append(chain[i:len(chain):len(chain)], target)))})
+		}
+	}
+	chain = append(chain[:len(chain):len(chain)], target)
+
+	// Resolve dependencies
 	args := make([]reflect.Value, len(info.paramTypes))
 	for i, paramType := range info.paramTypes {
-		// Temporarily unlock for dependency resolution
-		c.mu.Unlock()
-		instance, err := c.resolve(paramType)
-		c.mu.Lock()
+		instance, err := c.resolveChain(paramType, chain)
 		if err != nil {
-			c.mu.Unlock() // Unlock before panic
 			paramName := fmt.Sprintf("parameter #%d", i+1)
 			if len(info.paramTypes) == 1 {
 				paramName = "parameter"
 			}
-			panic(fmt.Errorf("%s (%s) requires %s of type %v, but: %w",
-				info.constructorName, returnType, paramName, paramType, err))
+			panic(resolveFailure{fmt.Errorf("%s (%s) requires %s of type %v, but: %w",
+				info.constructorName, target, paramName, paramType, err)})
 		}
 		args[i] = reflect.ValueOf(instance)
 	}
 
-	// Unlock before calling constructor to avoid deadlock
-	c.mu.Unlock()
-
 	// Call constructor
 	results := info.constructor.Call(args)
 
@@ -307,30 +917,183 @@ func (c *Container) invokeProviderForType(info providerInfo, returnIndex int, re
 		if !errorValue.IsNil() {
 			// Constructor returned an error
 			err := errorValue.Interface().(error)
-			panic(fmt.Errorf("%s returned error: %w", info.constructorName, err))
+			panic(resolveFailure{fmt.Errorf("%s returned error: %w", info.constructorName, err)})
 		}
 		// Remove error from results
 		results = results[:len(results)-1]
 	}
 
-	// Lock again to save results
+	return results
+}
+
+// ProvideGroup registers constructors as members of a named value group.
+// Each constructor returns a single value (optionally with an error); all members
+// of a group must return the same type T. A constructor parameter of type []T
+// (or Resolve[[]T]) receives the values of all members in registration order.
+//
+// Group members are not resolvable individually as T. Each element type may belong
+// to a single group, so that []T is unambiguous; a group may be extended by calling
+// ProvideGroup again with the same name.
+//
+// Example:
+//   - container.ProvideGroup("middleware", NewRequestID, NewLogger, NewRecover)
+//   - func NewServer(mw []middleware.Handler) *Server
+//
+// Panics on errors.
+func (c *Container) ProvideGroup(group string, constructors ...any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Register all return values as singletons
-	for i, result := range results {
-		rt := info.returnTypes[i]
-		// Check if someone created a singleton while we were calling the constructor
-		if _, exists := c.singletons[rt]; !exists {
-			c.singletons[rt] = result.Interface()
+	for _, constructor := range constructors {
+		info := newProviderInfo("ProvideGroup", constructor)
+		if len(info.returnTypes) != 1 {
+			panic(fmt.Errorf("ProvideGroup: constructor %s must return exactly one non-error value", info.constructorName))
+		}
+		elemType := info.returnTypes[0]
+
+		g, exists := c.groups[group]
+		if !exists {
+			for _, other := range c.groups {
+				if other.elemType == elemType {
+					panic(fmt.Errorf("ProvideGroup: constructor %s returns %v, which already belongs to group %q", info.constructorName, elemType, other.name))
+				}
+			}
+			g = &valueGroup{name: group, elemType: elemType}
+			c.groups[group] = g
 		}
+		if g.elemType != elemType {
+			panic(fmt.Errorf("ProvideGroup: constructor %s returns %v, but group %q contains %v", info.constructorName, elemType, group, g.elemType))
+		}
+		if g.values != nil {
+			panic(fmt.Errorf("ProvideGroup: group %q has already been resolved", group))
+		}
+
+		g.members = append(g.members, info)
 	}
+}
 
-	// Return value of the required type
-	if returnIndex < len(results) {
-		return results[returnIndex].Interface()
+// resolveGroup returns the values of the group whose element type is the element type of
+// sliceType, constructing them on first use (private method). Reports false if there is no such group.
+func (c *Container) resolveGroup(sliceType reflect.Type, chain []reflect.Type) (any, bool) {
+	c.mu.RLock()
+	var g *valueGroup
+	for _, candidate := range c.groups {
+		if candidate.elemType == sliceType.Elem() {
+			g = candidate
+			break
+		}
 	}
-	return nil
+	if g == nil {
+		c.mu.RUnlock()
+		return nil, false
+	}
+	if g.values != nil {
+		c.mu.RUnlock()
+		return g.values, true
+	}
+	c.mu.RUnlock()
+
+	// A group that is already being constructed by this goroutine would wait for itself
+	for i, typ := range chain {
+		if typ == sliceType {
+			panic(resolveFailure{fmt.Errorf("circular dependency detected: %s", formatChain(append(chain[i:len(chain):len(chain)], sliceType)))})
+		}
+	}
+
+	// The members are constructed at most once at a time, like providers (see invokeProviderForType)
+	c.mu.Lock()
+	if g.values != nil {
+		c.mu.Unlock()
+		return g.values, true
+	}
+	if call := g.call; call != nil {
+		c.mu.Unlock()
+		return call.wait(0), true
+	}
+	call := &providerCall{done: make(chan struct{})}
+	g.call = call
+	members := g.members
+	c.mu.Unlock()
+
+	// Release waiters even if a constructor fails; the next resolve constructs the group again
+	defer func() {
+		if r := recover(); r != nil {
+			call.failure = r
+		}
+		c.mu.Lock()
+		g.call = nil
+		c.mu.Unlock()
+		close(call.done)
+		if call.failure != nil {
+			panic(call.failure)
+		}
+	}()
+
+	// Construct members outside of lock, in registration order
+	values := reflect.MakeSlice(sliceType, 0, len(members))
+	for _, member := range members {
+		results := c.callConstructor(member, sliceType, chain)
+		values = reflect.Append(values, results[0])
+	}
+
+	c.mu.Lock()
+	g.values = values.Interface()
+	for i := 0; i < values.Len(); i++ {
+		c.registerDisposable(values.Index(i))
+	}
+	c.mu.Unlock()
+
+	call.results = []reflect.Value{values}
+	return g.values, true
+}
+
+// RegisterCleanup registers a function to be called by Shutdown.
+// Cleanups run in reverse registration order, so services registered later
+// (which may depend on earlier ones) are torn down first.
+func (c *Container) RegisterCleanup(fn func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cleanups = append(c.cleanups, fn)
+}
+
+// registerDisposable registers the Close method of a constructed value as a cleanup
+// Must be called with c.mu held
+func (c *Container) registerDisposable(value reflect.Value) {
+	// Constructors returning an interface may return a nil value
+	if !value.IsValid() || (value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer) && value.IsNil() {
+		return
+	}
+	if disposable, ok := value.Interface().(Disposable); ok {
+		c.cleanups = append(c.cleanups, disposable.Close)
+	}
+}
+
+// Shutdown runs the registered cleanups in reverse registration order.
+// Constructed values implementing Disposable are registered when they are created,
+// so dependents are closed before their dependencies. Every cleanup runs even if
+// earlier ones fail; their errors are joined. Cleanups run at most once.
+func (c *Container) Shutdown() error {
+	c.mu.Lock()
+	cleanups := c.cleanups
+	c.cleanups = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if err := cleanups[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// formatChain formats a dependency chain as "*A -> *B -> *A"
+func formatChain(chain []reflect.Type) string {
+	names := make([]string, len(chain))
+	for i, typ := range chain {
+		names[i] = typ.String()
+	}
+	return strings.Join(names, " -> ")
 }
 
 // getFunctionName extracts the function name from a function value
@@ -2,9 +2,18 @@ package di_test
 
 import (
 	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/gofiber/fiber/v2"
 	"github.com/shngxx/point/pkg/di"
+	"github.com/shngxx/point/pkg/http"
+	"github.com/shngxx/point/pkg/http/middleware"
 )
 
 // Example 1: Simple constructor without dependencies
@@ -234,3 +243,627 @@ func TestProvide_SingletonBehavior(t *testing.T) {
 		t.Errorf("Expected Value=1, got %d", counter1.Value)
 	}
 }
+
+// Example 8: Supplying and providing the same type is reported as a conflict
+func TestSupplyProvideConflict(t *testing.T) {
+	type Logger struct {
+		Name string
+	}
+
+	newLogger := func() *Logger {
+		return &Logger{Name: "provided"}
+	}
+
+	assertConflict := func(t *testing.T, register func(*di.Container)) {
+		t.Helper()
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("Expected panic on Supply/Provide conflict")
+			}
+			msg := fmt.Sprint(r)
+			if !strings.Contains(msg, "conflicts with") || !strings.Contains(msg, "*di_test.Logger") {
+				t.Errorf("Unexpected panic message: %s", msg)
+			}
+			if !strings.Contains(msg, "supplied") {
+				t.Errorf("Panic message should name the supplied value: %s", msg)
+			}
+		}()
+		register(di.NewContainer())
+	}
+
+	t.Run("Provide after Supply", func(t *testing.T) {
+		assertConflict(t, func(c *di.Container) {
+			c.Supply(&Logger{Name: "supplied"})
+			c.Provide(newLogger)
+		})
+	})
+
+	t.Run("Supply after Provide", func(t *testing.T) {
+		assertConflict(t, func(c *di.Container) {
+			c.Provide(newLogger)
+			c.Supply(&Logger{Name: "supplied"})
+		})
+	})
+}
+
+// Example 9: Resolving an unregistered collection type explains how to register it
+func TestResolve_UnregisteredSlice(t *testing.T) {
+	type Handler func() error
+
+	container := di.NewContainer()
+	container.Provide(func() Handler {
+		return func() error { return nil }
+	})
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic for unregistered slice type")
+		}
+		msg := fmt.Sprint(r)
+		if !strings.Contains(msg, "register a value group for []di_test.Handler") {
+			t.Errorf("Unexpected panic message: %s", msg)
+		}
+	}()
+
+	di.MustResolve[[]Handler](container)
+}
+
+// Example 10: Mutually dependent constructors are reported as a circular dependency
+func TestProvide_CircularDependency(t *testing.T) {
+	type A struct{}
+	type B struct{}
+
+	container := di.NewContainer()
+	container.Provide(
+		func(*B) *A { return &A{} },
+		func(*A) *B { return &B{} },
+	)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic for circular dependency")
+		}
+		msg := fmt.Sprint(r)
+		if !strings.Contains(msg, "circular dependency detected: *di_test.A -> *di_test.B -> *di_test.A") {
+			t.Errorf("Unexpected panic message: %s", msg)
+		}
+	}()
+
+	di.MustResolve[*A](container)
+}
+
+// Example 11: Resolve returns errors instead of panicking
+func TestResolve(t *testing.T) {
+	type Config struct{ Host string }
+	type Service struct{}
+	type Optional struct{}
+	type Broken struct{}
+	type Buggy struct{}
+
+	container := di.NewContainer()
+	container.Supply(Config{Host: "localhost"})
+	container.Provide(
+		func(cfg Config) *Service { return &Service{} },
+		func() (*Broken, error) { return nil, errors.New("connection refused") },
+		func() *Buggy {
+			var cfg *Config
+			_ = cfg.Host // nil dereference
+			return &Buggy{}
+		},
+	)
+
+	t.Run("found", func(t *testing.T) {
+		service, err := di.Resolve[*Service](container)
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if service == nil {
+			t.Error("Resolve() returned nil service")
+		}
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		optional, err := di.Resolve[*Optional](container)
+		if err == nil || !strings.Contains(err.Error(), "is not registered") {
+			t.Errorf("Resolve() error = %v, expected not registered error", err)
+		}
+		if optional != nil {
+			t.Errorf("Resolve() = %v, expected nil", optional)
+		}
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		_, err := di.Resolve[*Broken](container)
+		if err == nil || !strings.Contains(err.Error(), "connection refused") {
+			t.Errorf("Resolve() error = %v, expected constructor error", err)
+		}
+	})
+
+	t.Run("constructor panic", func(t *testing.T) {
+		// A bug in a constructor is not a resolution error
+		defer func() {
+			if _, ok := recover().(runtime.Error); !ok {
+				t.Error("Resolve() did not propagate the constructor's runtime panic")
+			}
+		}()
+		di.Resolve[*Buggy](container)
+	})
+}
+
+// Example 12: Value groups are injected as a slice in declaration order
+func TestProvideGroup(t *testing.T) {
+	type Server struct {
+		middleware []middleware.Handler
+	}
+
+	var calls []string
+	named := func(name string) func() middleware.Handler {
+		return func() middleware.Handler {
+			return func(c *fiber.Ctx) error {
+				calls = append(calls, name)
+				return nil
+			}
+		}
+	}
+
+	container := di.NewContainer()
+	container.ProvideGroup("middleware", named("request-id"), named("logger"))
+	container.ProvideGroup("middleware", named("recover"))
+	container.Provide(func(mw []middleware.Handler) *Server {
+		return &Server{middleware: mw}
+	})
+
+	server := di.MustResolve[*Server](container)
+	if len(server.middleware) != 3 {
+		t.Fatalf("Expected 3 middleware, got %d", len(server.middleware))
+	}
+	for _, handler := range server.middleware {
+		_ = handler(nil)
+	}
+	if got := strings.Join(calls, ","); got != "request-id,logger,recover" {
+		t.Errorf("Expected middleware in declaration order, got %s", got)
+	}
+
+	// The group is resolved once and shared
+	handlers := di.MustResolve[[]middleware.Handler](container)
+	if len(handlers) != 3 {
+		t.Errorf("Expected 3 middleware, got %d", len(handlers))
+	}
+	if _, err := di.Resolve[middleware.Handler](container); err == nil {
+		t.Error("Expected group members not to be resolvable individually")
+	}
+}
+
+type closer struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (c *closer) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return c.err
+}
+
+// Example 13: Shutdown closes constructed services in reverse creation order
+func TestShutdown(t *testing.T) {
+	type DB struct{ *closer }
+	type Cache struct{ *closer }
+	type Service struct{ *closer }
+
+	var closed []string
+	errCache := errors.New("cache flush failed")
+
+	container := di.NewContainer()
+	container.Provide(
+		func() *DB { return &DB{&closer{name: "db", closed: &closed}} },
+		func(*DB) *Cache { return &Cache{&closer{name: "cache", closed: &closed, err: errCache}} },
+		func(*DB, *Cache) *Service { return &Service{&closer{name: "service", closed: &closed}} },
+	)
+	container.RegisterCleanup(func() error {
+		closed = append(closed, "manual")
+		return nil
+	})
+
+	di.MustResolve[*Service](container)
+
+	err := container.Shutdown()
+	if !errors.Is(err, errCache) {
+		t.Errorf("Shutdown() error = %v, expected %v", err, errCache)
+	}
+	if got := strings.Join(closed, ","); got != "service,cache,db,manual" {
+		t.Errorf("Expected services closed in reverse creation order, got %s", got)
+	}
+
+	// Cleanups run only once
+	closed = nil
+	if err := container.Shutdown(); err != nil {
+		t.Errorf("Second Shutdown() error = %v", err)
+	}
+	if len(closed) != 0 {
+		t.Errorf("Expected no cleanups on second Shutdown, got %v", closed)
+	}
+}
+
+// Example 14: Invoke calls a function with injected arguments
+func TestInvoke(t *testing.T) {
+	type Config struct{ Port int }
+	type Server struct{ Port int }
+	type Missing struct{}
+
+	container := di.NewContainer()
+	container.Supply(Config{Port: 8080})
+	container.Provide(func(cfg Config) *Server { return &Server{Port: cfg.Port} })
+
+	t.Run("injects arguments", func(t *testing.T) {
+		var port int
+		err := container.Invoke(func(server *Server, cfg Config) {
+			port = server.Port + cfg.Port
+		})
+		if err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		if port != 16160 {
+			t.Errorf("Expected injected arguments, got port sum %d", port)
+		}
+	})
+
+	t.Run("returns function error", func(t *testing.T) {
+		errRoutes := errors.New("duplicate route")
+		err := container.Invoke(func(*Server) error { return errRoutes })
+		if !errors.Is(err, errRoutes) {
+			t.Errorf("Invoke() error = %v, expected %v", err, errRoutes)
+		}
+	})
+
+	t.Run("unresolvable parameter", func(t *testing.T) {
+		called := false
+		err := container.Invoke(func(*Server, *Missing) { called = true })
+		if err == nil || !strings.Contains(err.Error(), "requires parameter #2 of type *di_test.Missing") {
+			t.Errorf("Invoke() error = %v, expected parameter error", err)
+		}
+		if called {
+			t.Error("Expected function not to be called")
+		}
+	})
+
+	t.Run("not a function", func(t *testing.T) {
+		if err := container.Invoke(42); err == nil {
+			t.Error("Expected error for non-function argument")
+		}
+	})
+
+	t.Run("function panic", func(t *testing.T) {
+		// Panics of the invoked function are not resolution errors, even error values
+		failure := errors.New("invariant violated")
+		defer func() {
+			if r := recover(); r != failure {
+				t.Errorf("recover() = %v, expected the function's panic", r)
+			}
+		}()
+		container.Invoke(func(*Server) { panic(failure) })
+	})
+}
+
+// Example 15: Overriding provided dependencies in tests
+type Point struct{ X, Y int }
+
+type PointRepository interface {
+	Get(id int) (*Point, error)
+}
+
+type memoryPointRepository struct {
+	point *Point
+}
+
+func (r *memoryPointRepository) Get(int) (*Point, error) {
+	return r.point, nil
+}
+
+func TestOverride(t *testing.T) {
+	type GetPointUC struct{ repository PointRepository }
+
+	newContainer := func() *di.Container {
+		container := di.NewContainer()
+		container.Provide(
+			func() *memoryPointRepository { return &memoryPointRepository{point: &Point{X: 1, Y: 1}} },
+			func(repository PointRepository) *GetPointUC { return &GetPointUC{repository: repository} },
+		)
+		return container
+	}
+
+	t.Run("provider", func(t *testing.T) {
+		container := newContainer()
+		// The real repository is already constructed and cached
+		di.MustResolve[PointRepository](container)
+
+		mock := &memoryPointRepository{point: &Point{X: 42, Y: 7}}
+		container.OverrideProvider(func() PointRepository { return mock })
+
+		uc := di.MustResolve[*GetPointUC](container)
+		if uc.repository != mock {
+			t.Errorf("Expected mock repository, got %#v", uc.repository)
+		}
+	})
+
+	t.Run("value", func(t *testing.T) {
+		type Config struct{ Host string }
+
+		container := newContainer()
+		container.Supply(Config{Host: "db"})
+		container.Override(Config{Host: "localhost"})
+
+		if cfg := di.MustResolve[Config](container); cfg.Host != "localhost" {
+			t.Errorf("Expected overridden config, got %+v", cfg)
+		}
+	})
+}
+
+// Example 16: Named dependencies of the same type
+func TestSupplyNamed(t *testing.T) {
+	type Servers struct {
+		Public   string
+		Internal string
+		Default  string
+	}
+	type ServersParams struct {
+		di.In
+		Public   http.Config `name:"public"`
+		Internal http.Config `name:"internal"`
+		Default  http.Config
+	}
+
+	container := di.NewContainer()
+	container.Supply(http.Config{Host: "localhost", Port: 8000})
+	container.SupplyNamed("public", http.Config{Host: "0.0.0.0", Port: 8080})
+	container.SupplyNamed("internal", http.Config{Host: "127.0.0.1", Port: 9090})
+	container.Provide(func(p ServersParams) *Servers {
+		return &Servers{
+			Public:   p.Public.GetAddress(),
+			Internal: p.Internal.GetAddress(),
+			Default:  p.Default.GetAddress(),
+		}
+	})
+
+	servers := di.MustResolve[*Servers](container)
+	if servers.Public != "0.0.0.0:8080" || servers.Internal != "127.0.0.1:9090" || servers.Default != "localhost:8000" {
+		t.Errorf("Unexpected injected configs: %+v", servers)
+	}
+
+	internal, err := di.ResolveNamed[http.Config](container, "internal")
+	if err != nil {
+		t.Fatalf("ResolveNamed() error = %v", err)
+	}
+	if internal.Port != 9090 {
+		t.Errorf("Expected internal config, got %+v", internal)
+	}
+
+	// Unnamed resolution never returns named values
+	if cfg := di.MustResolve[http.Config](container); cfg.Port != 8000 {
+		t.Errorf("Expected unnamed config, got %+v", cfg)
+	}
+
+	if _, err := di.ResolveNamed[http.Config](container, "admin"); err == nil || !strings.Contains(err.Error(), `named "admin" is not registered`) {
+		t.Errorf("ResolveNamed() error = %v, expected not registered error", err)
+	}
+}
+
+// Example 17: Validate reports every missing dependency without calling constructors
+func TestValidate(t *testing.T) {
+	type Config struct{}
+	type Cache struct{}
+	type Repository interface{ Save() error }
+	type Service struct{}
+	type Handler struct{}
+
+	t.Run("valid", func(t *testing.T) {
+		container := di.NewContainer()
+		container.Supply(Config{})
+		container.Provide(
+			func(Config) *Service { return &Service{} },
+			func(*Service) (*Handler, error) { return nil, errors.New("must not be called") },
+		)
+
+		if err := container.Validate(); err != nil {
+			t.Errorf("Validate() error = %v", err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		called := false
+		container := di.NewContainer()
+		container.Provide(
+			func(Config, *Cache) *Service { called = true; return &Service{} },
+			func(Repository) *Handler { called = true; return &Handler{} },
+		)
+
+		err := container.Validate()
+		if err == nil {
+			t.Fatal("Expected error for missing dependencies")
+		}
+		for _, want := range []string{
+			"requires parameter #1 of type di_test.Config, but it is not registered",
+			"requires parameter #2 of type *di_test.Cache, but it is not registered",
+			"requires parameter of type di_test.Repository, but no implementation is registered",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Validate() error = %v, expected it to contain %q", err, want)
+			}
+		}
+		if called {
+			t.Error("Expected constructors not to be called")
+		}
+	})
+}
+
+// Example 18: Concurrent resolution builds each singleton exactly once
+func TestResolve_Concurrent(t *testing.T) {
+	type L1 struct{}
+	type L2 struct{ *L1 }
+	type L3 struct {
+		*L1
+		*L2
+	}
+	type L4 struct {
+		*L2
+		*L3
+	}
+	type L5 struct {
+		*L3
+		*L4
+	}
+
+	var calls [5]atomic.Int32
+	// Slow constructors widen the window for concurrent callers
+	slow := func(i int) {
+		calls[i].Add(1)
+		time.Sleep(time.Millisecond)
+	}
+
+	container := di.NewContainer()
+	container.Provide(
+		func() *L1 { slow(0); return &L1{} },
+		func(l1 *L1) *L2 { slow(1); return &L2{l1} },
+		func(l1 *L1, l2 *L2) *L3 { slow(2); return &L3{l1, l2} },
+		func(l2 *L2, l3 *L3) (*L4, error) { slow(3); return &L4{l2, l3}, nil },
+		func(l3 *L3, l4 *L4) *L5 { slow(4); return &L5{l3, l4} },
+	)
+
+	const goroutines = 50
+	results := make([]*L5, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Start resolving from different depths of the graph
+			switch i % 3 {
+			case 0:
+				di.MustResolve[*L3](container)
+			case 1:
+				di.MustResolve[*L4](container)
+			}
+			results[i] = di.MustResolve[*L5](container)
+		}()
+	}
+	wg.Wait()
+
+	for i := range calls {
+		if n := calls[i].Load(); n != 1 {
+			t.Errorf("Expected constructor of L%d to be called once, got %d", i+1, n)
+		}
+	}
+	for i, result := range results {
+		if result != results[0] {
+			t.Fatalf("Goroutine %d resolved a different *L5 instance", i)
+		}
+	}
+}
+
+// Concurrent resolution constructs each group member exactly once
+func TestResolveGroup_Concurrent(t *testing.T) {
+	type Plugin struct{ name string }
+
+	var calls [2]atomic.Int32
+	member := func(i int, name string) func() *Plugin {
+		return func() *Plugin {
+			calls[i].Add(1)
+			time.Sleep(time.Millisecond) // Widen the window for concurrent callers
+			return &Plugin{name: name}
+		}
+	}
+
+	container := di.NewContainer()
+	container.ProvideGroup("plugins", member(0, "metrics"), member(1, "tracing"))
+
+	const goroutines = 50
+	results := make([][]*Plugin, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = di.MustResolve[[]*Plugin](container)
+		}()
+	}
+	wg.Wait()
+
+	for i := range calls {
+		if n := calls[i].Load(); n != 1 {
+			t.Errorf("Expected constructor of member %d to be called once, got %d", i+1, n)
+		}
+	}
+	for i, result := range results {
+		if len(result) != 2 || result[0] != results[0][0] || result[1] != results[0][1] {
+			t.Fatalf("Goroutine %d resolved different group values", i)
+		}
+	}
+}
+
+// Example 19: Binding an interface to one of several implementations
+type redisPointRepository struct{}
+
+func (r *redisPointRepository) Get(int) (*Point, error) {
+	return &Point{X: 2, Y: 2}, nil
+}
+
+func TestBind(t *testing.T) {
+	type GetPointUC struct{ repository PointRepository }
+
+	newContainer := func() *di.Container {
+		container := di.NewContainer()
+		container.Provide(
+			func() *memoryPointRepository { return &memoryPointRepository{point: &Point{X: 1, Y: 1}} },
+			func() *redisPointRepository { return &redisPointRepository{} },
+			func(repository PointRepository) *GetPointUC { return &GetPointUC{repository: repository} },
+		)
+		return container
+	}
+
+	t.Run("ambiguous", func(t *testing.T) {
+		container := newContainer()
+		const expected = "several implementations (*di_test.memoryPointRepository, *di_test.redisPointRepository)"
+		if _, err := di.Resolve[*GetPointUC](container); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Resolve() error = %v, expected %q", err, expected)
+		}
+		if err := container.Validate(); err == nil || !strings.Contains(err.Error(), "choose one with Bind") {
+			t.Errorf("Validate() error = %v, expected ambiguous implementations", err)
+		}
+	})
+
+	t.Run("bound", func(t *testing.T) {
+		container := newContainer()
+		container.Bind((*PointRepository)(nil), (*redisPointRepository)(nil))
+
+		if err := container.Validate(); err != nil {
+			t.Errorf("Validate() error = %v", err)
+		}
+		uc := di.MustResolve[*GetPointUC](container)
+		if _, ok := uc.repository.(*redisPointRepository); !ok {
+			t.Errorf("repository = %T, expected *redisPointRepository", uc.repository)
+		}
+		if di.MustResolve[PointRepository](container) != di.MustResolve[*redisPointRepository](container) {
+			t.Error("Expected the bound implementation to be the provided singleton")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		container := newContainer()
+		for name, bind := range map[string]func(){
+			"not an interface pointer": func() { container.Bind(PointRepository(nil), (*redisPointRepository)(nil)) },
+			"not an implementation":    func() { container.Bind((*PointRepository)(nil), &Point{}) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s: Bind() did not panic", name)
+					}
+				}()
+				bind()
+			}()
+		}
+	})
+}
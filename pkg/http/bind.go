@@ -0,0 +1,34 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/shngxx/point/pkg/http/middleware"
+)
+
+// validatorKey is the context locals key of the server validator
+const validatorKey = "validator"
+
+// BindAndValidate parses the request body into v and validates it with the server validator
+// (see WithValidator). Validation is skipped if the server has no validator.
+// Returns a 400 Bad Request error for a malformed body or invalid fields, so handlers can return it as is.
+//
+// Example:
+//
+//	var req CreatePointRequest
+//	if err := http.BindAndValidate(c, &req); err != nil {
+//	    return err
+//	}
+func BindAndValidate[T any](c *Context, v *T) error {
+	if err := c.BodyParser(v); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	validator, ok := middleware.Local[Validator](c, validatorKey)
+	if !ok {
+		return nil
+	}
+	if err := validator.Validate(v); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+	return nil
+}
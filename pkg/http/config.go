@@ -29,13 +29,15 @@ type ServerConfig interface {
 
 // Config represents server configuration that can be loaded via pkg/config
 // Use this type with config.Load or config.LoadSection to load from YAML
+// Timeouts are durations ("30s", "1m30s"); plain numbers are read as seconds by pkg/config
 type Config struct {
-	Host            string `koanf:"host"`
-	Port            int    `koanf:"port"`
-	ReadTimeout     int    `koanf:"readTimeout"`     // in seconds
-	WriteTimeout    int    `koanf:"writeTimeout"`    // in seconds
-	IdleTimeout     int    `koanf:"idleTimeout"`     // in seconds (optional, default: 120)
-	ShutdownTimeout int    `koanf:"shutdownTimeout"` // in seconds (optional, default: 30)
+	Host            string        `koanf:"host"`
+	Port            int           `koanf:"port"`
+	ReadTimeout     time.Duration `koanf:"readTimeout"`     // default: 10s
+	WriteTimeout    time.Duration `koanf:"writeTimeout"`    // default: 10s
+	IdleTimeout     time.Duration `koanf:"idleTimeout"`     // optional, default: 120s
+	ShutdownTimeout time.Duration `koanf:"shutdownTimeout"` // optional, default: 30s
+	Compression     bool          `koanf:"compression"`     // optional, compress responses in NewWithDefaults, default: false
 }
 
 // GetAddress returns the server address
@@ -60,7 +62,7 @@ func (c Config) GetPort() int {
 // GetReadTimeout returns the read timeout
 func (c Config) GetReadTimeout() time.Duration {
 	if c.ReadTimeout > 0 {
-		return time.Duration(c.ReadTimeout) * time.Second
+		return c.ReadTimeout
 	}
 	return 10 * time.Second
 }
@@ -68,7 +70,7 @@ func (c Config) GetReadTimeout() time.Duration {
 // GetWriteTimeout returns the write timeout
 func (c Config) GetWriteTimeout() time.Duration {
 	if c.WriteTimeout > 0 {
-		return time.Duration(c.WriteTimeout) * time.Second
+		return c.WriteTimeout
 	}
 	return 10 * time.Second
 }
@@ -76,7 +78,7 @@ func (c Config) GetWriteTimeout() time.Duration {
 // GetIdleTimeout returns the idle timeout
 func (c Config) GetIdleTimeout() time.Duration {
 	if c.IdleTimeout > 0 {
-		return time.Duration(c.IdleTimeout) * time.Second
+		return c.IdleTimeout
 	}
 	return 120 * time.Second
 }
@@ -84,11 +86,22 @@ func (c Config) GetIdleTimeout() time.Duration {
 // GetShutdownTimeout returns the shutdown timeout
 func (c Config) GetShutdownTimeout() time.Duration {
 	if c.ShutdownTimeout > 0 {
-		return time.Duration(c.ShutdownTimeout) * time.Second
+		return c.ShutdownTimeout
 	}
 	return 30 * time.Second
 }
 
+// GetCompression reports whether NewWithDefaults compresses responses
+func (c Config) GetCompression() bool {
+	return c.Compression
+}
+
+// CompressionConfig is an optional extension of ServerConfig
+// NewWithDefaults adds the compression middleware if GetCompression returns true
+type CompressionConfig interface {
+	GetCompression() bool
+}
+
 // DefaultConfig provides default server configuration values
 type DefaultConfig struct {
 	Address         string
@@ -2,12 +2,14 @@ package errors
 
 // Common error codes
 const (
-	CodeInternalError   = "INTERNAL_ERROR"
-	CodeBadRequest      = "BAD_REQUEST"
-	CodeNotFound        = "NOT_FOUND"
-	CodeUnauthorized    = "UNAUTHORIZED"
-	CodeForbidden       = "FORBIDDEN"
-	CodeValidationError = "VALIDATION_ERROR"
-	CodeTimeout         = "TIMEOUT"
+	CodeInternalError      = "INTERNAL_ERROR"
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeNotFound           = "NOT_FOUND"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeValidationError    = "VALIDATION_ERROR"
+	CodeTimeout            = "TIMEOUT"
+	CodeTooManyRequests    = "TOO_MANY_REQUESTS"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
 )
 
@@ -1,19 +1,43 @@
 package errors
 
 import (
+	"errors"
 	"net/http"
+	"sync"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // DefaultErrorHandler is the default error handler implementation
-type DefaultErrorHandler struct{}
+type DefaultErrorHandler struct {
+	mu       sync.RWMutex
+	statuses []errorStatus
+}
+
+// errorStatus is the HTTP status of errors matching target
+type errorStatus struct {
+	target error
+	status int
+}
 
 // NewDefaultErrorHandler creates a new default error handler
 func NewDefaultErrorHandler() ErrorHandler {
 	return &DefaultErrorHandler{}
 }
 
+// MapError makes Handle respond with status to errors matching target (see errors.Is)
+// It lets applications map their domain errors (e.g. not found) to client errors
+// instead of 500 Internal Server Error. Mappings are checked in the order they were added.
+//
+// Example:
+//
+//	h.MapError(usecase.ErrNotFound, http.StatusNotFound)
+func (h *DefaultErrorHandler) MapError(target error, status int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.statuses = append(h.statuses, errorStatus{target: target, status: status})
+}
+
 // Handle processes errors and returns appropriate HTTP responses
 func (h *DefaultErrorHandler) Handle(c *fiber.Ctx, err error) error {
 	// Check if it's a Fiber error
@@ -25,6 +49,15 @@ func (h *DefaultErrorHandler) Handle(c *fiber.Ctx, err error) error {
 		})
 	}
 
+	// Check mapped errors
+	if status, ok := h.status(err); ok {
+		return c.Status(status).JSON(ErrorResponse{
+			Success: false,
+			Error:   err.Error(),
+			Code:    getErrorCode(status),
+		})
+	}
+
 	// Default to 500 Internal Server Error
 	return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
 		Success: false,
@@ -33,6 +66,18 @@ func (h *DefaultErrorHandler) Handle(c *fiber.Ctx, err error) error {
 	})
 }
 
+// status returns the mapped HTTP status of an error
+func (h *DefaultErrorHandler) status(err error) (int, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, mapping := range h.statuses {
+		if errors.Is(err, mapping.target) {
+			return mapping.status, true
+		}
+	}
+	return 0, false
+}
+
 // getErrorCode maps HTTP status codes to error codes
 func getErrorCode(statusCode int) string {
 	switch statusCode {
@@ -46,6 +91,10 @@ func getErrorCode(statusCode int) string {
 		return CodeNotFound
 	case http.StatusRequestTimeout:
 		return CodeTimeout
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
 	default:
 		return CodeInternalError
 	}
@@ -12,3 +12,18 @@ type ErrorResponse struct {
 	Error   string `json:"error"`
 	Code    string `json:"code,omitempty"`
 }
+
+// PaginatedResponse represents a successful HTTP response with one page of a list
+type PaginatedResponse struct {
+	Success    bool       `json:"success"`
+	Data       any        `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination describes the page of a paginated response
+type Pagination struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"pageSize"`
+	Total      int `json:"total"`
+	TotalPages int `json:"totalPages"`
+}
@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultCheck is the name of the check set with WithHealthCheck
+// Its error is also reported in the top-level "error" field, as before named checks existed
+const DefaultCheck = "default"
+
+// Checks accumulates named readiness checks (e.g. "db", "redis") and is safe for concurrent use
+type Checks struct {
+	mu     sync.RWMutex
+	names  []string
+	checks map[string]func(ctx context.Context) error
+}
+
+// NewChecks creates an empty set of readiness checks
+func NewChecks() *Checks {
+	return &Checks{
+		checks: make(map[string]func(ctx context.Context) error),
+	}
+}
+
+// Add registers a named check, replacing a previous check with the same name
+func (c *Checks) Add(name string, check func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.checks[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Run runs all checks in parallel and returns the error of every check by name (nil if it passed)
+// Checks that do not finish before ctx is done report the context error
+func (c *Checks) Run(ctx context.Context) map[string]error {
+	c.mu.RLock()
+	names := append([]string(nil), c.names...)
+	checks := make([]func(ctx context.Context) error, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mu.RUnlock()
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(names))
+	for i, name := range names {
+		go func() {
+			results <- result{name: name, err: checks[i](ctx)}
+		}()
+	}
+
+	errs := make(map[string]error, len(names))
+	for range names {
+		select {
+		case r := <-results:
+			errs[r.name] = r.err
+		case <-ctx.Done():
+			for _, name := range names {
+				if _, done := errs[name]; !done {
+					errs[name] = ctx.Err()
+				}
+			}
+			return errs
+		}
+	}
+	return errs
+}
+
+// ChecksHandler handles readiness probe requests by running all checks with a timeout
+// Returns 200 OK if every check passes, otherwise 503 Service Unavailable, with the result of each check:
+//
+//	{"status": "not ready", "checks": {"db": "ok", "redis": "connection refused"}}
+//
+// A failing DefaultCheck is also reported in the top-level "error" field:
+//
+//	{"status": "not ready", "error": "database unavailable", "checks": {"default": "database unavailable"}}
+func ChecksHandler(checks *Checks, timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+
+		ready := true
+		results := make(map[string]string)
+		for name, err := range checks.Run(ctx) {
+			if err != nil {
+				ready = false
+				results[name] = err.Error()
+				continue
+			}
+			results[name] = "ok"
+		}
+
+		if !ready {
+			response := fiber.Map{
+				"status": "not ready",
+				"checks": results,
+			}
+			if result, ok := results[DefaultCheck]; ok && result != "ok" {
+				response["error"] = result
+			}
+			return c.Status(fiber.StatusServiceUnavailable).JSON(response)
+		}
+		return c.JSON(fiber.Map{
+			"status": "ready",
+			"checks": results,
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/shngxx/point/pkg/http/middleware"
+)
+
+// nopLogger is returned by LoggerFrom for requests without a per-request logger
+var nopLogger = zerolog.Nop()
+
+// LoggerFrom returns the per-request logger carrying the request_id field (see middleware.RequestLogger)
+// Returns a no-op logger if the middleware is not installed
+//
+// Example:
+//
+//	http.LoggerFrom(c).Info().Int("id", id).Msg("Point requested")
+func LoggerFrom(c *Context) *zerolog.Logger {
+	if logger := middleware.GetLogger(c); logger != nil {
+		return logger
+	}
+	return &nopLogger
+}
+
+// logLevelRequest is the body of a log level change
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// LogLevelHandler returns a handler that changes the log level at runtime with set
+// (e.g. the log.LevelSetter returned by log.New), for requests like {"level": "debug"}
+// Returns 400 Bad Request for a malformed body or an unknown level. Protect the route,
+// e.g. with middleware.JWT, as it lets callers flood the logs.
+//
+// Example:
+//
+//	logger, setLevel, err := log.New(cfg)
+//	server.PUT("/admin/loglevel", http.LogLevelHandler(setLevel), middleware.JWT(jwtConfig))
+func LogLevelHandler(set func(level string) error) Handler {
+	return func(c *Context) error {
+		var req logLevelRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+		}
+		if req.Level == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "Level is required")
+		}
+		if err := set(req.Level); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid level %q", req.Level))
+		}
+		return c.JSON(req)
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/valyala/fasthttp"
+)
+
+// CompressionConfig contains configuration for the compression middleware
+type CompressionConfig struct {
+	// Level is the compression level (default: compress.LevelDefault)
+	Level compress.Level
+
+	// MinLength is the response size in bytes below which responses are sent uncompressed (default: 1024)
+	// Responses shorter than 200 bytes are never compressed
+	MinLength int
+}
+
+// minLength returns the compression threshold with default fallback
+func (c CompressionConfig) minLength() int {
+	if c.MinLength > 0 {
+		return c.MinLength
+	}
+	return 1024
+}
+
+// compressor returns the fasthttp compression handler of the level (as Fiber's compress middleware does),
+// or nil if compression is disabled
+func (c CompressionConfig) compressor() fasthttp.RequestHandler {
+	noop := func(*fasthttp.RequestCtx) {}
+	switch c.Level {
+	case compress.LevelDefault:
+		return fasthttp.CompressHandlerBrotliLevel(noop, fasthttp.CompressBrotliDefaultCompression, fasthttp.CompressDefaultCompression)
+	case compress.LevelBestSpeed:
+		return fasthttp.CompressHandlerBrotliLevel(noop, fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBestSpeed)
+	case compress.LevelBestCompression:
+		return fasthttp.CompressHandlerBrotliLevel(noop, fasthttp.CompressBrotliBestCompression, fasthttp.CompressBestCompression)
+	default:
+		return nil
+	}
+}
+
+// Compression returns a middleware that compresses responses with brotli, gzip or deflate
+// according to the client's Accept-Encoding. Responses shorter than MinLength are sent as is.
+//
+// Example:
+//
+//	server.Use(middleware.Compression(middleware.CompressionConfig{MinLength: 2048}))
+func Compression(cfg CompressionConfig) Handler {
+	compressor := cfg.compressor()
+	if compressor == nil {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	minLength := cfg.minLength()
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if len(c.Response().Body()) < minLength {
+			return nil
+		}
+
+		// Compresses according to Accept-Encoding and sets Content-Encoding and Vary
+		compressor(c.Context())
+		return nil
+	}
+}
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
@@ -15,6 +18,10 @@ type CORSConfig struct {
 }
 
 // CORS returns a middleware that handles CORS requests
+// With AllowCredentials the request's Origin is reflected when it is in AllowOrigins,
+// since browsers reject a wildcard Access-Control-Allow-Origin on credentialed requests.
+// AllowCredentials requires an explicit allow-list: CORS panics when AllowOrigins is empty
+// or contains "*", which would let any site make credentialed requests.
 func CORS(config CORSConfig) Handler {
 	corsConfig := cors.Config{
 		AllowOrigins:     "*",
@@ -24,19 +31,30 @@ func CORS(config CORSConfig) Handler {
 		MaxAge:           0,
 	}
 
-	if len(config.AllowOrigins) > 0 {
-		corsConfig.AllowOrigins = config.AllowOrigins[0]
-		if len(config.AllowOrigins) > 1 {
-			corsConfig.AllowOrigins = ""
-			corsConfig.AllowOriginsFunc = func(origin string) bool {
-				for _, allowed := range config.AllowOrigins {
-					if allowed == "*" || allowed == origin {
-						return true
-					}
+	origins := config.AllowOrigins
+	if config.AllowCredentials && (len(origins) == 0 || slices.Contains(origins, "*")) {
+		panic("middleware: CORS with AllowCredentials requires explicit AllowOrigins without \"*\"")
+	}
+	if len(origins) == 0 {
+		origins = []string{"*"}
+	}
+	if config.AllowCredentials || len(origins) > 1 {
+		corsConfig.AllowOrigins = ""
+		corsConfig.AllowOriginsFunc = func(origin string) bool {
+			for _, allowed := range origins {
+				if allowed == "*" || strings.EqualFold(allowed, origin) {
+					return true
 				}
-				return false
 			}
+			return false
 		}
+	} else {
+		corsConfig.AllowOrigins = origins[0]
+	}
+
+	// "*" is not a wildcard for credentialed requests; an empty value reflects the requested headers
+	if config.AllowCredentials {
+		corsConfig.AllowHeaders = ""
 	}
 
 	if len(config.AllowMethods) > 0 {
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeatureFlag returns a middleware that serves the route only when enabled returns true
+// When disabled, it responds with 404 Not Found, so the route appears not to exist
+//
+// Example:
+//
+//	server.POST("/api/point/:id/teleport", handler, middleware.FeatureFlag(flags.TeleportEnabled))
+func FeatureFlag(enabled func() bool) Handler {
+	return func(c *fiber.Ctx) error {
+		if enabled == nil || !enabled() {
+			// Same error Fiber returns for unregistered routes
+			return fiber.NewError(fiber.StatusNotFound, "Cannot "+c.Method()+" "+c.OriginalURL())
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	httperrors "github.com/shngxx/point/pkg/http/errors"
+)
+
+// JWTConfig contains configuration for the JWT middleware
+type JWTConfig struct {
+	// SigningKey verifies token signatures: []byte for HMAC, a public key for RSA, ECDSA and EdDSA
+	SigningKey any
+
+	// SigningMethod is the only accepted signing algorithm (default: HS256)
+	SigningMethod string
+
+	// Optional lets requests without a token through without claims
+	// Requests with an invalid token are still rejected
+	Optional bool
+}
+
+// signingMethod returns the signing algorithm with default fallback
+func (c JWTConfig) signingMethod() string {
+	if c.SigningMethod != "" {
+		return c.SigningMethod
+	}
+	return jwt.SigningMethodHS256.Alg()
+}
+
+// JWT returns a middleware that authenticates requests with a bearer token from the Authorization header
+// Missing (unless Optional), malformed, expired or wrongly signed tokens are rejected with 401 Unauthorized.
+// Claims of a valid token are available using GetClaims.
+//
+// Example:
+//
+//	server.GET("/api/me", handler, middleware.JWT(middleware.JWTConfig{SigningKey: []byte(secret)}))
+func JWT(cfg JWTConfig) Handler {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{cfg.signingMethod()}))
+	keyFunc := func(*jwt.Token) (any, error) {
+		return cfg.SigningKey, nil
+	}
+
+	return func(c *fiber.Ctx) error {
+		tokenString, ok := bearerToken(c.Get(fiber.HeaderAuthorization))
+		if !ok {
+			if cfg.Optional {
+				return c.Next()
+			}
+			return unauthorized(c, "Missing bearer token")
+		}
+
+		claims := jwt.MapClaims{}
+		if _, err := parser.ParseWithClaims(tokenString, claims, keyFunc); err != nil {
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				return unauthorized(c, "Token expired")
+			}
+			return unauthorized(c, "Invalid token")
+		}
+
+		c.Locals("claims", claims)
+		return c.Next()
+	}
+}
+
+// GetClaims retrieves the claims of the authenticated token from the context
+// Returns nil if the request is anonymous or the JWT middleware is not installed
+func GetClaims(c *fiber.Ctx) jwt.MapClaims {
+	claims, _ := Local[jwt.MapClaims](c, "claims")
+	return claims
+}
+
+// bearerToken extracts the token from an Authorization header value
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// unauthorized responds with 401 Unauthorized
+func unauthorized(c *fiber.Ctx, message string) error {
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return c.Status(fiber.StatusUnauthorized).JSON(httperrors.ErrorResponse{
+		Success: false,
+		Error:   message,
+		Code:    httperrors.CodeUnauthorized,
+	})
+}
@@ -41,3 +41,55 @@ func (w *logWriter) Write(p []byte) (n int, err error) {
 	w.logger.Info().Msg(msg)
 	return len(p), nil
 }
+
+// StructuredLogger returns a middleware that logs every HTTP request as a zerolog event
+// with the fields method, path, status, latency_ms, bytes, request_id and remote_ip.
+// Server errors are logged at error level, client errors at warn level, other requests at info level.
+//
+// Errors returned by handlers are passed to the app's error handler first (as Fiber's logger does),
+// so the logged status is the one sent to the client.
+func StructuredLogger(l *zerolog.Logger) Handler {
+	if l == nil {
+		// Return no-op middleware if logger is nil
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		chainErr := c.Next()
+		if chainErr != nil {
+			if err := c.App().ErrorHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		var event *zerolog.Event
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			event = l.Error()
+		case status >= fiber.StatusBadRequest:
+			event = l.Warn()
+		default:
+			event = l.Info()
+		}
+
+		event.
+			Str("method", c.Method()).
+			Str("path", c.Path()).
+			Int("status", status).
+			Float64("latency_ms", float64(time.Since(start).Microseconds())/1000).
+			Int("bytes", len(c.Response().Body())).
+			Str("request_id", GetRequestID(c)).
+			Str("remote_ip", c.IP())
+		if chainErr != nil {
+			event.Err(chainErr)
+		}
+		event.Msg("HTTP request")
+
+		return nil
+	}
+}
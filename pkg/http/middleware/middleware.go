@@ -15,6 +15,15 @@ func ToFiber(h Handler) fiber.Handler {
 	}
 }
 
+// RouteHandlers builds the Fiber handler chain of a route: middleware first, then the handler
+func RouteHandlers(handler fiber.Handler, mw []Handler) []fiber.Handler {
+	handlers := make([]fiber.Handler, 0, len(mw)+1)
+	for _, m := range mw {
+		handlers = append(handlers, ToFiber(m))
+	}
+	return append(handlers, handler)
+}
+
 // Chain chains multiple middleware handlers together
 func Chain(handlers ...Handler) Handler {
 	return func(c *fiber.Ctx) error {
@@ -26,3 +35,10 @@ func Chain(handlers ...Handler) Handler {
 		return c.Next()
 	}
 }
+
+// Local retrieves a per-request value stored in the context locals and asserts its type
+// Returns the zero value and false if the value is absent or has a different type
+func Local[T any](c *fiber.Ctx, key string) (T, bool) {
+	value, ok := c.Locals(key).(T)
+	return value, ok
+}
@@ -0,0 +1,471 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	httperrors "github.com/shngxx/point/pkg/http/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestLocal tests typed retrieval of context locals
+func TestLocal(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		c.Locals("request_id", "abc")
+		c.Locals("count", 42)
+
+		// Present with the expected type
+		if id, ok := Local[string](c, "request_id"); !ok || id != "abc" {
+			t.Errorf("Local[string](request_id) = %q, %v, expected abc, true", id, ok)
+		}
+
+		// Absent
+		if v, ok := Local[string](c, "missing"); ok || v != "" {
+			t.Errorf("Local[string](missing) = %q, %v, expected empty, false", v, ok)
+		}
+
+		// Present with a different type
+		if v, ok := Local[string](c, "count"); ok || v != "" {
+			t.Errorf("Local[string](count) = %q, %v, expected empty, false", v, ok)
+		}
+
+		return nil
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+}
+
+// TestGetRequestID tests that GetRequestID does not panic without the middleware
+func TestGetRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Get("/with", RequestID(), func(c *fiber.Ctx) error {
+		if GetRequestID(c) == "" {
+			t.Error("GetRequestID() returned empty string with middleware installed")
+		}
+		return nil
+	})
+	app.Get("/without", func(c *fiber.Ctx) error {
+		if id := GetRequestID(c); id != "" {
+			t.Errorf("GetRequestID() = %q, expected empty string", id)
+		}
+		return nil
+	})
+
+	for _, path := range []string{"/with", "/without"} {
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+			t.Fatalf("app.Test(%s) error = %v", path, err)
+		}
+	}
+}
+
+// TestJWT tests bearer token authentication
+func TestJWT(t *testing.T) {
+	key := []byte("secret")
+	sign := func(claims jwt.MapClaims, method jwt.SigningMethod, key any) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString() error = %v", err)
+		}
+		return token
+	}
+	valid := sign(jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()}, jwt.SigningMethodHS256, key)
+	expired := sign(jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(-time.Hour).Unix()}, jwt.SigningMethodHS256, key)
+	wrongKey := sign(jwt.MapClaims{"sub": "alice"}, jwt.SigningMethodHS256, []byte("other"))
+	wrongAlg := sign(jwt.MapClaims{"sub": "alice"}, jwt.SigningMethodHS512, key)
+
+	tests := []struct {
+		name          string
+		optional      bool
+		authorization string
+		status        int
+		subject       string // "sub" claim seen by the handler
+	}{
+		{"valid", false, "Bearer " + valid, fiber.StatusOK, "alice"},
+		{"valid lowercase scheme", false, "bearer " + valid, fiber.StatusOK, "alice"},
+		{"expired", false, "Bearer " + expired, fiber.StatusUnauthorized, ""},
+		{"malformed", false, "Bearer not.a.token", fiber.StatusUnauthorized, ""},
+		{"wrong key", false, "Bearer " + wrongKey, fiber.StatusUnauthorized, ""},
+		{"wrong algorithm", false, "Bearer " + wrongAlg, fiber.StatusUnauthorized, ""},
+		{"missing", false, "", fiber.StatusUnauthorized, ""},
+		{"not a bearer token", false, "Basic dXNlcjpwYXNz", fiber.StatusUnauthorized, ""},
+		{"optional anonymous", true, "", fiber.StatusOK, ""},
+		{"optional valid", true, "Bearer " + valid, fiber.StatusOK, "alice"},
+		{"optional invalid", true, "Bearer not.a.token", fiber.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			var subject string
+			app.Get("/", JWT(JWTConfig{SigningKey: key, Optional: tt.optional}), func(c *fiber.Ctx) error {
+				if claims := GetClaims(c); claims != nil {
+					subject, _ = claims.GetSubject()
+				}
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.authorization)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Status = %d, expected %d", resp.StatusCode, tt.status)
+			}
+			if subject != tt.subject {
+				t.Errorf("Claims subject = %q, expected %q", subject, tt.subject)
+			}
+			if resp.StatusCode == fiber.StatusUnauthorized {
+				var body httperrors.ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				if body.Success || body.Code != httperrors.CodeUnauthorized || body.Error == "" {
+					t.Errorf("Body = %+v, expected an unauthorized error response", body)
+				}
+			}
+		})
+	}
+}
+
+// TestRateLimit tests that requests beyond the burst are rejected per key
+func TestRateLimit(t *testing.T) {
+	const burst = 3
+	app := fiber.New()
+	app.Get("/", RateLimit(RateLimitConfig{
+		Requests: 1,
+		Window:   time.Minute,
+		Burst:    burst,
+		KeyFunc: func(c *fiber.Ctx) string {
+			return c.Get("X-API-Key")
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := func(apiKey string) *http.Response {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-API-Key", apiKey)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+		return resp
+	}
+
+	for i := range burst {
+		if resp := request("a"); resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("Request %d: status = %d, expected %d", i+1, resp.StatusCode, fiber.StatusOK)
+		}
+	}
+
+	resp := request("a")
+	if resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("Request %d: status = %d, expected %d", burst+1, resp.StatusCode, fiber.StatusTooManyRequests)
+	}
+	// The next token arrives in a minute
+	if retryAfter, err := strconv.Atoi(resp.Header.Get(fiber.HeaderRetryAfter)); err != nil || retryAfter < 1 || retryAfter > 60 {
+		t.Errorf("Retry-After = %q, expected 1..60 seconds", resp.Header.Get(fiber.HeaderRetryAfter))
+	}
+	var body httperrors.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if body.Success || body.Code != httperrors.CodeTooManyRequests {
+		t.Errorf("Body = %+v, expected a too many requests error response", body)
+	}
+
+	// Other keys have their own buckets
+	if resp := request("b"); resp.StatusCode != fiber.StatusOK {
+		t.Errorf("Other key: status = %d, expected %d", resp.StatusCode, fiber.StatusOK)
+	}
+}
+
+// TestCompression tests that only responses above the threshold are compressed for clients accepting gzip
+func TestCompression(t *testing.T) {
+	app := fiber.New()
+	app.Use(ToFiber(Compression(CompressionConfig{MinLength: 512})))
+	app.Get("/large", func(c *fiber.Ctx) error {
+		return c.SendString(strings.Repeat("point ", 1000))
+	})
+	app.Get("/small", func(c *fiber.Ctx) error {
+		return c.SendString(strings.Repeat("point ", 50)) // 300 bytes
+	})
+
+	tests := []struct {
+		path           string
+		acceptEncoding string
+		encoding       string
+	}{
+		{"/large", "gzip", "gzip"},
+		{"/small", "gzip", ""},
+		{"/large", "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.acceptEncoding != "" {
+			req.Header.Set(fiber.HeaderAcceptEncoding, tt.acceptEncoding)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test(%s) error = %v", tt.path, err)
+		}
+		if encoding := resp.Header.Get(fiber.HeaderContentEncoding); encoding != tt.encoding {
+			t.Errorf("%s with Accept-Encoding %q: Content-Encoding = %q, expected %q", tt.path, tt.acceptEncoding, encoding, tt.encoding)
+		}
+	}
+}
+
+// TestCORS tests that credentialed configurations reflect allowed origins instead of "*"
+// and leave disallowed origins without CORS headers
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      CORSConfig
+		origin      string
+		allowOrigin string
+		credentials string
+	}{
+		{"wildcard", CORSConfig{}, "https://a.example", "*", ""},
+		{"single origin credentials", CORSConfig{AllowOrigins: []string{"https://a.example"}, AllowCredentials: true}, "https://a.example", "https://a.example", "true"},
+		{"list credentials", CORSConfig{AllowOrigins: []string{"https://a.example", "https://b.example"}, AllowCredentials: true}, "https://b.example", "https://b.example", "true"},
+		{"disallowed single origin", CORSConfig{AllowOrigins: []string{"https://a.example"}, AllowCredentials: true}, "https://evil.example", "", ""},
+		{"disallowed list", CORSConfig{AllowOrigins: []string{"https://a.example", "https://b.example"}, AllowCredentials: true}, "https://evil.example", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(ToFiber(CORS(tt.config)))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			for _, method := range []string{"GET", "OPTIONS"} {
+				req := httptest.NewRequest(method, "/", nil)
+				req.Header.Set(fiber.HeaderOrigin, tt.origin)
+				if method == "OPTIONS" {
+					req.Header.Set(fiber.HeaderAccessControlRequestMethod, "GET")
+				}
+				resp, err := app.Test(req)
+				if err != nil {
+					t.Fatalf("app.Test(%s) error = %v", method, err)
+				}
+				if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != tt.allowOrigin {
+					t.Errorf("%s Access-Control-Allow-Origin = %q, expected %q", method, got, tt.allowOrigin)
+				}
+				if got := resp.Header.Get(fiber.HeaderAccessControlAllowCredentials); got != tt.credentials {
+					t.Errorf("%s Access-Control-Allow-Credentials = %q, expected %q", method, got, tt.credentials)
+				}
+			}
+		})
+	}
+}
+
+// TestCORS_CredentialedWildcard tests that credentials cannot be combined with any origin
+func TestCORS_CredentialedWildcard(t *testing.T) {
+	for _, origins := range [][]string{nil, {"*"}, {"https://a.example", "*"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("CORS(%q with credentials) did not panic", origins)
+				}
+			}()
+			CORS(CORSConfig{AllowOrigins: origins, AllowCredentials: true})
+		}()
+	}
+}
+
+// TestStructuredLogger tests that requests are logged with discrete fields
+func TestStructuredLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	app := fiber.New()
+	app.Use(ToFiber(StructuredLogger(&logger)), ToFiber(RequestID()))
+	app.Get("/api/point/:id", func(c *fiber.Ctx) error {
+		return c.SendString("point")
+	})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		level  string
+		bytes  int
+	}{
+		{"/api/point/1", 200, "info", len("point")},
+		{"/missing", 404, "warn", len(fiber.ErrNotFound.Message)},
+	}
+
+	for _, tt := range tests {
+		buf.Reset()
+		resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test(%s) error = %v", tt.path, err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status = %d, expected %d", tt.path, resp.StatusCode, tt.status)
+		}
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: log entry %q is not JSON: %v", tt.path, buf.String(), err)
+		}
+		for _, field := range []string{"method", "path", "status", "latency_ms", "bytes", "request_id", "remote_ip"} {
+			if _, ok := entry[field]; !ok {
+				t.Errorf("%s: log entry %v has no field %s", tt.path, entry, field)
+			}
+		}
+		if entry["level"] != tt.level || entry["method"] != "GET" || entry["path"] != tt.path ||
+			entry["status"] != float64(tt.status) || entry["bytes"] != float64(tt.bytes) ||
+			entry["request_id"] != resp.Header.Get(fiber.HeaderXRequestID) || entry["request_id"] == "" {
+			t.Errorf("%s: log entry = %v", tt.path, entry)
+		}
+	}
+}
+
+// TestRecoveryWithConfig tests that a panicking handler gets a 500 response, a log entry and the OnPanic callback
+func TestRecoveryWithConfig(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	var recovered any
+	var stack []byte
+	app := fiber.New()
+	app.Use(ToFiber(RecoveryWithConfig(RecoveryConfig{
+		Logger: &logger,
+		OnPanic: func(c *fiber.Ctx, r any, s []byte) {
+			recovered, stack = r, s
+		},
+	})))
+	app.Get("/", func(c *fiber.Ctx) error {
+		panic("point out of range")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Errorf("Status = %d, expected %d", resp.StatusCode, fiber.StatusInternalServerError)
+	}
+	var body httperrors.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if body.Success || body.Code != httperrors.CodeInternalError {
+		t.Errorf("Body = %+v, expected an internal error response", body)
+	}
+
+	if recovered != "point out of range" {
+		t.Errorf("OnPanic recovered = %v, expected the panic value", recovered)
+	}
+	if !bytes.Contains(stack, []byte("TestRecoveryWithConfig")) {
+		t.Errorf("OnPanic stack does not contain the panicking handler:\n%s", stack)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Log entry %q is not JSON: %v", buf.String(), err)
+	}
+	if entry["level"] != "error" || entry["panic"] != "point out of range" || entry["stack"] == "" {
+		t.Errorf("Log entry = %v, expected the panic with its stack at error level", entry)
+	}
+}
+
+// TestTracing tests starting a span per request that continues the incoming trace
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	app := fiber.New()
+	app.Use(ToFiber(Tracing(provider.Tracer("test"))))
+	var handlerSpan trace.SpanContext
+	app.Get("/api/point/:id", func(c *fiber.Ctx) error {
+		handlerSpan = trace.SpanContextFromContext(c.UserContext())
+		return c.SendString("point")
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "repository unavailable")
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("GET", "/api/point/7", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("Status = %d, expected 200", resp.StatusCode)
+	}
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/fail", nil)); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Spans = %d, expected one per request", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "GET /api/point/:id" {
+		t.Errorf("Name = %q, expected %q", span.Name(), "GET /api/point/:id")
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("Kind = %v, expected server", span.SpanKind())
+	}
+	if span.SpanContext().TraceID().String() != traceID || span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("Span %v does not continue the incoming trace", span.SpanContext())
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("Handler context span = %v, expected the request span", handlerSpan.SpanID())
+	}
+	if !strings.Contains(resp.Header.Get("traceparent"), traceID) {
+		t.Errorf("Response traceparent = %q, expected trace %s", resp.Header.Get("traceparent"), traceID)
+	}
+	attrs := attribute.NewSet(span.Attributes()...)
+	if v, _ := attrs.Value("http.route"); v.AsString() != "/api/point/:id" {
+		t.Errorf("http.route = %q, expected /api/point/:id", v.AsString())
+	}
+	if v, _ := attrs.Value("http.response.status_code"); v.AsInt64() != 200 {
+		t.Errorf("http.response.status_code = %d, expected 200", v.AsInt64())
+	}
+
+	span = spans[1]
+	if span.Status().Code != codes.Error || span.Status().Description != "repository unavailable" {
+		t.Errorf("Status = %+v, expected an error", span.Status())
+	}
+	attrs = attribute.NewSet(span.Attributes()...)
+	if v, _ := attrs.Value("http.response.status_code"); v.AsInt64() != 503 {
+		t.Errorf("http.response.status_code = %d, expected 503", v.AsInt64())
+	}
+	if len(span.Events()) == 0 || span.Events()[0].Name != "exception" {
+		t.Errorf("Events = %v, expected the recorded error", span.Events())
+	}
+}
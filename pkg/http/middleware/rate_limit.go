@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	httperrors "github.com/shngxx/point/pkg/http/errors"
+)
+
+// RateLimitConfig contains configuration for the rate limiting middleware
+type RateLimitConfig struct {
+	Requests int           // Requests allowed per Window (default: 60)
+	Window   time.Duration // Window of Requests (default: 1 minute)
+	Burst    int           // Maximum requests at once (default: Requests)
+
+	// KeyFunc returns the key requests are limited by (default: client IP)
+	KeyFunc func(*fiber.Ctx) string
+}
+
+// requests returns the number of requests per window with default fallback
+func (c RateLimitConfig) requests() int {
+	if c.Requests > 0 {
+		return c.Requests
+	}
+	return 60
+}
+
+// window returns the rate limit window with default fallback
+func (c RateLimitConfig) window() time.Duration {
+	if c.Window > 0 {
+		return c.Window
+	}
+	return time.Minute
+}
+
+// burst returns the maximum burst with default fallback
+func (c RateLimitConfig) burst() int {
+	if c.Burst > 0 {
+		return c.Burst
+	}
+	return c.requests()
+}
+
+// keyFunc returns the key function with default fallback
+func (c RateLimitConfig) keyFunc() func(*fiber.Ctx) string {
+	if c.KeyFunc != nil {
+		return c.KeyFunc
+	}
+	return func(c *fiber.Ctx) string {
+		return c.IP()
+	}
+}
+
+// bucket is a token bucket of a single key
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter holds the token buckets of all keys
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	rate      float64 // Tokens per second
+	burst     float64
+	lastSweep time.Time
+}
+
+// allow takes a token from the bucket of key
+// Returns false and the time until the next token if the bucket is empty
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep removes buckets that have refilled completely, as they are equivalent to new ones
+// Runs at most once per full refill time
+func (l *rateLimiter) sweep(now time.Time) {
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimit returns a middleware that limits the request rate per client with token buckets
+// Every key gets Burst tokens that refill at Requests per Window; a request without a token
+// is rejected with 429 Too Many Requests and a Retry-After header.
+//
+// Example:
+//
+//	server.GET("/api/point/:id", handler, middleware.RateLimit(middleware.RateLimitConfig{
+//	    Requests: 10,
+//	    Window:   time.Second,
+//	    Burst:    20,
+//	}))
+func RateLimit(cfg RateLimitConfig) Handler {
+	limiter := &rateLimiter{
+		buckets: make(map[string]*bucket),
+		rate:    float64(cfg.requests()) / cfg.window().Seconds(),
+		burst:   float64(cfg.burst()),
+	}
+	keyFunc := cfg.keyFunc()
+
+	return func(c *fiber.Ctx) error {
+		allowed, retryAfter := limiter.allow(keyFunc(c), time.Now())
+		if allowed {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return c.Status(fiber.StatusTooManyRequests).JSON(httperrors.ErrorResponse{
+			Success: false,
+			Error:   "Too many requests",
+			Code:    httperrors.CodeTooManyRequests,
+		})
+	}
+}
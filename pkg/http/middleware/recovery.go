@@ -1,14 +1,74 @@
 package middleware
 
 import (
-	"github.com/gofiber/fiber/v2/middleware/recover"
+	"fmt"
+	"os"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	httperrors "github.com/shngxx/point/pkg/http/errors"
 )
 
+// RecoveryConfig contains configuration for the recovery middleware
+type RecoveryConfig struct {
+	// Logger logs recovered panics with their stack at error level
+	// Default: nil, the panic and its stack are written to stderr
+	Logger *zerolog.Logger
+
+	// OnPanic is called after a panic is recovered, e.g. to forward it to Sentry (optional)
+	OnPanic func(c *fiber.Ctx, recovered any, stack []byte)
+}
+
 // Recovery returns a middleware that recovers from panics
-// It logs the panic and returns a 500 Internal Server Error
+// It writes the panic and its stack to stderr and returns a 500 Internal Server Error
+// (see RecoveryWithConfig to log panics with a logger)
 func Recovery() Handler {
-	recoverMiddleware := recover.New(recover.Config{
-		EnableStackTrace: true,
-	})
-	return ToFiber(recoverMiddleware)
+	return RecoveryWithConfig(RecoveryConfig{})
+}
+
+// RecoveryWithConfig returns a middleware that recovers from panics
+// It logs the panic value with the stack, calls OnPanic and returns a 500 Internal Server Error
+//
+// Example:
+//
+//	server.Use(middleware.RecoveryWithConfig(middleware.RecoveryConfig{
+//	    Logger: logger,
+//	    OnPanic: func(c *fiber.Ctx, recovered any, stack []byte) {
+//	        sentry.CurrentHub().Recover(recovered)
+//	    },
+//	}))
+func RecoveryWithConfig(cfg RecoveryConfig) Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			stack := debug.Stack()
+
+			if cfg.Logger == nil {
+				fmt.Fprintf(os.Stderr, "panic: %v\n%s\n", recovered, stack)
+			} else {
+				cfg.Logger.Error().
+					Str("panic", fmt.Sprint(recovered)).
+					Str("stack", string(stack)).
+					Str("method", c.Method()).
+					Str("path", c.Path()).
+					Str("request_id", GetRequestID(c)).
+					Msg("HTTP panic recovered")
+			}
+			if cfg.OnPanic != nil {
+				cfg.OnPanic(c, recovered, stack)
+			}
+
+			err = c.Status(fiber.StatusInternalServerError).JSON(httperrors.ErrorResponse{
+				Success: false,
+				Error:   "Internal server error",
+				Code:    httperrors.CodeInternalError,
+			})
+		}()
+
+		return c.Next()
+	}
 }
@@ -4,6 +4,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	applog "github.com/shngxx/point/pkg/log"
 )
 
 // RequestID returns a middleware that generates and sets a request ID
@@ -19,7 +21,36 @@ func RequestID() Handler {
 }
 
 // GetRequestID retrieves the request ID from the context
+// Returns an empty string if the RequestID middleware is not installed
 func GetRequestID(c *fiber.Ctx) string {
-	return c.Locals("request_id").(string)
+	id, _ := Local[string](c, "request_id")
+	return id
 }
 
+// RequestLogger returns a middleware that stores a child of l carrying the request_id field
+// in the context, so handlers log with request correlation (see http.LoggerFrom)
+// The logger is also stored in the user context, so use cases called with c.UserContext()
+// log with the request ID too (see log.FromContext)
+// Must be installed after RequestID
+func RequestLogger(l *zerolog.Logger) Handler {
+	return func(c *fiber.Ctx) error {
+		if l == nil {
+			return c.Next()
+		}
+
+		logger := *l
+		if id := GetRequestID(c); id != "" {
+			logger = l.With().Str("request_id", id).Logger()
+		}
+		c.Locals("logger", &logger)
+		c.SetUserContext(applog.WithContext(c.UserContext(), &logger))
+		return c.Next()
+	}
+}
+
+// GetLogger retrieves the per-request logger from the context
+// Returns nil if the RequestLogger middleware is not installed
+func GetLogger(c *fiber.Ctx) *zerolog.Logger {
+	logger, _ := Local[*zerolog.Logger](c, "logger")
+	return logger
+}
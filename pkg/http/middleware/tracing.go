@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceContext propagates spans with the W3C traceparent and tracestate headers
+var traceContext = propagation.TraceContext{}
+
+// Tracing returns a middleware that starts a span per request with tracer
+// The span continues the trace of an incoming traceparent header, is named by method and route pattern
+// (e.g. "GET /api/point/:id") and records the response status and errors. The span context is stored
+// in c.UserContext(), so use cases can start child spans, and the traceparent header is set on the response.
+// Errors are passed to the app ErrorHandler so the span records the final status.
+//
+// Example:
+//
+//	tracer := otel.Tracer("point")
+//	server.Use(middleware.Tracing(tracer))
+func Tracing(tracer trace.Tracer) Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := traceContext.Extract(c.UserContext(), requestHeaderCarrier{c})
+		ctx, span := tracer.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Method()),
+				attribute.String("url.path", c.Path()),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		traceContext.Inject(ctx, responseHeaderCarrier{c})
+
+		chainErr := c.Next()
+		if chainErr != nil {
+			span.RecordError(chainErr)
+			if err := c.App().ErrorHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// The route is known once the request has been routed
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		status := c.Response().StatusCode()
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			description := ""
+			if chainErr != nil {
+				description = chainErr.Error()
+			}
+			span.SetStatus(codes.Error, description)
+		}
+
+		return nil
+	}
+}
+
+// requestHeaderCarrier reads propagation headers from the request
+type requestHeaderCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h requestHeaderCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h requestHeaderCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h requestHeaderCarrier) Keys() []string {
+	keys := make([]string, 0)
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// responseHeaderCarrier writes propagation headers to the response
+type responseHeaderCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h responseHeaderCarrier) Get(key string) string {
+	return h.c.GetRespHeader(key)
+}
+
+func (h responseHeaderCarrier) Set(key, value string) {
+	h.c.Set(key, value)
+}
+
+func (h responseHeaderCarrier) Keys() []string {
+	keys := make([]string, 0)
+	h.c.Response().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// SkipWebSocket returns a middleware that runs h for every request except WebSocket upgrades
+// Use it for global middleware that does not make sense for upgraded connections
+//
+// Example:
+//
+//	server.Use(middleware.SkipWebSocket(middleware.Timeout(5 * time.Second)))
+func SkipWebSocket(h Handler) Handler {
+	return func(c *fiber.Ctx) error {
+		if websocket.IsWebSocketUpgrade(c) {
+			return c.Next()
+		}
+		return h(c)
+	}
+}
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"crypto/tls"
+
 	"github.com/rs/zerolog"
 	"github.com/shngxx/point/pkg/http/middleware"
 )
@@ -50,6 +52,8 @@ func WithErrorHandler(handler ErrorHandler) Option {
 }
 
 // WithHealthCheck sets a custom health check function for readiness probe
+// It is reported as the "default" check (see Server.AddReadinessCheck),
+// and its error also in the top-level "error" field of the response
 func WithHealthCheck(check func() error) Option {
 	return func(s *Server) {
 		s.healthCheck = check
@@ -64,3 +68,22 @@ func WithValidator(validator Validator) Option {
 		}
 	}
 }
+
+// WithTLS serves HTTPS with the certificate and key from PEM files
+// Fiber (fasthttp) serves HTTP/1.1 only, HTTP/2 requires a terminating proxy
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.tlsCertFile = certFile
+		s.tlsKeyFile = keyFile
+	}
+}
+
+// WithTLSConfig serves HTTPS with a custom TLS configuration (e.g. ClientAuth for mutual TLS
+// or CipherSuites). Certificates of WithTLS are added to the configuration.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(s *Server) {
+		if cfg != nil {
+			s.tlsConfig = cfg
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shngxx/point/pkg/http/middleware"
+)
+
+// ParamInt returns a path parameter as an integer, or def if the parameter is empty
+// Returns a 400 Bad Request error if the parameter is not an integer
+//
+// Example:
+//
+//	id, err := http.ParamInt(c, "id", 1)
+func ParamInt(c *Context, name string, def int) (int, error) {
+	return parseInt("path parameter", name, c.Params(name), def)
+}
+
+// QueryInt returns a query parameter as an integer, or def if the parameter is absent or empty
+// Returns a 400 Bad Request error if the parameter is not an integer
+func QueryInt(c *Context, name string, def int) (int, error) {
+	return parseInt("query parameter", name, c.Query(name), def)
+}
+
+// parseInt parses an integer request parameter
+func parseInt(kind, name, value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid %s %s: %q is not an integer", kind, name, value))
+	}
+	return n, nil
+}
+
+const (
+	// DefaultPageSize is the page size used by PageParams when none is requested
+	DefaultPageSize = 20
+	// MaxPageSize is the largest page size PageParams returns
+	MaxPageSize = 100
+)
+
+// PageParams returns the requested page (from 1) and page size from the page and size query parameters
+// Absent or invalid values fall back to page 1 and DefaultPageSize; sizes above MaxPageSize are capped
+func PageParams(c *Context) (page, size int) {
+	page = c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	size = c.QueryInt("size", DefaultPageSize)
+	if size < 1 {
+		size = DefaultPageSize
+	}
+	return page, min(size, MaxPageSize)
+}
+
+// BindParams fills the fields of v tagged `param:"name"` from path parameters and `query:"name"`
+// from query parameters, then validates v with the server validator (see WithValidator).
+// Fields of absent parameters keep their values, so defaults can be set before binding.
+// Supported field types are strings, integers, unsigned integers, floats and bools.
+// Returns a 400 Bad Request error for unparsable or invalid parameters.
+//
+// Example:
+//
+//	type ListParams struct {
+//	    ID    int `param:"id" validate:"gte=1"`
+//	    Limit int `query:"limit" validate:"lte=100"`
+//	}
+//
+//	params := ListParams{ID: 1, Limit: 20}
+//	if err := http.BindParams(c, &params); err != nil {
+//	    return err
+//	}
+func BindParams[T any](c *Context, v *T) error {
+	value := reflect.ValueOf(v).Elem()
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("BindParams: %T is not a pointer to a struct", v)
+	}
+
+	typ := value.Type()
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		kind, name, raw := "path parameter", field.Tag.Get("param"), ""
+		if name != "" {
+			raw = c.Params(name)
+		} else if name = field.Tag.Get("query"); name != "" {
+			kind, raw = "query parameter", c.Query(name)
+		} else {
+			continue
+		}
+		if raw == "" {
+			continue
+		}
+
+		if err := setParam(value.Field(i), raw); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid %s %s: %q %v", kind, name, raw, err))
+		}
+	}
+
+	validator, ok := middleware.Local[Validator](c, validatorKey)
+	if !ok {
+		return nil
+	}
+	if err := validator.Validate(v); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+	return nil
+}
+
+// setParam parses a parameter value into a field according to its type
+func setParam(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("is not an integer")
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("is not an unsigned integer")
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("is not a number")
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("is not a boolean")
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("cannot be bound to %v", field.Type())
+	}
+	return nil
+}
@@ -1,16 +1,22 @@
 package plugin
 
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/shngxx/point/pkg/http/middleware"
+	"github.com/shngxx/point/pkg/http/routing"
+)
+
 // ServerInterface defines the interface that plugins can use to interact with the server
-// This avoids import cycles
+// It uses the routing and middleware types, so this package does not import the server (avoids import cycles)
 type ServerInterface interface {
-	App() any // Returns the underlying Fiber app
-	Use(middleware ...any)
-	GET(path string, handler any)
-	POST(path string, handler any)
-	PUT(path string, handler any)
-	DELETE(path string, handler any)
-	PATCH(path string, handler any)
-	Group(prefix string, fn any)
+	App() *fiber.App // Returns the underlying Fiber app
+	Use(mw ...middleware.Handler)
+	GET(path string, handler routing.Handler, mw ...middleware.Handler)
+	POST(path string, handler routing.Handler, mw ...middleware.Handler)
+	PUT(path string, handler routing.Handler, mw ...middleware.Handler)
+	DELETE(path string, handler routing.Handler, mw ...middleware.Handler)
+	PATCH(path string, handler routing.Handler, mw ...middleware.Handler)
+	Group(prefix string, fn func(*routing.Group))
 }
 
 // Plugin defines the interface for server plugins
@@ -21,3 +27,20 @@ type Plugin interface {
 	// Install installs the plugin on the server
 	Install(server ServerInterface) error
 }
+
+// DependentPlugin is implemented by plugins that must be installed after other plugins
+type DependentPlugin interface {
+	Plugin
+
+	// Dependencies returns the names of the plugins to install first
+	Dependencies() []string
+}
+
+// ClosablePlugin is implemented by plugins that release resources on shutdown
+// (e.g. stop background goroutines or close connections)
+type ClosablePlugin interface {
+	Plugin
+
+	// Uninstall tears the plugin down
+	Uninstall(server ServerInterface) error
+}
@@ -1,7 +1,9 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -9,6 +11,10 @@ import (
 type Registry struct {
 	mu      sync.RWMutex
 	plugins map[string]Plugin
+	order   []string // Plugin names in registration order
+
+	server    ServerInterface // Server the plugins were installed on
+	installed []string        // Installed plugin names in install order
 }
 
 // NewRegistry creates a new plugin registry
@@ -37,23 +43,99 @@ func (r *Registry) Register(plugin Plugin) error {
 	}
 
 	r.plugins[name] = plugin
+	r.order = append(r.order, name)
 	return nil
 }
 
 // Install installs all registered plugins on the server
+// Plugins are installed in registration order, except that the dependencies of a DependentPlugin
+// are installed before it. Nothing is installed if a dependency is missing or cyclic.
 func (r *Registry) Install(server ServerInterface) error {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, err := r.installOrder()
+	if err != nil {
+		return err
+	}
 
-	for name, plugin := range r.plugins {
-		if err := plugin.Install(server); err != nil {
+	r.server = server
+	for _, name := range order {
+		if err := r.plugins[name].Install(server); err != nil {
 			return fmt.Errorf("failed to install plugin %s: %w", name, err)
 		}
+		r.installed = append(r.installed, name)
 	}
 
 	return nil
 }
 
+// Uninstall tears down the installed plugins that implement ClosablePlugin in reverse install order
+// Every plugin is uninstalled even if some fail; the errors are returned joined
+func (r *Registry) Uninstall() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for i := len(r.installed) - 1; i >= 0; i-- {
+		name := r.installed[i]
+		closable, ok := r.plugins[name].(ClosablePlugin)
+		if !ok {
+			continue
+		}
+		if err := closable.Uninstall(r.server); err != nil {
+			errs = append(errs, fmt.Errorf("failed to uninstall plugin %s: %w", name, err))
+		}
+	}
+	r.installed = nil
+
+	return errors.Join(errs...)
+}
+
+// installOrder sorts plugins topologically by their dependencies, keeping registration order otherwise
+func (r *Registry) installOrder() ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(r.plugins))
+	order := make([]string, 0, len(r.plugins))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("plugin dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		path = append(path, name)
+
+		if dependent, ok := r.plugins[name].(DependentPlugin); ok {
+			for _, dep := range dependent.Dependencies() {
+				if _, exists := r.plugins[dep]; !exists {
+					return fmt.Errorf("plugin %s depends on unregistered plugin %s", name, dep)
+				}
+				if err := visit(dep, path); err != nil {
+					return err
+				}
+			}
+		}
+
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range r.order {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
 // Get retrieves a plugin by name
 func (r *Registry) Get(name string) (Plugin, bool) {
 	r.mu.RLock()
@@ -0,0 +1,171 @@
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testPlugin records its installation
+type testPlugin struct {
+	name      string
+	deps      []string
+	installed *[]string
+}
+
+func (p *testPlugin) Name() string { return p.name }
+
+func (p *testPlugin) Install(ServerInterface) error {
+	*p.installed = append(*p.installed, p.name)
+	return nil
+}
+
+// dependentPlugin is a testPlugin with dependencies
+type dependentPlugin struct {
+	testPlugin
+}
+
+func (p *dependentPlugin) Dependencies() []string { return p.deps }
+
+// TestRegistry_InstallOrder tests installing dependencies before the plugins that need them
+func TestRegistry_InstallOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugins  func(installed *[]string) []Plugin
+		expected []string
+		err      string
+	}{
+		{
+			name: "dependency registered later",
+			plugins: func(installed *[]string) []Plugin {
+				return []Plugin{
+					&dependentPlugin{testPlugin{name: "A", deps: []string{"B"}, installed: installed}},
+					&testPlugin{name: "B", installed: installed},
+				}
+			},
+			expected: []string{"B", "A"},
+		},
+		{
+			name: "registration order without dependencies",
+			plugins: func(installed *[]string) []Plugin {
+				return []Plugin{
+					&testPlugin{name: "C", installed: installed},
+					&dependentPlugin{testPlugin{name: "A", deps: []string{"B"}, installed: installed}},
+					&testPlugin{name: "D", installed: installed},
+					&testPlugin{name: "B", installed: installed},
+				}
+			},
+			expected: []string{"C", "B", "A", "D"},
+		},
+		{
+			name: "missing dependency",
+			plugins: func(installed *[]string) []Plugin {
+				return []Plugin{
+					&dependentPlugin{testPlugin{name: "A", deps: []string{"B"}, installed: installed}},
+				}
+			},
+			err: "depends on unregistered plugin B",
+		},
+		{
+			name: "cycle",
+			plugins: func(installed *[]string) []Plugin {
+				return []Plugin{
+					&dependentPlugin{testPlugin{name: "A", deps: []string{"B"}, installed: installed}},
+					&dependentPlugin{testPlugin{name: "B", deps: []string{"A"}, installed: installed}},
+				}
+			},
+			err: "cycle: A -> B -> A",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var installed []string
+			r := NewRegistry()
+			for _, p := range tt.plugins(&installed) {
+				if err := r.Register(p); err != nil {
+					t.Fatalf("Register() error = %v", err)
+				}
+			}
+
+			err := r.Install(nil)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Install() error = %v, expected %q", err, tt.err)
+				}
+				if len(installed) != 0 {
+					t.Errorf("Installed %v despite the error", installed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			if strings.Join(installed, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Install order = %v, expected %v", installed, tt.expected)
+			}
+		})
+	}
+}
+
+// workerPlugin runs a background goroutine while installed
+type workerPlugin struct {
+	testPlugin
+	stop    chan struct{}
+	stopped chan struct{}
+	err     error // Returned by Uninstall
+}
+
+func (p *workerPlugin) Install(server ServerInterface) error {
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+	go func() {
+		defer close(p.stopped)
+		<-p.stop
+	}()
+	return p.testPlugin.Install(server)
+}
+
+func (p *workerPlugin) Uninstall(ServerInterface) error {
+	close(p.stop)
+	<-p.stopped
+	*p.installed = append(*p.installed, "-"+p.name)
+	return p.err
+}
+
+// TestRegistry_Uninstall tests tearing plugins down in reverse install order
+func TestRegistry_Uninstall(t *testing.T) {
+	var log []string
+	worker := &workerPlugin{testPlugin: testPlugin{name: "worker", installed: &log}}
+	failing := &workerPlugin{testPlugin: testPlugin{name: "failing", installed: &log}, err: errors.New("close failed")}
+
+	r := NewRegistry()
+	for _, p := range []Plugin{worker, &testPlugin{name: "plain", installed: &log}, failing} {
+		if err := r.Register(p); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	if err := r.Install(nil); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	err := r.Uninstall()
+	if err == nil || !strings.Contains(err.Error(), "failed to uninstall plugin failing: close failed") {
+		t.Errorf("Uninstall() error = %v, expected the failing plugin error", err)
+	}
+
+	select {
+	case <-worker.stopped:
+	default:
+		t.Error("Worker goroutine is still running after Uninstall")
+	}
+
+	expected := "worker,plain,failing,-failing,-worker"
+	if strings.Join(log, ",") != expected {
+		t.Errorf("Lifecycle = %v, expected %s", log, expected)
+	}
+
+	if err := r.Uninstall(); err != nil {
+		t.Errorf("Second Uninstall() error = %v", err)
+	}
+}
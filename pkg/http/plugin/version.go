@@ -0,0 +1,26 @@
+package plugin
+
+import "github.com/gofiber/fiber/v2"
+
+// VersionPlugin serves the application version at GET /version
+type VersionPlugin struct {
+	version string
+}
+
+// NewVersionPlugin creates a plugin that reports the given version
+func NewVersionPlugin(version string) *VersionPlugin {
+	return &VersionPlugin{version: version}
+}
+
+// Name returns the plugin name
+func (p *VersionPlugin) Name() string {
+	return "version"
+}
+
+// Install registers the /version route
+func (p *VersionPlugin) Install(server ServerInterface) error {
+	server.GET("/version", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"version": p.version})
+	})
+	return nil
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/shngxx/point/pkg/http/hooks"
+	"github.com/shngxx/point/pkg/http/middleware"
+	"github.com/shngxx/point/pkg/http/plugin"
+	"github.com/shngxx/point/pkg/http/routing"
+)
+
+// UsePlugins installs the plugins of a registry on the server (see plugin.Registry.Install)
+// The plugins are uninstalled after the server shuts down (see plugin.Registry.Uninstall)
+//
+// Example:
+//
+//	reg := plugin.NewRegistry()
+//	reg.Register(plugin.NewVersionPlugin("1.2.0"))
+//	if err := server.UsePlugins(reg); err != nil {
+//	    return err
+//	}
+func (s *Server) UsePlugins(reg *plugin.Registry) error {
+	if err := reg.Install(pluginServer{s}); err != nil {
+		return err
+	}
+	s.AddHook(hooks.AfterShutdown, reg.Uninstall)
+	return nil
+}
+
+// pluginServer adapts Server to plugin.ServerInterface
+type pluginServer struct {
+	s *Server
+}
+
+func (p pluginServer) App() *fiber.App {
+	return p.s.App()
+}
+
+func (p pluginServer) Use(mw ...middleware.Handler) {
+	p.s.Use(mw...)
+}
+
+func (p pluginServer) GET(path string, handler routing.Handler, mw ...middleware.Handler) {
+	p.s.GET(path, Handler(handler), mw...)
+}
+
+func (p pluginServer) POST(path string, handler routing.Handler, mw ...middleware.Handler) {
+	p.s.POST(path, Handler(handler), mw...)
+}
+
+func (p pluginServer) PUT(path string, handler routing.Handler, mw ...middleware.Handler) {
+	p.s.PUT(path, Handler(handler), mw...)
+}
+
+func (p pluginServer) DELETE(path string, handler routing.Handler, mw ...middleware.Handler) {
+	p.s.DELETE(path, Handler(handler), mw...)
+}
+
+func (p pluginServer) PATCH(path string, handler routing.Handler, mw ...middleware.Handler) {
+	p.s.PATCH(path, Handler(handler), mw...)
+}
+
+func (p pluginServer) Group(prefix string, fn func(*routing.Group)) {
+	p.s.Group(prefix, fn)
+}
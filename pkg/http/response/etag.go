@@ -0,0 +1,49 @@
+package response
+
+import (
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JSONWithETag sends data as JSON with a weak ETag computed from the encoded body
+// If the request's If-None-Match matches the ETag, 304 Not Modified is sent without a body
+//
+// Example:
+//
+//	return response.JSONWithETag(c, pointInfo)
+func JSONWithETag(c *fiber.Ctx, data any) error {
+	body, err := c.App().Config().JSONEncoder(data)
+	if err != nil {
+		return err
+	}
+
+	etag := fmt.Sprintf(`W/"%d-%08x"`, len(body), crc32.ChecksumIEEE(body))
+	c.Set(fiber.HeaderETag, etag)
+
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(http.StatusNotModified)
+	}
+
+	c.Type("json")
+	return c.Send(body)
+}
+
+// etagMatches reports whether an If-None-Match header matches an ETag
+// If-None-Match uses weak comparison, so the W/ prefix is ignored
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -23,6 +23,32 @@ func Created(c *fiber.Ctx, data any) error {
 	})
 }
 
+// Paginated sends a 200 OK response with one page of items and the pagination details
+// total is the number of items across all pages
+//
+// Example:
+//
+//	page, size := http.PageParams(c)
+//	start := min((page-1)*size, len(points))
+//	end := min(start+size, len(points))
+//	return response.Paginated(c, points[start:end], page, size, len(points))
+func Paginated(c *fiber.Ctx, items any, page, pageSize, total int) error {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = (total + pageSize - 1) / pageSize
+	}
+	return c.Status(http.StatusOK).JSON(errors.PaginatedResponse{
+		Success: true,
+		Data:    items,
+		Pagination: errors.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	})
+}
+
 // BadRequest sends a 400 Bad Request response
 func BadRequest(c *fiber.Ctx, err error) error {
 	return c.Status(http.StatusBadRequest).JSON(errors.ErrorResponse{
@@ -32,29 +32,52 @@ func (g *Group) Use(mw ...middleware.Handler) {
 	}
 }
 
-// GET registers a GET route in this group
-func (g *Group) GET(path string, handler Handler) {
-	g.group.Get(path, handler)
+// GET registers a GET route in this group with optional route-level middleware
+func (g *Group) GET(path string, handler Handler, mw ...middleware.Handler) {
+	g.group.Get(path, middleware.RouteHandlers(handler, mw)...)
 }
 
-// POST registers a POST route in this group
-func (g *Group) POST(path string, handler Handler) {
-	g.group.Post(path, handler)
+// POST registers a POST route in this group with optional route-level middleware
+func (g *Group) POST(path string, handler Handler, mw ...middleware.Handler) {
+	g.group.Post(path, middleware.RouteHandlers(handler, mw)...)
 }
 
-// PUT registers a PUT route in this group
-func (g *Group) PUT(path string, handler Handler) {
-	g.group.Put(path, handler)
+// PUT registers a PUT route in this group with optional route-level middleware
+func (g *Group) PUT(path string, handler Handler, mw ...middleware.Handler) {
+	g.group.Put(path, middleware.RouteHandlers(handler, mw)...)
 }
 
-// DELETE registers a DELETE route in this group
-func (g *Group) DELETE(path string, handler Handler) {
-	g.group.Delete(path, handler)
+// DELETE registers a DELETE route in this group with optional route-level middleware
+func (g *Group) DELETE(path string, handler Handler, mw ...middleware.Handler) {
+	g.group.Delete(path, middleware.RouteHandlers(handler, mw)...)
 }
 
-// PATCH registers a PATCH route in this group
-func (g *Group) PATCH(path string, handler Handler) {
-	g.group.Patch(path, handler)
+// PATCH registers a PATCH route in this group with optional route-level middleware
+func (g *Group) PATCH(path string, handler Handler, mw ...middleware.Handler) {
+	g.group.Patch(path, middleware.RouteHandlers(handler, mw)...)
+}
+
+// HEAD registers a HEAD route in this group with optional route-level middleware
+func (g *Group) HEAD(path string, handler Handler, mw ...middleware.Handler) {
+	g.group.Head(path, middleware.RouteHandlers(handler, mw)...)
+}
+
+// OPTIONS registers an OPTIONS route in this group with optional route-level middleware
+func (g *Group) OPTIONS(path string, handler Handler, mw ...middleware.Handler) {
+	g.group.Options(path, middleware.RouteHandlers(handler, mw)...)
+}
+
+// All registers a route in this group matching every HTTP method with optional route-level middleware
+func (g *Group) All(path string, handler Handler, mw ...middleware.Handler) {
+	g.group.All(path, middleware.RouteHandlers(handler, mw)...)
+}
+
+// Add registers a route in this group for the given HTTP methods with optional route-level middleware
+func (g *Group) Add(methods []string, path string, handler Handler, mw ...middleware.Handler) {
+	handlers := middleware.RouteHandlers(handler, mw)
+	for _, method := range methods {
+		g.group.Add(method, path, handlers...)
+	}
 }
 
 // Group creates a nested route group
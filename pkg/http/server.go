@@ -1,7 +1,11 @@
 package http
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"time"
 
@@ -28,6 +32,9 @@ type ErrorHandler = httperrors.ErrorHandler
 // Validator is an alias for the validator interface
 type Validator = httpvalidation.Validator
 
+// readinessTimeout limits the time of all readiness checks of a probe
+const readinessTimeout = 5 * time.Second
+
 // Server represents the HTTP server wrapper
 type Server struct {
 	app          *fiber.App
@@ -37,8 +44,16 @@ type Server struct {
 	middleware   []middleware.Handler
 	errorHandler ErrorHandler
 	healthCheck  func() error
+	readiness    *health.Checks
 	validator    Validator
 	hookManager  *hooks.Manager
+	tlsCertFile  string
+	tlsKeyFile   string
+	tlsConfig    *tls.Config
+
+	// Context of server-bound streams (see SSEHandler), cancelled when the server shuts down
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // New creates a new Server instance with the given options
@@ -49,7 +64,9 @@ func New(opts ...Option) *Server {
 		errorHandler: httperrors.NewDefaultErrorHandler(),
 		config:       &DefaultConfig{},
 		hookManager:  hooks.NewManager(),
+		readiness:    health.NewChecks(),
 	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
 
 	// Apply options
 	for _, opt := range opts {
@@ -73,6 +90,20 @@ func New(opts ...Option) *Server {
 		ErrorHandler: s.errorHandler.Handle,
 	})
 
+	// Make the server context available to SSEHandler
+	s.app.Use(func(c *fiber.Ctx) error {
+		c.Locals(serverContextKey, s.ctx)
+		return c.Next()
+	})
+
+	// Make the validator available to BindAndValidate
+	if s.validator != nil {
+		s.app.Use(func(c *fiber.Ctx) error {
+			c.Locals(validatorKey, s.validator)
+			return c.Next()
+		})
+	}
+
 	// Register global middleware
 	for _, mw := range s.middleware {
 		s.app.Use(middleware.ToFiber(mw))
@@ -80,25 +111,72 @@ func New(opts ...Option) *Server {
 
 	// Register health check endpoints
 	s.app.Get("/health", health.LivenessHandler)
-	s.app.Get("/ready", health.ReadinessHandler(s.healthCheck))
+	if check := s.healthCheck; check != nil {
+		s.readiness.Add(health.DefaultCheck, func(context.Context) error {
+			return check()
+		})
+	}
+	s.app.Get("/ready", health.ChecksHandler(s.readiness, readinessTimeout))
 
 	return s
 }
 
 // NewWithDefaults creates a new HTTP server with default middleware stack
-// This is a convenience function that sets up Recovery, Logger, and RequestID middleware automatically
+// This is a convenience function that sets up Recovery (with panic logging), StructuredLogger, RequestID and RequestLogger middleware automatically,
+// plus Compression if cfg implements CompressionConfig and enables it
 func NewWithDefaults(cfg ServerConfig, l *zerolog.Logger) *Server {
+	mw := []middleware.Handler{
+		middleware.RecoveryWithConfig(middleware.RecoveryConfig{Logger: l}),
+		middleware.StructuredLogger(l),
+		middleware.RequestID(),
+		middleware.RequestLogger(l),
+	}
+	if c, ok := cfg.(CompressionConfig); ok && c.GetCompression() {
+		mw = append(mw, middleware.SkipWebSocket(middleware.Compression(middleware.CompressionConfig{})))
+	}
+
 	return New(
 		WithConfig(cfg),
 		WithLogger(l),
-		WithMiddleware(
-			middleware.Recovery(),
-			middleware.Logger(l),
-			middleware.RequestID(),
-		),
+		WithMiddleware(mw...),
 	)
 }
 
+// Validator returns the validator set with WithValidator, or nil
+func (s *Server) Validator() Validator {
+	return s.validator
+}
+
+// errorMapper is implemented by error handlers supporting MapError
+type errorMapper interface {
+	MapError(target error, status int)
+}
+
+// MapError makes the error handler respond with status to errors matching target (see errors.Is)
+// Only error handlers with a MapError method support it (e.g. the default one); otherwise it does nothing.
+//
+// Example:
+//
+//	server.MapError(usecase.ErrNotFound, fiber.StatusNotFound)
+func (s *Server) MapError(target error, status int) {
+	mapper, ok := s.errorHandler.(errorMapper)
+	if !ok {
+		s.logger.Warn().Int("status", status).Msg("Error handler does not support error mapping")
+		return
+	}
+	mapper.MapError(target, status)
+}
+
+// AddReadinessCheck registers a named check of the readiness probe (/ready)
+// All checks run in parallel; the server is ready only if every check passes.
+//
+// Example:
+//
+//	server.AddReadinessCheck("db", pool.Ping)
+func (s *Server) AddReadinessCheck(name string, check func(ctx context.Context) error) {
+	s.readiness.Add(name, check)
+}
+
 // Use registers global middleware
 func (s *Server) Use(mw ...middleware.Handler) {
 	for _, m := range mw {
@@ -106,29 +184,52 @@ func (s *Server) Use(mw ...middleware.Handler) {
 	}
 }
 
-// GET registers a GET route
-func (s *Server) GET(path string, handler Handler) {
-	s.app.Get(path, handler)
+// GET registers a GET route with optional route-level middleware
+func (s *Server) GET(path string, handler Handler, mw ...middleware.Handler) {
+	s.app.Get(path, middleware.RouteHandlers(handler, mw)...)
+}
+
+// POST registers a POST route with optional route-level middleware
+func (s *Server) POST(path string, handler Handler, mw ...middleware.Handler) {
+	s.app.Post(path, middleware.RouteHandlers(handler, mw)...)
+}
+
+// PUT registers a PUT route with optional route-level middleware
+func (s *Server) PUT(path string, handler Handler, mw ...middleware.Handler) {
+	s.app.Put(path, middleware.RouteHandlers(handler, mw)...)
+}
+
+// DELETE registers a DELETE route with optional route-level middleware
+func (s *Server) DELETE(path string, handler Handler, mw ...middleware.Handler) {
+	s.app.Delete(path, middleware.RouteHandlers(handler, mw)...)
 }
 
-// POST registers a POST route
-func (s *Server) POST(path string, handler Handler) {
-	s.app.Post(path, handler)
+// PATCH registers a PATCH route with optional route-level middleware
+func (s *Server) PATCH(path string, handler Handler, mw ...middleware.Handler) {
+	s.app.Patch(path, middleware.RouteHandlers(handler, mw)...)
 }
 
-// PUT registers a PUT route
-func (s *Server) PUT(path string, handler Handler) {
-	s.app.Put(path, handler)
+// HEAD registers a HEAD route with optional route-level middleware
+func (s *Server) HEAD(path string, handler Handler, mw ...middleware.Handler) {
+	s.app.Head(path, middleware.RouteHandlers(handler, mw)...)
 }
 
-// DELETE registers a DELETE route
-func (s *Server) DELETE(path string, handler Handler) {
-	s.app.Delete(path, handler)
+// OPTIONS registers an OPTIONS route with optional route-level middleware
+func (s *Server) OPTIONS(path string, handler Handler, mw ...middleware.Handler) {
+	s.app.Options(path, middleware.RouteHandlers(handler, mw)...)
 }
 
-// PATCH registers a PATCH route
-func (s *Server) PATCH(path string, handler Handler) {
-	s.app.Patch(path, handler)
+// All registers a route matching every HTTP method with optional route-level middleware
+func (s *Server) All(path string, handler Handler, mw ...middleware.Handler) {
+	s.app.All(path, middleware.RouteHandlers(handler, mw)...)
+}
+
+// Add registers a route for the given HTTP methods with optional route-level middleware
+func (s *Server) Add(methods []string, path string, handler Handler, mw ...middleware.Handler) {
+	handlers := middleware.RouteHandlers(handler, mw)
+	for _, method := range methods {
+		s.app.Add(method, path, handlers...)
+	}
 }
 
 // Group creates a new route group
@@ -146,9 +247,9 @@ func (s *Server) run() error {
 
 	// Start server in a goroutine
 	errChan := make(chan error, 1)
-		go func() {
-		s.logger.Info().Str("address", s.address).Msg("Starting server")
-		if err := s.app.Listen(s.address); err != nil {
+	go func() {
+		s.logger.Info().Str("address", s.address).Bool("tls", s.tlsEnabled()).Msg("Starting server")
+		if err := s.listen(); err != nil {
 			errChan <- err
 		}
 	}()
@@ -171,7 +272,7 @@ func (s *Server) run() error {
 	}
 
 	// Graceful shutdown
-	if err := shutdown.GracefulShutdown(s.app, s.config.GetShutdownTimeout()); err != nil {
+	if err := s.stop(); err != nil {
 		s.logger.Error().Err(err).Msg("Shutdown error")
 		return err
 	}
@@ -186,6 +287,56 @@ func (s *Server) run() error {
 	return nil
 }
 
+// listen serves requests on the server address, over TLS if configured
+func (s *Server) listen() error {
+	if !s.tlsEnabled() {
+		return s.app.Listen(s.address)
+	}
+
+	ln, err := s.tlsListener()
+	if err != nil {
+		return err
+	}
+	return s.app.Listener(ln)
+}
+
+// tlsEnabled reports whether the server is configured with WithTLS or WithTLSConfig
+func (s *Server) tlsEnabled() bool {
+	return s.tlsCertFile != "" || s.tlsConfig != nil
+}
+
+// tlsListener creates a TLS listener on the server address
+func (s *Server) tlsListener() (net.Listener, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.tlsConfig != nil {
+		cfg = s.tlsConfig.Clone()
+	}
+
+	if s.tlsCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.tlsCertFile, s.tlsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: cannot load key pair from %s and %s: %w", s.tlsCertFile, s.tlsKeyFile, err)
+		}
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+	if len(cfg.Certificates) == 0 && cfg.GetCertificate == nil {
+		return nil, errors.New("tls: no certificate configured")
+	}
+
+	// Make the client hello available to handlers (c.ClientHelloInfo), as app.ListenTLS does
+	if cfg.GetCertificate == nil {
+		tlsHandler := &fiber.TLSHandler{}
+		cfg.GetCertificate = tlsHandler.GetClientInfo
+		s.app.SetTLSHandler(tlsHandler)
+	}
+
+	ln, err := net.Listen(s.app.Config().Network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	return tls.NewListener(ln, cfg), nil
+}
+
 // Start starts the server and exits the program if an error occurs
 // This is a convenience method for applications that want to exit on server errors
 // It logs the error using the server's logger before exiting
@@ -198,6 +349,13 @@ func (s *Server) Start() {
 
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown() error {
+	return s.stop()
+}
+
+// stop cancels the server context, so open streams end instead of holding the shutdown
+// until its timeout, and gracefully shuts down the Fiber app
+func (s *Server) stop() error {
+	s.cancel()
 	return shutdown.GracefulShutdown(s.app, s.config.GetShutdownTimeout())
 }
 
@@ -0,0 +1,865 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/rs/zerolog"
+	httperrors "github.com/shngxx/point/pkg/http/errors"
+	"github.com/shngxx/point/pkg/http/middleware"
+	"github.com/shngxx/point/pkg/http/plugin"
+	"github.com/shngxx/point/pkg/http/response"
+	"github.com/shngxx/point/pkg/http/routing"
+	"github.com/shngxx/point/pkg/http/validation"
+	applog "github.com/shngxx/point/pkg/log"
+)
+
+// TestServer_FeatureFlag tests serving a route only while its feature flag is enabled
+func TestServer_FeatureFlag(t *testing.T) {
+	var enabled atomic.Bool
+
+	s := New()
+	s.POST("/api/point/:id/teleport", func(c *Context) error {
+		return c.SendStatus(200)
+	}, middleware.FeatureFlag(enabled.Load))
+
+	request := func() int {
+		t.Helper()
+		resp, err := s.App().Test(httptest.NewRequest("POST", "/api/point/1/teleport", nil))
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := request(); status != 404 {
+		t.Errorf("status with flag off = %d, expected 404", status)
+	}
+
+	enabled.Store(true)
+	if status := request(); status != 200 {
+		t.Errorf("status with flag on = %d, expected 200", status)
+	}
+
+	enabled.Store(false)
+	if status := request(); status != 404 {
+		t.Errorf("status with flag toggled off = %d, expected 404", status)
+	}
+}
+
+// movePointRequest is a request body with validation rules
+type movePointRequest struct {
+	DX   int    `json:"dx" validate:"gte=-10,lte=10"`
+	DY   int    `json:"dy" validate:"gte=-10,lte=10"`
+	Mode string `json:"mode" validate:"required,oneof=move teleport"`
+}
+
+// TestBindAndValidate tests parsing and validating request bodies with the server validator
+func TestBindAndValidate(t *testing.T) {
+	s := New(WithValidator(validation.NewStructValidator()))
+	var bound movePointRequest
+	s.POST("/move", func(c *Context) error {
+		var req movePointRequest
+		if err := BindAndValidate(c, &req); err != nil {
+			return err
+		}
+		bound = req
+		return c.SendStatus(200)
+	})
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		fields []string // Invalid fields expected in the error message
+	}{
+		{"valid", `{"dx": 1, "dy": -2, "mode": "move"}`, 200, nil},
+		{"invalid fields", `{"dx": 100, "dy": 0, "mode": "jump"}`, 400, []string{"dx", "mode"}},
+		{"missing required field", `{"dx": 1}`, 400, []string{"mode"}},
+		{"malformed JSON", `{"dx": `, 400, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/move", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := s.App().Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Status = %d, expected %d", resp.StatusCode, tt.status)
+			}
+
+			if tt.status == 200 {
+				expected := movePointRequest{DX: 1, DY: -2, Mode: "move"}
+				if bound != expected {
+					t.Errorf("Bound request = %+v, expected %+v", bound, expected)
+				}
+				return
+			}
+
+			var body httperrors.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if body.Code != httperrors.CodeBadRequest {
+				t.Errorf("Code = %q, expected %q", body.Code, httperrors.CodeBadRequest)
+			}
+			for _, field := range tt.fields {
+				if !strings.Contains(body.Error, field+":") {
+					t.Errorf("Error %q does not mention field %s", body.Error, field)
+				}
+			}
+		})
+	}
+}
+
+// TestParamInt tests reading integer path and query parameters
+func TestParamInt(t *testing.T) {
+	s := New()
+	handler := func(c *Context) error {
+		id, err := ParamInt(c, "id", 1)
+		if err != nil {
+			return err
+		}
+		limit, err := QueryInt(c, "limit", 20)
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"id": id, "limit": limit})
+	}
+	s.GET("/points/:id?", handler)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		id     int
+		limit  int
+	}{
+		{"values", "/points/5?limit=10", 200, 5, 10},
+		{"missing values use defaults", "/points", 200, 1, 20},
+		{"empty query uses default", "/points/2?limit=", 200, 2, 20},
+		{"invalid path parameter", "/points/abc", 400, 0, 0},
+		{"invalid query parameter", "/points/2?limit=ten", 400, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.App().Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Status = %d, expected %d", resp.StatusCode, tt.status)
+			}
+
+			if tt.status != 200 {
+				var body httperrors.ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				if body.Code != httperrors.CodeBadRequest {
+					t.Errorf("Code = %q, expected %q", body.Code, httperrors.CodeBadRequest)
+				}
+				return
+			}
+
+			var body struct{ ID, Limit int }
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if body.ID != tt.id || body.Limit != tt.limit {
+				t.Errorf("id, limit = %d, %d, expected %d, %d", body.ID, body.Limit, tt.id, tt.limit)
+			}
+		})
+	}
+}
+
+// TestPagination tests page parameters with defaults and caps and the paginated envelope
+func TestPagination(t *testing.T) {
+	s := New()
+	s.GET("/points", func(c *Context) error {
+		page, size := PageParams(c)
+		return response.Paginated(c, []int{}, page, size, 45)
+	})
+
+	tests := []struct {
+		name       string
+		query      string
+		page       int
+		size       int
+		totalPages int
+	}{
+		{"defaults", "", 1, DefaultPageSize, 3},
+		{"values", "?page=2&size=10", 2, 10, 5},
+		{"size capped", "?size=1000", 1, MaxPageSize, 1},
+		{"invalid values use defaults", "?page=0&size=-5", 1, DefaultPageSize, 3},
+		{"unparsable values use defaults", "?page=two&size=ten", 1, DefaultPageSize, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.App().Test(httptest.NewRequest("GET", "/points"+tt.query, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+
+			var body httperrors.PaginatedResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			expected := httperrors.Pagination{Page: tt.page, PageSize: tt.size, Total: 45, TotalPages: tt.totalPages}
+			if !body.Success || body.Pagination != expected {
+				t.Errorf("Body = %+v, expected success with pagination %+v", body, expected)
+			}
+		})
+	}
+}
+
+// listPointsParams are request parameters with validation rules
+type listPointsParams struct {
+	ID     int    `param:"id" validate:"gte=1"`
+	Limit  int    `query:"limit" validate:"gte=1,lte=100"`
+	Sort   string `query:"sort" validate:"omitempty,oneof=x y"`
+	Active bool   `query:"active"`
+}
+
+// TestBindParams tests binding and validating tagged path and query parameters
+func TestBindParams(t *testing.T) {
+	s := New(WithValidator(validation.NewStructValidator()))
+	var bound listPointsParams
+	s.GET("/points/:id", func(c *Context) error {
+		params := listPointsParams{Limit: 20}
+		if err := BindParams(c, &params); err != nil {
+			return err
+		}
+		bound = params
+		return c.SendStatus(200)
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		expected listPointsParams
+	}{
+		{"all parameters", "/points/3?limit=50&sort=y&active=true", 200, listPointsParams{ID: 3, Limit: 50, Sort: "y", Active: true}},
+		{"missing query keeps defaults", "/points/3", 200, listPointsParams{ID: 3, Limit: 20}},
+		{"invalid integer", "/points/abc", 400, listPointsParams{}},
+		{"invalid boolean", "/points/3?active=maybe", 400, listPointsParams{}},
+		{"validation failure", "/points/0?limit=500", 400, listPointsParams{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.App().Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Status = %d, expected %d", resp.StatusCode, tt.status)
+			}
+			if tt.status == 200 && bound != tt.expected {
+				t.Errorf("Bound params = %+v, expected %+v", bound, tt.expected)
+			}
+		})
+	}
+}
+
+// TestServer_UsePlugins tests serving routes registered by plugins
+func TestServer_UsePlugins(t *testing.T) {
+	reg := plugin.NewRegistry()
+	if err := reg.Register(plugin.NewVersionPlugin("1.2.0")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	s := New()
+	if err := s.UsePlugins(reg); err != nil {
+		t.Fatalf("UsePlugins() error = %v", err)
+	}
+
+	resp, err := s.App().Test(httptest.NewRequest("GET", "/version", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("Status = %d, expected 200", resp.StatusCode)
+	}
+	var body struct{ Version string }
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if body.Version != "1.2.0" {
+		t.Errorf("Version = %q, expected %q", body.Version, "1.2.0")
+	}
+}
+
+// TestLogLevelHandler tests changing the log level at runtime
+func TestLogLevelHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, setLevel, err := applog.New(applog.Config{Level: "info", Output: path})
+	if err != nil {
+		t.Fatalf("log.New() error = %v", err)
+	}
+
+	s := New()
+	s.PUT("/admin/loglevel", LogLevelHandler(setLevel))
+	putLevel := func(body string) int {
+		t.Helper()
+		req := httptest.NewRequest("PUT", "/admin/loglevel", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := s.App().Test(req)
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+		return resp.StatusCode
+	}
+	logs := func() string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		return string(data)
+	}
+
+	logger.Debug().Msg("suppressed at info")
+	if status := putLevel(`{"level": "debug"}`); status != 200 {
+		t.Fatalf("Status = %d, expected 200", status)
+	}
+	logger.Debug().Msg("visible at debug")
+
+	if status := putLevel(`{"level": "loud"}`); status != 400 {
+		t.Errorf("Status for an unknown level = %d, expected 400", status)
+	}
+	logger.Debug().Msg("still visible at debug")
+
+	if status := putLevel(`{"level": "warn"}`); status != 200 {
+		t.Fatalf("Status = %d, expected 200", status)
+	}
+	logger.Info().Msg("suppressed at warn")
+
+	got := logs()
+	for _, msg := range []string{"visible at debug", "still visible at debug"} {
+		if !strings.Contains(got, msg) {
+			t.Errorf("Logs %q do not contain %q", got, msg)
+		}
+	}
+	for _, msg := range []string{"suppressed at info", "suppressed at warn"} {
+		if strings.Contains(got, msg) {
+			t.Errorf("Logs %q contain %q", got, msg)
+		}
+	}
+}
+
+// TestSSEHandler tests streaming events until the stream ends
+func TestSSEHandler(t *testing.T) {
+	s := New()
+	s.GET("/stream", SSEHandler(func(ctx context.Context, send func(string, any) error) error {
+		for x := 1; x <= 3; x++ {
+			if err := send("position", map[string]int{"x": x, "y": 0}); err != nil {
+				return err
+			}
+		}
+		return send("", "done\nbye")
+	}))
+
+	resp, err := s.App().Test(httptest.NewRequest("GET", "/stream", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Content-Type = %q, expected text/event-stream", contentType)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	expected := "event: position\ndata: {\"x\":1,\"y\":0}\n\n" +
+		"event: position\ndata: {\"x\":2,\"y\":0}\n\n" +
+		"event: position\ndata: {\"x\":3,\"y\":0}\n\n" +
+		"data: done\ndata: bye\n\n"
+	if string(body) != expected {
+		t.Errorf("Body = %q, expected %q", body, expected)
+	}
+}
+
+// TestSSEHandler_Disconnect tests that the stream context is cancelled when the client disconnects
+func TestSSEHandler_Disconnect(t *testing.T) {
+	stopped := make(chan error, 1)
+	s := New()
+	s.GET("/stream", SSEHandler(func(ctx context.Context, send func(string, any) error) error {
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for x := 0; ; x++ {
+			select {
+			case <-ctx.Done():
+				stopped <- ctx.Err()
+				return ctx.Err()
+			case <-ticker.C:
+				// Keep sending until the disconnect is detected
+				_ = send("position", x)
+			}
+		}
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go func() { _ = s.App().Listener(ln) }()
+	defer func() { _ = s.App().Shutdown() }()
+
+	resp, err := nethttp.Get("http://" + ln.Addr().String() + "/stream")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "event: position\n" {
+		t.Fatalf("First line = %q, %v, expected an event", line, err)
+	}
+	_ = resp.Body.Close()
+
+	select {
+	case err := <-stopped:
+		if err != context.Canceled {
+			t.Errorf("Stream context error = %v, expected %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stream was not stopped after the client disconnected")
+	}
+}
+
+// TestSSEHandler_Shutdown tests that the stream context is cancelled when the server shuts down
+func TestSSEHandler_Shutdown(t *testing.T) {
+	stopped := make(chan error, 1)
+	s := New()
+	s.GET("/stream", SSEHandler(func(ctx context.Context, send func(string, any) error) error {
+		if err := send("position", 0); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return ctx.Err()
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go func() { _ = s.App().Listener(ln) }()
+
+	resp, err := nethttp.Get("http://" + ln.Addr().String() + "/stream")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "event: position\n" {
+		t.Fatalf("First line = %q, %v, expected an event", line, err)
+	}
+
+	if err := s.Shutdown(); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	select {
+	case err := <-stopped:
+		if err != context.Canceled {
+			t.Errorf("Stream context error = %v, expected %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stream was not stopped after the server shut down")
+	}
+}
+
+// TestServer_WS tests that a WebSocket route accepts upgrades, skips middleware wrapped
+// with SkipWebSocket and rejects plain requests
+func TestServer_WS(t *testing.T) {
+	var global atomic.Int32
+	s := New(WithMiddleware(
+		func(c *Context) error {
+			global.Add(1)
+			return c.Next()
+		},
+		middleware.SkipWebSocket(func(c *Context) error {
+			return fiber.ErrForbidden
+		}),
+	))
+	s.WS("/ws", func(conn *websocket.Conn) {
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go func() { _ = s.App().Listener(ln) }()
+	defer func() { _ = s.App().Shutdown() }()
+
+	client, _, err := fastws.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer client.Close()
+
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := client.WriteMessage(fastws.TextMessage, []byte("ping")); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	if _, data, err := client.ReadMessage(); err != nil || string(data) != "ping" {
+		t.Errorf("ReadMessage() = %q, %v, expected echoed ping", data, err)
+	}
+	if global.Load() != 1 {
+		t.Errorf("global middleware ran %d times, expected 1", global.Load())
+	}
+
+	resp, err := nethttp.Get("http://" + ln.Addr().String() + "/ws")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	// Plain requests still go through the skipped middleware
+	if resp.StatusCode != fiber.StatusForbidden {
+		t.Errorf("plain request status = %d, expected %d", resp.StatusCode, fiber.StatusForbidden)
+	}
+
+	plain := New()
+	plain.WS("/ws", func(*websocket.Conn) {})
+	resp, err = plain.App().Test(httptest.NewRequest("GET", "/ws", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUpgradeRequired {
+		t.Errorf("plain request status = %d, expected %d", resp.StatusCode, fiber.StatusUpgradeRequired)
+	}
+}
+
+// TestServer_Static tests serving static files with the SPA fallback without shadowing API routes
+func TestServer_Static(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":    "<html>app</html>",
+		"assets/app.js": "console.log('point')",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := New()
+	s.Static("/", dir, StaticOptions{SPAFallback: true})
+	s.GET("/api/point/:id", func(c *Context) error {
+		return c.SendString("point " + c.Params("id"))
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/assets/app.js", 200, "console.log('point')"},
+		{"/", 200, "<html>app</html>"},
+		{"/points/1/history", 200, "<html>app</html>"}, // Client-side route
+		{"/api/point/1", 200, "point 1"},
+		{"/api/unknown", 404, ""},
+		{"/ws", 404, ""},
+	}
+
+	for _, tt := range tests {
+		resp, err := s.App().Test(httptest.NewRequest("GET", tt.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test(%s) error = %v", tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status = %d, expected %d", tt.path, resp.StatusCode, tt.status)
+			continue
+		}
+		if tt.body != "" && string(body) != tt.body {
+			t.Errorf("%s: body = %q, expected %q", tt.path, body, tt.body)
+		}
+	}
+}
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1 and its key to PEM files
+func writeSelfSignedCert(t *testing.T) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "point test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, cert
+}
+
+// TestServer_TLS tests serving an HTTPS request and shutting down gracefully over TLS
+func TestServer_TLS(t *testing.T) {
+	certFile, keyFile, cert := writeSelfSignedCert(t)
+
+	s := New(
+		WithAddress("127.0.0.1:0"),
+		WithTLS(certFile, keyFile),
+		WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13}),
+	)
+	s.GET("/ping", func(c *Context) error {
+		return c.SendString("pong")
+	})
+
+	ln, err := s.tlsListener()
+	if err != nil {
+		t.Fatalf("tlsListener() error = %v", err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- s.app.Listener(ln) }()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	client := &nethttp.Client{
+		Transport: &nethttp.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}},
+		Timeout:   5 * time.Second,
+	}
+	resp, err := client.Get("https://" + ln.Addr().String() + "/ping")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "pong" || resp.TLS == nil || resp.TLS.Version != tls.VersionTLS13 {
+		t.Errorf("Response = %q over %+v, expected pong over TLS 1.3", body, resp.TLS)
+	}
+
+	if err := s.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Listener() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Server did not stop after Shutdown")
+	}
+}
+
+// TestServer_ReadinessChecks tests that the readiness probe reports every named check
+func TestServer_ReadinessChecks(t *testing.T) {
+	probe := func(s *Server) (int, map[string]any) {
+		t.Helper()
+		resp, err := s.App().Test(httptest.NewRequest("GET", "/ready", nil))
+		if err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		return resp.StatusCode, body
+	}
+
+	s := New(WithHealthCheck(func() error { return nil }))
+	s.AddReadinessCheck("db", func(ctx context.Context) error { return nil })
+	if status, body := probe(s); status != 200 || body["status"] != "ready" {
+		t.Errorf("Passing checks: %d %v, expected 200 ready", status, body)
+	}
+
+	s.AddReadinessCheck("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+	status, body := probe(s)
+	if status != 503 || body["status"] != "not ready" {
+		t.Errorf("Failing check: %d %v, expected 503 not ready", status, body)
+	}
+	expected := map[string]any{"default": "ok", "db": "ok", "redis": "connection refused"}
+	checks, _ := body["checks"].(map[string]any)
+	if len(checks) != len(expected) {
+		t.Fatalf("Checks = %v, expected %v", checks, expected)
+	}
+	for name, result := range expected {
+		if checks[name] != result {
+			t.Errorf("Check %s = %v, expected %v", name, checks[name], result)
+		}
+	}
+	if _, ok := body["error"]; ok {
+		t.Errorf("Failing named check: error = %v, expected none", body["error"])
+	}
+
+	// The WithHealthCheck error keeps its top-level field
+	s = New(WithHealthCheck(func() error { return errors.New("database unavailable") }))
+	status, body = probe(s)
+	if status != 503 || body["error"] != "database unavailable" {
+		t.Errorf("Failing health check: %d %v, expected 503 with the error", status, body)
+	}
+}
+
+// TestLoggerFrom tests that handlers log with the request ID of the request
+func TestLoggerFrom(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	s := NewWithDefaults(Config{}, &logger)
+	s.GET("/api/point/:id", func(c *Context) error {
+		LoggerFrom(c).Info().Msg("Point requested")
+		applog.FromContext(c.UserContext()).Info().Msg("Point loaded") // As a use case would
+		return c.SendString("point")
+	})
+
+	resp, err := s.App().Test(httptest.NewRequest("GET", "/api/point/1", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	requestID := resp.Header.Get("X-Request-ID")
+	if requestID == "" {
+		t.Fatal("Response has no request ID")
+	}
+
+	found := 0
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("Log entry %q is not JSON: %v", line, err)
+		}
+		if entry["message"] == "Point requested" || entry["message"] == "Point loaded" {
+			found++
+			if entry["request_id"] != requestID {
+				t.Errorf("%s log request_id = %v, expected %s", entry["message"], entry["request_id"], requestID)
+			}
+		}
+	}
+	if found != 2 {
+		t.Errorf("Handler and context log entries not found in %q", buf.String())
+	}
+
+	// Without the middleware LoggerFrom is usable but discards logs
+	bare := New()
+	bare.GET("/", func(c *Context) error {
+		LoggerFrom(c).Info().Msg("discarded")
+		return nil
+	})
+	if _, err := bare.App().Test(httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+}
+
+// TestServer_Methods tests HEAD, OPTIONS, All and Add routes on the server and on groups
+func TestServer_Methods(t *testing.T) {
+	ok := func(c *Context) error {
+		return c.SendString(c.Method())
+	}
+	groupOK := func(c *fiber.Ctx) error {
+		return c.SendString("group " + c.Method())
+	}
+
+	s := New()
+	s.HEAD("/head", ok)
+	s.OPTIONS("/options", ok)
+	s.All("/all", ok)
+	s.Add([]string{"GET", "PUT"}, "/add", ok)
+	s.Group("/api", func(g *routing.Group) {
+		g.HEAD("/head", groupOK)
+		g.OPTIONS("/options", groupOK)
+		g.All("/all", groupOK)
+		g.Add([]string{"POST"}, "/add", groupOK)
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{"HEAD", "/head", 200, ""},
+		{"OPTIONS", "/options", 200, "OPTIONS"},
+		{"GET", "/all", 200, "GET"},
+		{"DELETE", "/all", 200, "DELETE"},
+		{"GET", "/add", 200, "GET"},
+		{"PUT", "/add", 200, "PUT"},
+		{"POST", "/add", 405, ""},
+		{"HEAD", "/api/head", 200, ""},
+		{"OPTIONS", "/api/options", 200, "group OPTIONS"},
+		{"PATCH", "/api/all", 200, "group PATCH"},
+		{"POST", "/api/add", 200, "group POST"},
+		{"GET", "/api/add", 405, ""},
+	}
+
+	for _, tt := range tests {
+		resp, err := s.App().Test(httptest.NewRequest(tt.method, tt.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test(%s %s) error = %v", tt.method, tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: status = %d, expected %d", tt.method, tt.path, resp.StatusCode, tt.status)
+			continue
+		}
+		if tt.status == 200 && string(body) != tt.body {
+			t.Errorf("%s %s: body = %q, expected %q", tt.method, tt.path, body, tt.body)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shngxx/point/pkg/http/middleware"
+)
+
+// sseHeartbeat is the interval of comment lines sent to detect disconnected clients
+// while the stream has no events
+var sseHeartbeat = 15 * time.Second
+
+// serverContextKey is the context locals key of the server context
+const serverContextKey = "server_context"
+
+// SSEHandler returns a handler that streams Server-Sent Events produced by stream
+// send writes an event (an empty event name sends an unnamed "message" event) and flushes it to the client;
+// strings are sent as is, other data is encoded as JSON.
+// The stream context is cancelled when the client disconnects or the server shuts down,
+// after which send returns an error.
+// A stream error other than a cancelled context is sent to the client as an "error" event.
+//
+// Example:
+//
+//	server.GET("/api/point/:id/stream", http.SSEHandler(func(ctx context.Context, send func(string, any) error) error {
+//	    for pos := range positions(ctx) {
+//	        if err := send("position", pos); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	}))
+func SSEHandler(stream func(ctx context.Context, send func(event string, data any) error) error) Handler {
+	return func(c *Context) error {
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no") // Disable proxy buffering
+
+		// The writer runs after the handler returns, so the stream does not use the request context
+		// but the server context, which is done when the server shuts down
+		serverCtx, ok := middleware.Local[context.Context](c, serverContextKey)
+		if !ok {
+			serverCtx = context.Background()
+		}
+
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			ctx, cancel := context.WithCancel(serverCtx)
+			defer cancel()
+
+			var mu sync.Mutex // serializes writes of events and heartbeats
+			write := func(frame string) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if _, err := w.WriteString(frame); err != nil {
+					cancel()
+					return err
+				}
+				if err := w.Flush(); err != nil {
+					// Client disconnected
+					cancel()
+					return err
+				}
+				return nil
+			}
+
+			go func() {
+				ticker := time.NewTicker(sseHeartbeat)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						_ = write(":\n\n")
+					}
+				}
+			}()
+
+			send := func(event string, data any) error {
+				frame, err := sseFrame(event, data)
+				if err != nil {
+					return err
+				}
+				return write(frame)
+			}
+
+			if err := stream(ctx, send); err != nil && ctx.Err() == nil {
+				_ = send("error", err.Error())
+			}
+		})
+		return nil
+	}
+}
+
+// sseFrame formats an event in the text/event-stream format
+func sseFrame(event string, data any) (string, error) {
+	payload, ok := data.(string)
+	if !ok {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return "", fmt.Errorf("error encoding event data: %w", err)
+		}
+		payload = string(encoded)
+	}
+
+	var b strings.Builder
+	if event != "" {
+		b.WriteString("event: " + event + "\n")
+	}
+	for _, line := range strings.Split(payload, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
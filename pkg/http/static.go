@@ -0,0 +1,74 @@
+package http
+
+import (
+	nethttp "net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+)
+
+// StaticOptions contains options for serving static files
+type StaticOptions struct {
+	Index  string // Index file of directories (default: index.html)
+	MaxAge int    // Cache-Control max-age in seconds (default: 0, no header)
+	Browse bool   // Enable directory browsing
+
+	// SPAFallback serves the index file for paths without a file,
+	// so client-side routing of single-page applications works
+	SPAFallback bool
+
+	// Exclude lists path prefixes that are never served from rootDir (default: /api and /ws),
+	// so static files and the SPA fallback do not shadow API and WebSocket routes
+	Exclude []string
+}
+
+// index returns the index file with default fallback
+func (o StaticOptions) index() string {
+	if o.Index != "" {
+		return o.Index
+	}
+	return "index.html"
+}
+
+// exclude returns the excluded path prefixes with default fallback
+func (o StaticOptions) exclude() []string {
+	if o.Exclude != nil {
+		return o.Exclude
+	}
+	return []string{"/api", "/ws"}
+}
+
+// Static serves files from rootDir under urlPrefix using Fiber's filesystem middleware
+//
+// Example:
+//
+//	server.Static("/", "./web/dist", http.StaticOptions{SPAFallback: true})
+func (s *Server) Static(urlPrefix, rootDir string, opts StaticOptions) {
+	exclude := opts.exclude()
+	cfg := filesystem.Config{
+		Root:   nethttp.Dir(rootDir),
+		Browse: opts.Browse,
+		Index:  opts.index(),
+		MaxAge: opts.MaxAge,
+		Next: func(c *fiber.Ctx) bool {
+			return excluded(c.Path(), exclude)
+		},
+	}
+	if opts.SPAFallback {
+		cfg.NotFoundFile = opts.index()
+	}
+
+	s.app.Use(urlPrefix, filesystem.New(cfg))
+}
+
+// excluded reports whether a path is one of the prefixes or below it
+func excluded(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shngxx/point/pkg/internal/fielderr"
+)
+
+// StructValidator validates structures using `validate:"..."` struct tags
+// (see github.com/go-playground/validator for the available rules)
+// Fields are reported by their JSON names
+type StructValidator struct {
+	validate *validator.Validate
+}
+
+// NewStructValidator creates a new struct tag validator
+func NewStructValidator() *StructValidator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		default:
+			return name
+		}
+	})
+	return &StructValidator{validate: v}
+}
+
+// Validate validates a structure and returns an error listing every invalid field, or nil
+func (v *StructValidator) Validate(data any) error {
+	err := v.validate.Struct(data)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fields := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, fmt.Sprintf("%s: does not satisfy %q", fielderr.Path(fieldErr), fielderr.Rule(fieldErr)))
+	}
+	return errors.New(strings.Join(fields, "; "))
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// WS registers a WebSocket route
+// Requests that are not WebSocket upgrades are rejected with 426 Upgrade Required.
+// Global middleware runs before the upgrade; wrap middleware that does not apply to
+// upgrades (e.g. compression or timeouts) with middleware.SkipWebSocket.
+//
+// Example:
+//
+//	server.WS("/ws", wsManager.HandleConnection, wsManager.UpgradeConfig())
+func (s *Server) WS(path string, handler func(*websocket.Conn), cfg ...websocket.Config) {
+	s.app.Get(path, func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return fiber.ErrUpgradeRequired
+		}
+		return c.Next()
+	}, websocket.New(handler, cfg...))
+}
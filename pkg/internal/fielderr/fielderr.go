@@ -0,0 +1,27 @@
+// Package fielderr describes the field errors of github.com/go-playground/validator
+// the same way for configuration (pkg/config) and request (pkg/http/validation) validation
+package fielderr
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Path returns the path of an invalid field without the root structure name (e.g. "server.port")
+// Fields are named by the tag name function of the validator
+func Path(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// Rule returns the failed rule with its parameter (e.g. "gte=1")
+func Rule(fieldErr validator.FieldError) string {
+	if fieldErr.Param() != "" {
+		return fieldErr.Tag() + "=" + fieldErr.Param()
+	}
+	return fieldErr.Tag()
+}
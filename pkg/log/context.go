@@ -0,0 +1,37 @@
+package log
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// loggerKey is the context key of the request-scoped logger
+type loggerKey struct{}
+
+// nop is returned by FromContext when the context has no logger
+var nop = zerolog.Nop()
+
+// WithContext returns a copy of ctx that carries logger
+// Use it to pass a logger with request-scoped fields (request ID, user ID) to use cases
+//
+// Example:
+//
+//	logger := base.With().Str("request_id", id).Logger()
+//	ctx = log.WithContext(ctx, &logger)
+func WithContext(ctx context.Context, logger *zerolog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by WithContext, or a no-op logger
+func FromContext(ctx context.Context) *zerolog.Logger {
+	return FromContextOr(ctx, &nop)
+}
+
+// FromContextOr returns the logger stored in ctx by WithContext, or fallback
+func FromContextOr(ctx context.Context, fallback *zerolog.Logger) *zerolog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zerolog.Logger); ok && logger != nil {
+		return logger
+	}
+	return fallback
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelSetter changes the minimum level of a logger created by New at runtime
+// (debug, info, warn, error, ...); it returns an error for an unknown level
+type LevelSetter func(level string) error
+
+// levelSampler filters events below a level that can be changed concurrently
+// zerolog fixes a logger's level at creation, so New creates loggers at trace level
+// and filters events with this sampler instead. Unlike a hook, a sampler is consulted
+// before the event is created, so disabled levels cost no allocation (e.g. logger.Debug()
+// returns nil). It is inherited by child loggers; note that zerolog.DisableSampling disables it too.
+type levelSampler struct {
+	level atomic.Int32
+}
+
+// newLevelSampler creates a sampler with the given minimum level
+func newLevelSampler(level zerolog.Level) *levelSampler {
+	s := &levelSampler{}
+	s.level.Store(int32(level))
+	return s
+}
+
+// Sample implements the zerolog.Sampler interface
+func (s *levelSampler) Sample(level zerolog.Level) bool {
+	return level >= zerolog.Level(s.level.Load())
+}
+
+// setLevel implements LevelSetter
+func (s *levelSampler) setLevel(level string) error {
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	s.level.Store(int32(parsed))
+	return nil
+}
@@ -1,10 +1,14 @@
 package log
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/rs/zerolog"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Config holds configuration for logger
@@ -27,11 +31,55 @@ type Config struct {
 	SentrySampleRate float64 `koanf:"sentrySampleRate"`
 
 	// PrettyPrint enables pretty-printed JSON output (useful for development)
+	// Ignored for file output, which is always plain JSON
 	PrettyPrint bool `koanf:"prettyPrint"`
+
+	// Output is where logs are written: "stderr", "stdout" or a file path
+	// Default: stderr
+	Output string `koanf:"output"`
+
+	// MaxSizeMB is the size of a log file at which it is rotated (file output only)
+	// Default: 100
+	MaxSizeMB int `koanf:"maxSizeMB"`
+
+	// MaxBackups is the number of rotated log files to keep (file output only)
+	// Default: 0 (keep all)
+	MaxBackups int `koanf:"maxBackups"`
+
+	// MaxAgeDays is the number of days to keep rotated log files (file output only)
+	// Default: 0 (no age limit)
+	MaxAgeDays int `koanf:"maxAgeDays"`
+
+	// Compress enables gzip compression of rotated log files (file output only)
+	Compress bool `koanf:"compress"`
+}
+
+// isFile reports whether logs are written to a file
+func (c Config) isFile() bool {
+	return c.Output != "" && c.Output != "stderr" && c.Output != "stdout"
+}
+
+// output returns the writer for the configured output
+func (c Config) output() io.Writer {
+	switch {
+	case c.Output == "stdout":
+		return os.Stdout
+	case c.isFile():
+		return &lumberjack.Logger{
+			Filename:   c.Output,
+			MaxSize:    c.MaxSizeMB,
+			MaxBackups: c.MaxBackups,
+			MaxAge:     c.MaxAgeDays,
+			Compress:   c.Compress,
+		}
+	default:
+		return os.Stderr
+	}
 }
 
 // New creates a new zerolog.Logger with the given configuration and optional Sentry integration
-func New(cfg Config) (*zerolog.Logger, error) {
+// The returned LevelSetter changes the logger level at runtime (e.g. from http.LogLevelHandler)
+func New(cfg Config) (*zerolog.Logger, LevelSetter, error) {
 	// Set log level
 	level := zerolog.InfoLevel
 	if cfg.Level != "" {
@@ -43,18 +91,9 @@ func New(cfg Config) (*zerolog.Logger, error) {
 	}
 
 	// Configure output
-	var logger zerolog.Logger
-	if cfg.PrettyPrint {
-		output := zerolog.ConsoleWriter{Out: os.Stderr}
-		logger = zerolog.New(output).With().
-			Timestamp().
-			Logger().
-			Level(level)
-	} else {
-		logger = zerolog.New(os.Stderr).With().
-			Timestamp().
-			Logger().
-			Level(level)
+	output := cfg.output()
+	if cfg.PrettyPrint && !cfg.isFile() {
+		output = zerolog.ConsoleWriter{Out: output}
 	}
 
 	// Initialize Sentry if DSN is provided
@@ -71,14 +110,21 @@ func New(cfg Config) (*zerolog.Logger, error) {
 		}
 
 		if err := sentry.Init(sentryOptions); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		// Add Sentry hook to logger
-		logger = logger.Hook(&SentryHook{})
+		// Send error events to Sentry as well
+		output = zerolog.MultiLevelWriter(output, &SentryWriter{})
 	}
 
-	return &logger, nil
+	sampler := newLevelSampler(level)
+	logger := zerolog.New(output).With().
+		Timestamp().
+		Logger().
+		Level(zerolog.TraceLevel).
+		Sample(sampler)
+
+	return &logger, sampler.setLevel, nil
 }
 
 // MustNew creates a new zerolog.Logger with the given configuration
@@ -86,7 +132,7 @@ func New(cfg Config) (*zerolog.Logger, error) {
 // This is a convenience function for cases where logger initialization failure
 // should cause the program to terminate immediately
 func MustNew(cfg Config) *zerolog.Logger {
-	logger, err := New(cfg)
+	logger, _, err := New(cfg)
 	if err != nil {
 		// Use standard log package for fatal error since logger failed to initialize
 		// This prevents infinite recursion if logger initialization itself fails
@@ -95,18 +141,80 @@ func MustNew(cfg Config) *zerolog.Logger {
 	return logger
 }
 
-// SentryHook is a zerolog hook that sends errors to Sentry
-type SentryHook struct{}
-
-// Run implements the zerolog.Hook interface
-func (h *SentryHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
-	if level >= zerolog.ErrorLevel && sentry.CurrentHub().Client() != nil {
-		sentry.WithScope(func(scope *sentry.Scope) {
-			// Try to extract error from event
-			// Note: zerolog.Event doesn't expose fields directly in hook,
-			// so we capture the message. For better error tracking, use
-			// logger.Error().Err(err).Msg("message") which will be captured by hook
-			sentry.CaptureMessage(msg)
-		})
+// SentryWriter is a zerolog writer that sends error, fatal and panic events to Sentry
+// zerolog hooks cannot read event fields, so the writer parses the JSON event instead:
+// an attached error (logger.Error().Err(err)) is captured as an exception, other events as a message,
+// and the message and remaining fields are attached as extras
+type SentryWriter struct {
+	// Hub is the Sentry hub to capture events with (default: sentry.CurrentHub())
+	Hub *sentry.Hub
+}
+
+// Write implements io.Writer; events without a level are ignored
+func (w *SentryWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter
+func (w *SentryWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < zerolog.ErrorLevel || level > zerolog.PanicLevel {
+		return len(p), nil
+	}
+
+	hub := w.Hub
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	if hub.Client() == nil {
+		return len(p), nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return len(p), nil // Not a JSON event, nothing to capture
+	}
+
+	message, _ := fields[zerolog.MessageFieldName].(string)
+	errMessage, hasError := fields[zerolog.ErrorFieldName].(string)
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.TimestampFieldName)
+	delete(fields, zerolog.ErrorFieldName)
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetLevel(sentryLevel(level))
+		scope.SetExtras(fields)
+		if hasError {
+			hub.CaptureException(errors.New(errMessage))
+		} else {
+			hub.CaptureMessage(message)
+		}
+	})
+	return len(p), nil
+}
+
+// SentryHook is a zerolog hook that sends error, fatal and panic events to Sentry
+//
+// Deprecated: hooks cannot read event fields, so only the message is captured.
+// Use SentryWriter instead.
+type SentryHook struct {
+	// Hub is the Sentry hub to capture events with (default: sentry.CurrentHub())
+	Hub *sentry.Hub
+}
+
+// Run implements the zerolog.Hook interface by forwarding the message to SentryWriter
+func (h *SentryHook) Run(_ *zerolog.Event, level zerolog.Level, msg string) {
+	event, err := json.Marshal(map[string]string{zerolog.MessageFieldName: msg})
+	if err != nil {
+		return
+	}
+	writer := SentryWriter{Hub: h.Hub}
+	_, _ = writer.WriteLevel(level, event)
+}
+
+// sentryLevel maps a zerolog error, fatal or panic level to a Sentry level
+func sentryLevel(level zerolog.Level) sentry.Level {
+	if level >= zerolog.FatalLevel {
+		return sentry.LevelFatal
 	}
+	return sentry.LevelError
 }
@@ -0,0 +1,163 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/rs/zerolog"
+)
+
+// TestNew_FileRotation tests rotating the log file once it exceeds its size limit
+func TestNew_FileRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	logger, _, err := New(Config{Output: path, MaxSizeMB: 1, PrettyPrint: true})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Write a little more than 1 MB
+	message := strings.Repeat("x", 1024)
+	for i := 0; i < 1100; i++ {
+		logger.Info().Int("i", i).Msg(message)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) < 2 {
+		t.Fatalf("Log files = %d, expected the current file and a rotated one", len(entries))
+	}
+
+	// File output is plain JSON even with PrettyPrint
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Errorf("Log line %.40q... is not JSON: %v", line, err)
+	}
+}
+
+// TestNew_LevelSetter tests that disabled levels create no events
+// and that the level can be changed at runtime
+func TestNew_LevelSetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, setLevel, err := New(Config{Output: path, Level: "info"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if e := logger.Debug(); e != nil {
+		t.Error("Debug() returned an event at info level")
+	}
+	child := logger.With().Str("component", "test").Logger()
+	if e := child.Debug(); e != nil {
+		t.Error("Debug() of a child logger returned an event at info level")
+	}
+	logger.Info().Msg("info")
+
+	if err := setLevel("debug"); err != nil {
+		t.Fatalf("setLevel() error = %v", err)
+	}
+	child.Debug().Msg("debug")
+	if err := setLevel("verbose"); err == nil {
+		t.Error("setLevel(\"verbose\") error = nil, expected error")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 || !strings.Contains(string(data), `"message":"debug"`) {
+		t.Errorf("Log = %q, expected the info and debug events", data)
+	}
+}
+
+// mockTransport records the events sent to Sentry
+type mockTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *mockTransport) Configure(sentry.ClientOptions)        {}
+func (t *mockTransport) Flush(time.Duration) bool              { return true }
+func (t *mockTransport) FlushWithContext(context.Context) bool { return true }
+func (t *mockTransport) Close()                                {}
+func (t *mockTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+// TestSentryWriter tests capturing logged errors as Sentry exceptions with the event fields
+func TestSentryWriter(t *testing.T) {
+	transport := &mockTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	logger := zerolog.New(&SentryWriter{Hub: hub})
+	logger.Info().Msg("not sent")
+	logger.Error().Err(errors.New("connection refused")).Int("point_id", 7).Msg("Failed to save point")
+	logger.WithLevel(zerolog.FatalLevel).Msg("no error attached") // WithLevel does not exit
+
+	if len(transport.events) != 2 {
+		t.Fatalf("Sent events = %d, expected 2", len(transport.events))
+	}
+
+	event := transport.events[0]
+	if len(event.Exception) == 0 || event.Exception[len(event.Exception)-1].Value != "connection refused" {
+		t.Fatalf("Exception = %+v, expected the logged error", event.Exception)
+	}
+	if event.Level != sentry.LevelError {
+		t.Errorf("Level = %q, expected %q", event.Level, sentry.LevelError)
+	}
+	if event.Extra["message"] != "Failed to save point" || event.Extra["point_id"] != float64(7) {
+		t.Errorf("Extra = %v, expected the message and point_id", event.Extra)
+	}
+
+	event = transport.events[1]
+	if len(event.Exception) != 0 || event.Message != "no error attached" {
+		t.Errorf("Event = exception %+v, message %q, expected a message", event.Exception, event.Message)
+	}
+	if event.Level != sentry.LevelFatal {
+		t.Errorf("Level = %q, expected %q", event.Level, sentry.LevelFatal)
+	}
+}
+
+// TestSentryHook tests that the deprecated hook still captures logged errors as messages
+func TestSentryHook(t *testing.T) {
+	transport := &mockTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	logger := zerolog.New(io.Discard).Hook(&SentryHook{Hub: hub})
+	logger.Info().Msg("not sent")
+	logger.Error().Msg("Failed to save point")
+
+	if len(transport.events) != 1 {
+		t.Fatalf("Sent events = %d, expected 1", len(transport.events))
+	}
+	if event := transport.events[0]; event.Message != "Failed to save point" || event.Level != sentry.LevelError {
+		t.Errorf("Event = message %q, level %q, expected the logged error", event.Message, event.Level)
+	}
+}
@@ -4,6 +4,12 @@ import (
 	"time"
 )
 
+// defaultBufferSize is the read/write buffer size used when a config returns an invalid one
+const defaultBufferSize = 4096
+
+// defaultMaxMessageSize is the default maximum size of an incoming message
+const defaultMaxMessageSize = 64 * 1024
+
 // ManagerConfig defines the interface for WebSocket manager configuration
 // Implementations should provide WebSocket settings without binding to specific config libraries
 type ManagerConfig interface {
@@ -24,6 +30,16 @@ type ManagerConfig interface {
 
 	// GetShutdownTimeout returns the graceful shutdown timeout duration
 	GetShutdownTimeout() time.Duration
+
+	// GetIdleTimeout returns how long a client may send nothing, pongs included, before it is disconnected (0 = disabled)
+	GetIdleTimeout() time.Duration
+
+	// GetWriteTimeout returns how long a single message write may take before the connection is closed
+	GetWriteTimeout() time.Duration
+
+	// GetMaxMessageSize returns the maximum size of an incoming message in bytes
+	// Connections sending larger messages are closed with 1009 (message too big)
+	GetMaxMessageSize() int
 }
 
 // Config represents WebSocket manager configuration that can be loaded via pkg/config
@@ -35,6 +51,9 @@ type Config struct {
 	WriteBufferSize       int `koanf:"writeBufferSize"`       // in bytes
 	MaxConnectionsPerRoom int `koanf:"maxConnectionsPerRoom"` // 0 = unlimited
 	ShutdownTimeout       int `koanf:"shutdownTimeout"`       // in seconds
+	IdleTimeout           int `koanf:"idleTimeout"`           // in seconds, 0 = disabled
+	WriteTimeout          int `koanf:"writeTimeout"`          // in seconds
+	MaxMessageSize        int `koanf:"maxMessageSize"`        // in bytes
 }
 
 // GetPingInterval returns the ping interval
@@ -82,6 +101,30 @@ func (c *Config) GetShutdownTimeout() time.Duration {
 	return 30 * time.Second // Default: 30 seconds
 }
 
+// GetIdleTimeout returns the idle timeout
+func (c *Config) GetIdleTimeout() time.Duration {
+	if c.IdleTimeout > 0 {
+		return time.Duration(c.IdleTimeout) * time.Second
+	}
+	return 0 // Default: disabled
+}
+
+// GetWriteTimeout returns the write timeout
+func (c *Config) GetWriteTimeout() time.Duration {
+	if c.WriteTimeout > 0 {
+		return time.Duration(c.WriteTimeout) * time.Second
+	}
+	return 10 * time.Second // Default: 10 seconds
+}
+
+// GetMaxMessageSize returns the maximum incoming message size
+func (c *Config) GetMaxMessageSize() int {
+	if c.MaxMessageSize > 0 {
+		return c.MaxMessageSize
+	}
+	return defaultMaxMessageSize // Default: 64KB
+}
+
 // DefaultConfig provides default WebSocket manager configuration values
 type DefaultConfig struct {
 	PingInterval          time.Duration
@@ -90,6 +133,9 @@ type DefaultConfig struct {
 	WriteBufferSize       int
 	MaxConnectionsPerRoom int
 	ShutdownTimeout       time.Duration
+	IdleTimeout           time.Duration
+	WriteTimeout          time.Duration
+	MaxMessageSize        int
 }
 
 // GetPingInterval returns the ping interval
@@ -136,3 +182,27 @@ func (c *DefaultConfig) GetShutdownTimeout() time.Duration {
 	}
 	return 30 * time.Second
 }
+
+// GetIdleTimeout returns the idle timeout
+func (c *DefaultConfig) GetIdleTimeout() time.Duration {
+	if c.IdleTimeout > 0 {
+		return c.IdleTimeout
+	}
+	return 0
+}
+
+// GetWriteTimeout returns the write timeout
+func (c *DefaultConfig) GetWriteTimeout() time.Duration {
+	if c.WriteTimeout > 0 {
+		return c.WriteTimeout
+	}
+	return 10 * time.Second
+}
+
+// GetMaxMessageSize returns the maximum incoming message size
+func (c *DefaultConfig) GetMaxMessageSize() int {
+	if c.MaxMessageSize > 0 {
+		return c.MaxMessageSize
+	}
+	return defaultMaxMessageSize
+}
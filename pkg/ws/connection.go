@@ -3,16 +3,56 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	fastws "github.com/fasthttp/websocket"
 	"github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"github.com/shngxx/point/pkg/ws/hooks"
 )
 
+// closeTimeout bounds how long CloseWithCode waits for the peer's close response
+const closeTimeout = time.Second
+
+// WritePolicy defines what WriteJSON does when the connection's write buffer is full
+type WritePolicy int
+
+const (
+	// DropNewest drops the message being written (default)
+	DropNewest WritePolicy = iota
+
+	// DropOldest drops the oldest queued message to make room for the new one
+	DropOldest
+
+	// Block waits until there is room in the buffer or the connection is closed
+	Block
+
+	// CloseOnFull closes the connection (slow consumer) and returns ErrWriteBufferFull
+	CloseOnFull
+)
+
+// frame is a WebSocket data frame with its message type (text or binary)
+type frame struct {
+	messageType int
+	data        []byte
+}
+
+// flushMarker is queued behind pending messages; writeLoop closes it once
+// everything queued before it has been written
+type flushMarker chan struct{}
+
 // Connection wraps websocket.Conn with enhanced functionality
 type Connection struct {
-	conn   *websocket.Conn
-	logger *zerolog.Logger
+	id          string
+	conn        *websocket.Conn
+	logger      *zerolog.Logger
+	config      ManagerConfig
+	writePolicy WritePolicy
 
 	// Metadata storage
 	metadata   map[string]any
@@ -27,62 +67,202 @@ type Connection struct {
 	cancel context.CancelFunc
 
 	// Message channels
-	readChan  chan []byte
+	readChan  chan frame
 	writeChan chan any
 	errorChan chan error
 
 	// Connection state
-	closed   bool
-	closedMu sync.RWMutex
+	closed    bool
+	closedMu  sync.RWMutex
+	closeDone chan struct{} // closed when the close handshake has finished
+	cause     string        // disconnect cause passed to OnDisconnect (e.g. hooks.DisconnectKicked)
+
+	// Tracks read/write/ping goroutines, which must stop before the
+	// underlying websocket.Conn is released by the upgrade handler
+	wg       sync.WaitGroup
+	started  atomic.Bool
+	readDone chan struct{} // closed when readLoop exits
+
+	// Unix nanoseconds of the last message or ping received from the client
+	lastActivity atomic.Int64
 }
 
 // NewConnection creates a new Connection wrapper
-func NewConnection(conn *websocket.Conn, logger *zerolog.Logger) *Connection {
+// If config is nil, DefaultConfig is used
+func NewConnection(conn *websocket.Conn, logger *zerolog.Logger, config ManagerConfig, writePolicy WritePolicy) *Connection {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	if config == nil {
+		config = &DefaultConfig{}
+	}
+
 	return &Connection{
-		conn:      conn,
-		logger:    logger,
-		metadata:  make(map[string]any),
-		rooms:     make(map[string]bool),
-		ctx:       ctx,
-		cancel:    cancel,
-		readChan:  make(chan []byte, 256),
-		writeChan: make(chan any, 256),
-		errorChan: make(chan error, 1),
+		id:          uuid.NewString(),
+		conn:        conn,
+		logger:      logger,
+		config:      config,
+		writePolicy: writePolicy,
+		metadata:    make(map[string]any),
+		rooms:       make(map[string]bool),
+		ctx:         ctx,
+		cancel:      cancel,
+		readChan:    make(chan frame, 256),
+		writeChan:   make(chan any, 256),
+		errorChan:   make(chan error, 1),
+		readDone:    make(chan struct{}),
+		closeDone:   make(chan struct{}),
 	}
 }
 
-// Start starts the connection handlers (read and write goroutines)
+// Start starts the connection handlers (read, write, ping and idle watchdog goroutines)
 func (c *Connection) Start(ctx context.Context) {
+	c.started.Store(true)
+	c.touch()
+	c.wg.Add(3)
+
 	// Start read goroutine
-	go c.readLoop()
+	go func() {
+		defer c.wg.Done()
+		c.readLoop()
+	}()
 
 	// Start write goroutine
-	go c.writeLoop()
+	go func() {
+		defer c.wg.Done()
+		c.writeLoop()
+	}()
+
+	// Start keepalive goroutine
+	go func() {
+		defer c.wg.Done()
+		c.pingLoop()
+	}()
+
+	// Start idle watchdog goroutine
+	if c.config.GetIdleTimeout() > 0 {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.idleLoop()
+		}()
+	}
+}
+
+// touch records client activity
+func (c *Connection) touch() {
+	c.lastActivity.Store(time.Now().UnixNano())
+}
+
+// idleLoop closes the connection once the client has been idle for the idle timeout
+// Activity is any data message, ping or pong from the client, including pongs answering
+// the server's keepalive pings (see the pong handler in readLoop)
+func (c *Connection) idleLoop() {
+	timeout := c.config.GetIdleTimeout()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-timer.C:
+			idle := time.Since(time.Unix(0, c.lastActivity.Load()))
+			if idle < timeout {
+				timer.Reset(timeout - idle)
+				continue
+			}
+			c.logger.Debug().Dur("idle", idle).Msg("WebSocket connection idle, closing")
+			c.setDisconnectCause(hooks.DisconnectIdle)
+			c.CloseWithCode(websocket.CloseNormalClosure, "idle timeout")
+			return
+		}
+	}
+}
+
+// wait blocks until all connection goroutines have stopped
+func (c *Connection) wait() {
+	c.wg.Wait()
+}
+
+// pongWait returns how long the connection may stay silent before it is considered dead:
+// one ping interval plus the time allowed for the pong to arrive
+func (c *Connection) pongWait() time.Duration {
+	return c.config.GetPingInterval() + c.config.GetPongTimeout()
+}
+
+// pingLoop periodically sends ping control frames to the peer
+// Missing pongs are detected by the read deadline in readLoop
+func (c *Connection) pingLoop() {
+	ticker := time.NewTicker(c.config.GetPingInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			// WriteControl is safe to call concurrently with writeLoop
+			deadline := time.Now().Add(c.config.GetPongTimeout())
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				c.logger.Debug().Err(err).Msg("WebSocket ping failed, closing connection")
+				c.Close()
+				return
+			}
+		}
+	}
 }
 
 // readLoop continuously reads messages from the WebSocket connection
 func (c *Connection) readLoop() {
+	defer close(c.readDone)
 	defer close(c.readChan)
 	defer close(c.errorChan)
 
+	// Larger messages fail the read; the library answers them with a 1009 close frame
+	c.conn.SetReadLimit(int64(c.config.GetMaxMessageSize()))
+
+	// A connection that misses a pong fails the next read with a timeout,
+	// which closes it through the manager's normal disconnect path
+	c.conn.SetReadDeadline(time.Now().Add(c.pongWait()))
+	c.conn.SetPongHandler(func(string) error {
+		c.touch()
+		return c.conn.SetReadDeadline(time.Now().Add(c.pongWait()))
+	})
+	// Client pings count as activity like pongs; reply like the default ping handler
+	c.conn.SetPingHandler(func(appData string) error {
+		c.touch()
+		err := c.conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(time.Second))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			return nil
+		}
+		return err
+	})
+
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
 		default:
-			_, message, err := c.conn.ReadMessage()
+			messageType, message, err := c.conn.ReadMessage()
 			if err != nil {
-				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				// gofiber/websocket re-declares ErrReadLimit, the underlying connection returns fasthttp's
+				if errors.Is(err, fastws.ErrReadLimit) {
+					c.logger.Warn().Int("limit", c.config.GetMaxMessageSize()).Msg("Message too large, closing connection")
+					c.setDisconnectCause(hooks.DisconnectMessageTooLarge)
+				} else if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					c.logger.Error().Err(err).Msg("WebSocket read error")
 				}
 				c.errorChan <- err
 				return
 			}
 
+			c.touch()
+
 			select {
-			case c.readChan <- message:
+			case c.readChan <- frame{messageType: messageType, data: message}:
 			case <-c.ctx.Done():
 				return
 			}
@@ -101,10 +281,18 @@ func (c *Connection) writeLoop() {
 				return
 			}
 
+			if marker, ok := msg.(flushMarker); ok {
+				close(marker)
+				continue
+			}
+
+			messageType := websocket.TextMessage
 			var data []byte
 			var err error
 
 			switch v := msg.(type) {
+			case frame:
+				messageType, data = v.messageType, v.data
 			case []byte:
 				data = v
 			case string:
@@ -117,31 +305,103 @@ func (c *Connection) writeLoop() {
 				}
 			}
 
-			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
-				c.logger.Error().Err(err).Msg("WebSocket write error")
+			// A client that stops reading fills its TCP buffer and blocks the write,
+			// so every write is bounded and a timed out connection is closed
+			c.conn.SetWriteDeadline(time.Now().Add(c.config.GetWriteTimeout()))
+			if err := c.conn.WriteMessage(messageType, data); err != nil {
+				var ne net.Error
+				if errors.As(err, &ne) && ne.Timeout() {
+					c.logger.Warn().Err(err).Str("conn", c.id).Msg("WebSocket write timed out, closing connection")
+					c.setDisconnectCause(hooks.DisconnectWriteTimeout)
+					c.Close()
+					return
+				}
+				// The connection has no writer left: close it so that it is unregistered
+				// and senders blocked on a full write buffer are released
+				c.logger.Error().Err(err).Str("conn", c.id).Msg("WebSocket write error, closing connection")
+				c.setDisconnectCause(hooks.DisconnectWriteError)
+				c.Close()
 				return
 			}
 		}
 	}
 }
 
-// ReadJSON reads a JSON message from the connection
-func (c *Connection) ReadJSON(v any) error {
+// ReadMessage reads the next message from the connection along with its frame type
+// (websocket.TextMessage or websocket.BinaryMessage)
+func (c *Connection) ReadMessage() (messageType int, data []byte, err error) {
 	select {
 	case <-c.ctx.Done():
-		return c.ctx.Err()
-	case message, ok := <-c.readChan:
+		return 0, nil, c.ctx.Err()
+	case f, ok := <-c.readChan:
 		if !ok {
-			return websocket.ErrCloseSent
+			return 0, nil, websocket.ErrCloseSent
 		}
-		return json.Unmarshal(message, v)
+		return f.messageType, f.data, nil
 	case err := <-c.errorChan:
+		return 0, nil, err
+	}
+}
+
+// ReadJSON reads a JSON message from the connection
+func (c *Connection) ReadJSON(v any) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
 		return err
 	}
+	return json.Unmarshal(data, v)
 }
 
-// WriteJSON writes a JSON message to the connection
+// WriteJSON writes a JSON message to the connection as a text frame
+// When the write buffer is full, the connection's WritePolicy decides what happens
 func (c *Connection) WriteJSON(v any) error {
+	return c.enqueue(v)
+}
+
+// WriteJSONContext writes a JSON message to the connection, waiting for room in the
+// write buffer until ctx or the connection is done, regardless of the WritePolicy.
+// Use it for messages that must not be dropped (e.g. control messages).
+func (c *Connection) WriteJSONContext(ctx context.Context, v any) error {
+	if c.isClosed() {
+		return websocket.ErrCloseSent
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	case c.writeChan <- v:
+		return nil
+	}
+}
+
+// WriteAck acknowledges the message with the given correlation ID (Message.ID)
+// The client receives {"id": requestID, "ack": true, "data": result}
+func (c *Connection) WriteAck(requestID string, result any) error {
+	return c.WriteJSON(Ack{ID: requestID, Ack: true, Data: result})
+}
+
+// WriteBinary writes data to the connection as a binary frame
+// When the write buffer is full, the connection's WritePolicy decides what happens
+func (c *Connection) WriteBinary(data []byte) error {
+	return c.enqueue(frame{messageType: websocket.BinaryMessage, data: data})
+}
+
+// errDropped is returned by send for a message dropped by the DropNewest policy
+var errDropped = errors.New("write buffer full, message dropped")
+
+// enqueue queues a message for writeLoop, applying the WritePolicy when the buffer is full
+// Messages dropped by the DropNewest policy are not reported as errors
+func (c *Connection) enqueue(v any) error {
+	if err := c.send(v); !errors.Is(err, errDropped) {
+		return err
+	}
+	return nil
+}
+
+// send queues a message for writeLoop like enqueue, returning errDropped for a dropped message
+func (c *Connection) send(v any) error {
 	if c.isClosed() {
 		return websocket.ErrCloseSent
 	}
@@ -152,26 +412,129 @@ func (c *Connection) WriteJSON(v any) error {
 	case c.writeChan <- v:
 		return nil
 	default:
-		// Channel is full, message dropped
+	}
+
+	// Channel is full
+	switch c.writePolicy {
+	case DropOldest:
+		for {
+			select {
+			case <-c.ctx.Done():
+				return c.ctx.Err()
+			case c.writeChan <- v:
+				return nil
+			default:
+			}
+			// Make room by dropping the oldest queued message
+			select {
+			case <-c.writeChan:
+				c.logger.Warn().Msg("Write channel full, oldest message dropped")
+			default:
+			}
+		}
+	case Block:
+		select {
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		case c.writeChan <- v:
+			return nil
+		}
+	case CloseOnFull:
+		c.logger.Warn().Msg("Write channel full, closing slow connection")
+		// Close asynchronously: the close handshake may take up to closeTimeout
+		go c.CloseWithCode(websocket.CloseTryAgainLater, "write buffer full")
+		return ErrWriteBufferFull
+	default:
 		c.logger.Warn().Msg("Write channel full, message dropped")
-		return nil
+		return errDropped
 	}
 }
 
-// Close closes the connection
+// Close closes the connection with a normal closure (1000) handshake
 func (c *Connection) Close() error {
+	return c.CloseWithCode(websocket.CloseNormalClosure, "")
+}
+
+// CloseWithCode performs a WebSocket close handshake and closes the connection:
+// it sends a close frame with the given code and reason, waits for the peer's
+// close response (bounded by a timeout), then closes the underlying connection.
+// Concurrent calls block until the first one has finished.
+func (c *Connection) CloseWithCode(code int, reason string) error {
+	return c.closeWithTimeout(code, reason, closeTimeout)
+}
+
+// flush waits until all messages queued so far have been written or the timeout expires
+func (c *Connection) flush(timeout time.Duration) {
+	if !c.started.Load() {
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	marker := make(flushMarker)
+	select {
+	case c.writeChan <- marker:
+	case <-c.ctx.Done():
+		return
+	case <-timer.C:
+		return
+	}
+
+	select {
+	case <-marker:
+	case <-c.ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// closeWithTimeout performs the close handshake, waiting up to timeout for the peer's response
+func (c *Connection) closeWithTimeout(code int, reason string, timeout time.Duration) error {
 	c.closedMu.Lock()
 	if c.closed {
 		c.closedMu.Unlock()
+		<-c.closeDone
 		return nil
 	}
 	c.closed = true
 	c.closedMu.Unlock()
+	defer close(c.closeDone)
+
+	if c.conn == nil {
+		// Not backed by a socket (e.g. in tests)
+		c.cancel()
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	msg := websocket.FormatCloseMessage(code, reason)
+	if err := c.conn.WriteControl(websocket.CloseMessage, msg, deadline); err == nil && c.started.Load() {
+		// readLoop exits once the peer's close frame arrives
+		select {
+		case <-c.readDone:
+		case <-time.After(time.Until(deadline)):
+			c.logger.Debug().Msg("Timed out waiting for WebSocket close response")
+		}
+	}
 
 	c.cancel()
 	return c.conn.Close()
 }
 
+// setDisconnectCause records why the connection is being closed
+func (c *Connection) setDisconnectCause(cause string) {
+	c.closedMu.Lock()
+	defer c.closedMu.Unlock()
+	c.cause = cause
+}
+
+// disconnectCause returns the recorded disconnect cause, if any
+func (c *Connection) disconnectCause() string {
+	c.closedMu.RLock()
+	defer c.closedMu.RUnlock()
+	return c.cause
+}
+
 // isClosed checks if the connection is closed
 func (c *Connection) isClosed() bool {
 	c.closedMu.RLock()
@@ -179,6 +542,11 @@ func (c *Connection) isClosed() bool {
 	return c.closed
 }
 
+// ID returns the unique connection identifier, stable for the lifetime of the connection
+func (c *Connection) ID() string {
+	return c.id
+}
+
 // Context returns the connection's context
 func (c *Connection) Context() context.Context {
 	return c.ctx
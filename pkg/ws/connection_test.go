@@ -0,0 +1,520 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/rs/zerolog"
+	"github.com/shngxx/point/pkg/ws/hooks"
+)
+
+// newTestServer starts a Fiber app serving the manager on /ws and returns its WebSocket URL
+func newTestServer(t *testing.T, m *Manager) string {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws", websocket.New(m.HandleConnection))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		m.Shutdown()
+		app.Shutdown()
+	})
+
+	return "ws://" + ln.Addr().String() + "/ws"
+}
+
+// dialTestServer connects a WebSocket client to the given URL
+func dialTestServer(t *testing.T, url string) *fastws.Conn {
+	t.Helper()
+
+	client, _, err := fastws.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// waitFor polls cond until it returns true or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
+
+// TestConnection_PingPong tests that healthy clients are kept alive
+// and clients that miss pongs are disconnected
+func TestConnection_PingPong(t *testing.T) {
+	m := NewManager(WithConfig(&DefaultConfig{
+		PingInterval: 30 * time.Millisecond,
+		PongTimeout:  30 * time.Millisecond,
+	}))
+	url := newTestServer(t, m)
+
+	// Responsive client: reading processes pings and replies with pongs automatically
+	responsive := dialTestServer(t, url)
+	pings := make(chan struct{}, 100)
+	responsive.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return responsive.WriteControl(fastws.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			if _, _, err := responsive.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Silent client: never reads, so it never answers pings
+	dialTestServer(t, url)
+
+	if !waitFor(t, time.Second, func() bool { return m.GetConnectionCount() == 2 }) {
+		t.Fatalf("GetConnectionCount() = %d, expected 2", m.GetConnectionCount())
+	}
+
+	// The silent client misses its pong and is cleaned up, the responsive one stays
+	if !waitFor(t, time.Second, func() bool { return m.GetConnectionCount() == 1 }) {
+		t.Fatalf("GetConnectionCount() = %d, expected 1 after missed pong", m.GetConnectionCount())
+	}
+	time.Sleep(200 * time.Millisecond)
+	if count := m.GetConnectionCount(); count != 1 {
+		t.Errorf("GetConnectionCount() = %d, expected responsive client to stay connected", count)
+	}
+	if len(pings) == 0 {
+		t.Error("responsive client did not receive any ping")
+	}
+}
+
+// TestConnection_NilConfig tests that NewConnection falls back to default configuration
+func TestConnection_NilConfig(t *testing.T) {
+	conn := newTestConnection()
+	if conn.pongWait() != 70*time.Second {
+		t.Errorf("pongWait() = %v, expected 70s", conn.pongWait())
+	}
+	if conn.config.GetPingInterval() != 60*time.Second {
+		t.Errorf("GetPingInterval() = %v, expected 60s", conn.config.GetPingInterval())
+	}
+}
+
+// TestConnection_CloseWithCode tests that a close frame is sent and the handshake
+// completes before the underlying connection is torn down
+func TestConnection_CloseWithCode(t *testing.T) {
+	connected := make(chan *Connection, 1)
+	m := NewManager(WithHook(hooks.OnConnect, func(conn hooks.ConnectionInterface, data ...any) error {
+		connected <- conn.(*Connection)
+		return nil
+	}))
+	url := newTestServer(t, m)
+	client := dialTestServer(t, url)
+
+	var serverConn *Connection
+	select {
+	case serverConn = <-connected:
+	case <-time.After(time.Second):
+		t.Fatal("connection was not established")
+	}
+
+	// The client echoes the close frame while reading
+	closeErr := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				closeErr <- err
+				return
+			}
+		}
+	}()
+
+	start := time.Now()
+	serverConn.CloseWithCode(4000, "bye")
+	if elapsed := time.Since(start); elapsed >= closeTimeout {
+		t.Errorf("CloseWithCode() took %v, expected the handshake to complete before the timeout", elapsed)
+	}
+
+	select {
+	case err := <-closeErr:
+		if !fastws.IsCloseError(err, 4000) {
+			t.Fatalf("client read error = %v, expected close frame with code 4000", err)
+		}
+		if ce := err.(*fastws.CloseError); ce.Text != "bye" {
+			t.Errorf("close reason = %q, expected bye", ce.Text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("client did not observe the close")
+	}
+}
+
+// newFullConnection creates a socketless connection with the given policy and a full write buffer
+func newFullConnection(policy WritePolicy) *Connection {
+	nop := zerolog.Nop()
+	conn := NewConnection(nil, &nop, nil, policy)
+	for i := 0; i < cap(conn.writeChan); i++ {
+		conn.writeChan <- i
+	}
+	return conn
+}
+
+// TestWritePolicy tests the behavior of each write policy when the buffer is full
+func TestWritePolicy(t *testing.T) {
+	t.Run("DropNewest", func(t *testing.T) {
+		conn := newFullConnection(DropNewest)
+		if err := conn.WriteJSON("new"); err != nil {
+			t.Fatalf("WriteJSON() error = %v", err)
+		}
+		if first := <-conn.writeChan; first != 0 {
+			t.Errorf("first queued message = %v, expected 0", first)
+		}
+		for len(conn.writeChan) > 0 {
+			if msg := <-conn.writeChan; msg == "new" {
+				t.Error("new message should have been dropped")
+			}
+		}
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		conn := newFullConnection(DropOldest)
+		if err := conn.WriteJSON("new"); err != nil {
+			t.Fatalf("WriteJSON() error = %v", err)
+		}
+		if first := <-conn.writeChan; first != 1 {
+			t.Errorf("first queued message = %v, expected 1 (oldest dropped)", first)
+		}
+		var last any
+		for len(conn.writeChan) > 0 {
+			last = <-conn.writeChan
+		}
+		if last != "new" {
+			t.Errorf("last queued message = %v, expected new", last)
+		}
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		conn := newFullConnection(Block)
+		done := make(chan error, 1)
+		go func() { done <- conn.WriteJSON("new") }()
+
+		select {
+		case <-done:
+			t.Fatal("WriteJSON() should block while the buffer is full")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		<-conn.writeChan
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("WriteJSON() error = %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("WriteJSON() did not unblock after room was made")
+		}
+
+		// A blocked write returns once the connection is closed
+		go func() { done <- conn.WriteJSON("another") }()
+		conn.cancel()
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Error("WriteJSON() should return error when the connection is closed")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("WriteJSON() did not respect the connection context")
+		}
+	})
+
+	t.Run("CloseOnFull", func(t *testing.T) {
+		conn := newFullConnection(CloseOnFull)
+		if err := conn.WriteJSON("new"); err != ErrWriteBufferFull {
+			t.Fatalf("WriteJSON() error = %v, expected ErrWriteBufferFull", err)
+		}
+		select {
+		case <-conn.Context().Done():
+		case <-time.After(time.Second):
+			t.Fatal("connection was not closed")
+		}
+	})
+}
+
+// TestConnection_BinaryFrames tests that binary frames reach the binary handler unparsed
+// and WriteBinary emits binary frames, while text frames are still routed as JSON
+func TestConnection_BinaryFrames(t *testing.T) {
+	m := NewManager()
+	m.HandleBinary(func(conn *Connection, data []byte) error {
+		// Echo the payload back reversed
+		reply := make([]byte, len(data))
+		for i, b := range data {
+			reply[len(data)-1-i] = b
+		}
+		return conn.WriteBinary(reply)
+	})
+	m.HandleMessage("ping", func(conn *Connection, msg *Message) error {
+		return conn.WriteJSON(map[string]string{"type": "pong"})
+	})
+
+	client := dialTestServer(t, newTestServer(t, m))
+	client.SetReadDeadline(time.Now().Add(time.Second))
+
+	// Not valid JSON: would be ignored without the binary handler
+	if err := client.WriteMessage(fastws.BinaryMessage, []byte{0x01, 0x02, 0xff}); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	messageType, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if messageType != fastws.BinaryMessage {
+		t.Errorf("message type = %d, expected binary", messageType)
+	}
+	if string(data) != string([]byte{0xff, 0x02, 0x01}) {
+		t.Errorf("data = %v, expected [255 2 1]", data)
+	}
+
+	if err := client.WriteJSON(Message{Action: "ping"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	messageType, data, err = client.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if messageType != fastws.TextMessage || string(data) != `{"type":"pong"}` {
+		t.Errorf("received (%d, %s), expected text pong", messageType, data)
+	}
+}
+
+// TestConnection_WriteJSONContext tests that WriteJSONContext waits for room in a full
+// buffer until the context deadline
+func TestConnection_WriteJSONContext(t *testing.T) {
+	conn := newFullConnection(DropNewest)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := conn.WriteJSONContext(ctx, "control"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WriteJSONContext() on full buffer error = %v, expected deadline exceeded", err)
+	}
+
+	// Room is made while the write is waiting
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-conn.writeChan
+	}()
+	if err := conn.WriteJSONContext(context.Background(), "control"); err != nil {
+		t.Fatalf("WriteJSONContext() error = %v", err)
+	}
+
+	// The message is queued behind the pending ones
+	var last any
+	for len(conn.writeChan) > 0 {
+		last = <-conn.writeChan
+	}
+	if last != "control" {
+		t.Errorf("last queued message = %v, expected control", last)
+	}
+
+	conn.Close()
+	if err := conn.WriteJSONContext(context.Background(), "control"); err == nil {
+		t.Error("WriteJSONContext() on closed connection should return error")
+	}
+}
+
+// TestConnection_IdleTimeout tests that silent clients are disconnected while clients
+// sending messages or pings stay connected
+func TestConnection_IdleTimeout(t *testing.T) {
+	causes := make(chan any, 3)
+	m := NewManager(
+		WithConfig(&DefaultConfig{IdleTimeout: 150 * time.Millisecond}),
+		WithHook(hooks.OnDisconnect, func(conn hooks.ConnectionInterface, data ...any) error {
+			if len(data) > 0 {
+				causes <- data[0]
+			}
+			return nil
+		}),
+	)
+	m.HandleMessage("noop", func(conn *Connection, msg *Message) error { return nil })
+	url := newTestServer(t, m)
+
+	silent := dialTestServer(t, url)
+	active := dialTestServer(t, url)
+	pinging := dialTestServer(t, url)
+
+	closed := make(chan error, 1)
+	go func() {
+		silent.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, _, err := silent.ReadMessage()
+		closed <- err
+	}()
+
+	// Stay active for well over the idle timeout
+	for i := 0; i < 10; i++ {
+		if err := active.WriteJSON(Message{Action: "noop"}); err != nil {
+			t.Fatalf("active: WriteJSON() error = %v", err)
+		}
+		if err := pinging.WriteControl(fastws.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+			t.Fatalf("pinging: WriteControl() error = %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	select {
+	case err := <-closed:
+		var ce *fastws.CloseError
+		if !errors.As(err, &ce) || ce.Code != fastws.CloseNormalClosure || ce.Text != "idle timeout" {
+			t.Errorf("silent client read error = %v, expected idle timeout close", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("silent client was not disconnected")
+	}
+
+	select {
+	case cause := <-causes:
+		if cause != hooks.DisconnectIdle {
+			t.Errorf("OnDisconnect cause = %v, expected %q", cause, hooks.DisconnectIdle)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnDisconnect hook was not executed with a cause")
+	}
+
+	if n := m.GetConnectionCount(); n != 2 {
+		t.Errorf("GetConnectionCount() = %d, expected 2 (active and pinging)", n)
+	}
+}
+
+// TestConnection_IdleTimeoutPong tests that pongs answering the server's keepalive pings
+// count as activity, so a client that only answers pings is not idle
+func TestConnection_IdleTimeoutPong(t *testing.T) {
+	m := NewManager(WithConfig(&DefaultConfig{
+		IdleTimeout:  150 * time.Millisecond,
+		PingInterval: 50 * time.Millisecond,
+	}))
+	url := newTestServer(t, m)
+
+	client := dialTestServer(t, url)
+	closed := make(chan error, 1)
+	go func() {
+		// Reading makes the client answer pings with pongs
+		client.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, _, err := client.ReadMessage()
+		closed <- err
+	}()
+
+	select {
+	case err := <-closed:
+		t.Fatalf("client answering pings was disconnected: %v", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+	if n := m.GetConnectionCount(); n != 1 {
+		t.Errorf("GetConnectionCount() = %d, expected 1", n)
+	}
+}
+
+// TestConnection_WriteTimeout tests that a client that stops reading is disconnected
+// once a write blocks for longer than the write timeout
+func TestConnection_WriteTimeout(t *testing.T) {
+	causes := make(chan any, 1)
+	connected := make(chan *Connection, 1)
+	m := NewManager(
+		WithConfig(&DefaultConfig{WriteTimeout: 100 * time.Millisecond}),
+		WithWritePolicy(Block),
+		WithHook(hooks.OnConnect, func(conn hooks.ConnectionInterface, data ...any) error {
+			connected <- conn.(*Connection)
+			return nil
+		}),
+		WithHook(hooks.OnDisconnect, func(conn hooks.ConnectionInterface, data ...any) error {
+			if len(data) > 0 {
+				causes <- data[0]
+			}
+			return nil
+		}),
+	)
+
+	// The client never reads, so the socket buffers fill up and writes block
+	dialTestServer(t, newTestServer(t, m))
+	conn := <-connected
+
+	payload := make([]byte, 1<<20)
+	go func() {
+		for conn.WriteBinary(payload) == nil {
+		}
+	}()
+
+	select {
+	case cause := <-causes:
+		if cause != hooks.DisconnectWriteTimeout {
+			t.Errorf("OnDisconnect cause = %v, expected %q", cause, hooks.DisconnectWriteTimeout)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Blocked connection was not torn down")
+	}
+
+	if !waitFor(t, time.Second, func() bool { return m.GetConnectionCount() == 0 }) {
+		t.Errorf("GetConnectionCount() = %d, expected 0", m.GetConnectionCount())
+	}
+}
+
+// TestConnection_MaxMessageSize tests that overlong actions are rejected and a message
+// over the maximum size closes the connection with 1009 (message too big)
+func TestConnection_MaxMessageSize(t *testing.T) {
+	causes := make(chan any, 1)
+	m := NewManager(
+		WithConfig(&DefaultConfig{MaxMessageSize: 1024}),
+		WithHook(hooks.OnDisconnect, func(conn hooks.ConnectionInterface, data ...any) error {
+			if len(data) > 0 {
+				causes <- data[0]
+			}
+			return nil
+		}),
+	)
+	m.HandleMessage(strings.Repeat("a", MaxActionLength+1), func(conn *Connection, msg *Message) error {
+		t.Error("overlong action was routed")
+		return nil
+	})
+	client := dialTestServer(t, newTestServer(t, m))
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	if err := client.WriteJSON(Message{Action: strings.Repeat("a", MaxActionLength+1)}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var resp map[string]any
+	if err := client.ReadJSON(&resp); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if resp["error"] != ErrActionTooLong.Message {
+		t.Errorf("response = %v, expected %q", resp, ErrActionTooLong.Message)
+	}
+
+	payload := `{"action":"move","data":"` + strings.Repeat("x", 2048) + `"}`
+	if err := client.WriteMessage(fastws.TextMessage, []byte(payload)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	if _, _, err := client.ReadMessage(); !fastws.IsCloseError(err, fastws.CloseMessageTooBig) {
+		t.Fatalf("read error = %v, expected message too big close (1009)", err)
+	}
+
+	select {
+	case cause := <-causes:
+		if cause != hooks.DisconnectMessageTooLarge {
+			t.Errorf("OnDisconnect cause = %v, expected %q", cause, hooks.DisconnectMessageTooLarge)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Connection was not torn down")
+	}
+}
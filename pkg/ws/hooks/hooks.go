@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"context"
+	"sync"
 )
 
 // ConnectionInterface defines the interface for a WebSocket connection
@@ -35,19 +36,43 @@ const (
 	OnError HookType = "on_error"
 
 	// OnJoinRoom is called when a connection joins a room
+	// Data: roomID (string), room size after joining (int)
 	OnJoinRoom HookType = "on_join_room"
 
 	// OnLeaveRoom is called when a connection leaves a room
+	// Data: roomID (string), room size after leaving (int)
 	OnLeaveRoom HookType = "on_leave_room"
 )
 
+// DisconnectKicked is passed as the first OnDisconnect data argument
+// when the connection was removed with Manager.Kick
+const DisconnectKicked = "kicked"
+
+// DisconnectIdle is passed as the first OnDisconnect data argument
+// when the connection was closed by the idle timeout
+const DisconnectIdle = "idle"
+
+// DisconnectWriteTimeout is passed as the first OnDisconnect data argument
+// when the connection was closed because a write exceeded the write timeout (slow consumer)
+const DisconnectWriteTimeout = "write_timeout"
+
+// DisconnectWriteError is passed as the first OnDisconnect data argument
+// when the connection was closed because a write failed for a reason other than the write timeout
+const DisconnectWriteError = "write_error"
+
+// DisconnectMessageTooLarge is passed as the first OnDisconnect data argument
+// when the connection was closed for sending a message over the maximum message size
+const DisconnectMessageTooLarge = "message_too_large"
+
 // HookFunc is a function that can be registered as a lifecycle hook
 // It receives the connection and optional context data
 type HookFunc func(conn ConnectionInterface, data ...any) error
 
 // Manager manages lifecycle hooks
+// Hooks may be added while other hooks are being executed
 type Manager struct {
 	hooks map[HookType][]HookFunc
+	mu    sync.RWMutex
 }
 
 // NewManager creates a new hook manager
@@ -59,6 +84,8 @@ func NewManager() *Manager {
 
 // Add registers a hook function for the given hook type
 func (m *Manager) Add(hookType HookType, fn HookFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.hooks == nil {
 		m.hooks = make(map[HookType][]HookFunc)
 	}
@@ -68,7 +95,10 @@ func (m *Manager) Add(hookType HookType, fn HookFunc) {
 // Execute runs all hooks of the given type in order
 // Returns the first error encountered, if any
 func (m *Manager) Execute(hookType HookType, conn ConnectionInterface, data ...any) error {
+	// Hooks run outside of the lock so they may register other hooks
+	m.mu.RLock()
 	hooks, ok := m.hooks[hookType]
+	m.mu.RUnlock()
 	if !ok {
 		return nil
 	}
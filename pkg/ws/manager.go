@@ -3,12 +3,19 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gofiber/websocket/v2"
 	"github.com/rs/zerolog"
 	"github.com/shngxx/point/pkg/ws/hooks"
 	"github.com/shngxx/point/pkg/ws/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Manager represents the WebSocket connection manager
@@ -18,29 +25,46 @@ type Manager struct {
 	middleware  []middleware.Handler
 	hookManager *hooks.Manager
 	router      *Router
+	writePolicy WritePolicy
+	tracer      trace.Tracer // Optional, see WithTracer
+
+	// Middleware run around routing of each message, see WithMessageMiddleware
+	messageMiddleware []MessageMiddleware
 
 	// Connection management
-	connections map[*Connection]bool
+	connections map[string]*Connection // keyed by Connection.ID()
 	connMu      sync.RWMutex
 
 	// Room management
-	rooms  map[string]*Room
-	roomMu sync.RWMutex
+	// Lock ordering: roomMu before Room.clientsMu before Connection.roomsMu.
+	// Hooks are executed without holding roomMu.
+	rooms            map[string]*Room
+	roomMu           sync.RWMutex
+	roomHistoryLimit int
+	broadcastWorkers int
 
 	// Shutdown
-	shutdown     chan struct{}
-	shutdownOnce sync.Once
+	shutdown        chan struct{}
+	shutdownOnce    sync.Once
+	draining        chan struct{} // closed when new connections are no longer accepted
+	drainOnce       sync.Once
+	drainOnShutdown bool
+	drainNotice     any
 }
 
+// ShutdownNotice is the notice sent to clients before a drain close, e.g. WithDrainOnShutdown(ws.ShutdownNotice)
+var ShutdownNotice = map[string]string{"type": "server_shutdown"}
+
 // NewManager creates a new WebSocket manager instance with the given options
 func NewManager(opts ...Option) *Manager {
 	nop := zerolog.Nop()
 	m := &Manager{
 		logger:      &nop,
 		config:      &DefaultConfig{},
-		connections: make(map[*Connection]bool),
+		connections: make(map[string]*Connection),
 		rooms:       make(map[string]*Room),
 		shutdown:    make(chan struct{}),
+		draining:    make(chan struct{}),
 		hookManager: hooks.NewManager(),
 		router:      NewRouter(),
 	}
@@ -65,38 +89,67 @@ func NewManagerWithDefaults(l *zerolog.Logger) *Manager {
 	)
 }
 
+// UpgradeConfig returns the websocket upgrader configuration built from the manager config
+// Pass it to websocket.New so the configured buffer sizes are applied:
+//
+//	app.Get("/ws", websocket.New(m.HandleConnection, m.UpgradeConfig()))
+func (m *Manager) UpgradeConfig() websocket.Config {
+	readBufferSize := m.config.GetReadBufferSize()
+	if readBufferSize <= 0 {
+		readBufferSize = defaultBufferSize
+	}
+	writeBufferSize := m.config.GetWriteBufferSize()
+	if writeBufferSize <= 0 {
+		writeBufferSize = defaultBufferSize
+	}
+
+	return websocket.Config{
+		ReadBufferSize:  readBufferSize,
+		WriteBufferSize: writeBufferSize,
+	}
+}
+
 // HandleConnection handles a new WebSocket connection
 // This is the entry point for new connections from Fiber
 func (m *Manager) HandleConnection(c *websocket.Conn) {
-	// Check if manager is shutting down
+	// Check if manager is shutting down or draining; clients are told to try again later (1013)
 	select {
 	case <-m.shutdown:
-		c.Close()
+		rejectConnection(c, websocket.CloseTryAgainLater, "server shutting down")
+		return
+	case <-m.draining:
+		rejectConnection(c, websocket.CloseTryAgainLater, "server draining")
 		return
 	default:
 	}
 
 	// Create connection wrapper
-	conn := NewConnection(c, m.logger)
+	conn := NewConnection(c, m.logger, m.config, m.writePolicy)
 
 	// Apply middleware
 	for _, mw := range m.middleware {
 		if err := mw(conn); err != nil {
 			m.logger.Error().Err(err).Msg("Middleware error")
-			conn.Close()
+			m.reportError(conn, err)
+			// No-op if the middleware already closed the connection with its own code
+			conn.CloseWithCode(websocket.ClosePolicyViolation, "connection rejected")
 			return
 		}
 	}
 
 	// Register connection
 	m.connMu.Lock()
-	m.connections[conn] = true
+	m.connections[conn.ID()] = conn
 	m.connMu.Unlock()
 
 	// Execute OnConnect hook
 	if err := m.hookManager.Execute(hooks.OnConnect, conn); err != nil {
 		m.logger.Error().Err(err).Msg("OnConnect hook failed")
-		conn.Close()
+		// No-op if the hook already closed the connection with its own code
+		conn.CloseWithCode(websocket.CloseInternalServerErr, "connection setup failed")
+		m.leaveAllRooms(conn)
+		m.unregister(conn)
+		conn.wait()
 		return
 	}
 
@@ -104,18 +157,21 @@ func (m *Manager) HandleConnection(c *websocket.Conn) {
 
 	// Defer cleanup
 	defer func() {
-		// Execute OnDisconnect hook
-		m.hookManager.Execute(hooks.OnDisconnect, conn)
+		// Execute OnDisconnect hook, passing the cause if one was recorded
+		var data []any
+		if cause := conn.disconnectCause(); cause != "" {
+			data = append(data, cause)
+		}
+		m.hookManager.Execute(hooks.OnDisconnect, conn, data...)
 
 		// Remove from all rooms
 		m.leaveAllRooms(conn)
 
-		// Unregister connection
-		m.connMu.Lock()
-		delete(m.connections, conn)
-		m.connMu.Unlock()
+		m.unregister(conn)
 
 		conn.Close()
+		// The underlying websocket.Conn is released when this handler returns
+		conn.wait()
 		m.logger.Info().Msg("WebSocket connection closed")
 	}()
 
@@ -126,7 +182,56 @@ func (m *Manager) HandleConnection(c *websocket.Conn) {
 	m.handleMessages(conn)
 }
 
+// unregister removes a connection from the registered connections
+func (m *Manager) unregister(conn *Connection) {
+	m.connMu.Lock()
+	delete(m.connections, conn.ID())
+	m.connMu.Unlock()
+}
+
+// reportError executes the OnError hook with the originating error as the first data argument.
+// A failing hook is only logged: it never prevents the error response or connection teardown
+// that follows the error.
+func (m *Manager) reportError(conn *Connection, err error) {
+	if hookErr := m.hookManager.Execute(hooks.OnError, conn, err); hookErr != nil {
+		m.logger.Warn().Err(hookErr).Msg("OnError hook failed")
+	}
+}
+
+// allowMessage reports whether the connection may send another message
+// according to the limiter attached by middleware.RateLimit, if any
+func (m *Manager) allowMessage(conn *Connection) bool {
+	limiter, ok := conn.GetMetadata(middleware.RateLimiterKey)
+	if !ok {
+		return true
+	}
+	l, ok := limiter.(interface{ Allow() bool })
+	return !ok || l.Allow()
+}
+
+// isCloseError reports whether err is part of a normal connection teardown
+func isCloseError(err error) bool {
+	return websocket.IsCloseError(err,
+		websocket.CloseNormalClosure,
+		websocket.CloseGoingAway,
+		websocket.CloseNoStatusReceived,
+		websocket.CloseAbnormalClosure,
+	) || errors.Is(err, websocket.ErrCloseSent) || errors.Is(err, context.Canceled)
+}
+
+// rejectConnection sends a close frame to a connection that is not accepted and closes it
+func rejectConnection(c *websocket.Conn, code int, reason string) {
+	c.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	c.Close()
+}
+
 // handleMessages handles incoming messages from a connection
+//
+// Errors are reported through the OnError hook:
+//   - messages dropped by the rate limit (ErrRateLimited)
+//   - read errors other than a normal close (the connection is then closed)
+//   - invalid JSON messages (the message is skipped)
+//   - routing/handler errors: the hook runs before the error response is sent to the client
 func (m *Manager) handleMessages(conn *Connection) {
 	for {
 		select {
@@ -135,42 +240,105 @@ func (m *Manager) handleMessages(conn *Connection) {
 		case <-conn.Context().Done():
 			return
 		default:
-			var msg Message
-			if err := conn.ReadJSON(&msg); err != nil {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
 				// Check if it's a connection close error
-				if websocket.IsCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				if isCloseError(err) {
 					return
 				}
-				// For JSON parse errors, log and continue (might be ping/pong or empty message)
-				if _, ok := err.(*json.SyntaxError); ok || err.Error() == "unexpected end of JSON input" {
+				// For other errors, close connection
+				m.reportError(conn, err)
+				return
+			}
+
+			// Drop messages over the rate limit attached by middleware.RateLimit
+			if !m.allowMessage(conn) {
+				m.logger.Debug().Str("conn", conn.ID()).Msg("Rate limit exceeded, message dropped")
+				m.reportError(conn, ErrRateLimited)
+				continue
+			}
+
+			// Binary frames bypass JSON parsing when a binary handler is registered
+			if handler, ok := m.router.BinaryHandler(); ok && messageType == websocket.BinaryMessage {
+				if err := handler(conn, data); err != nil {
+					m.logger.Error().Err(err).Msg("Binary message handler error")
+					m.reportError(conn, err)
+					conn.WriteJSON(map[string]any{"error": err.Error()})
+				}
+				continue
+			}
+
+			var msg Message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				// Malformed JSON: log and continue (might be an empty message)
+				var syntaxErr *json.SyntaxError
+				if errors.As(err, &syntaxErr) {
 					m.logger.Debug().Err(err).Msg("Invalid JSON message received, ignoring")
+					m.reportError(conn, err)
 					continue
 				}
-				// For other errors, close connection
+				// Valid JSON that is not a message (e.g. a non-string action): close connection
+				m.reportError(conn, err)
 				return
 			}
+			msg.raw = data
 
 			// Skip empty messages
 			if msg.Action == "" && msg.Type == "" {
 				continue
 			}
 
-			// Execute OnMessage hook
-			if err := m.hookManager.Execute(hooks.OnMessage, conn, &msg); err != nil {
-				m.logger.Error().Err(err).Msg("OnMessage hook failed")
+			// Oversized actions never match a handler but would be logged and traced as is
+			if len(msg.Action) > MaxActionLength || len(msg.Type) > MaxActionLength {
+				m.reportError(conn, ErrActionTooLong)
+				conn.WriteJSON(map[string]any{"error": ErrActionTooLong.Error()})
 				continue
 			}
 
-			// Route message
-			if err := m.router.Route(conn, &msg); err != nil {
-				m.logger.Error().Err(err).Msg("Message routing error")
-				// Send error response to client
-				errorMsg := map[string]any{
-					"error": err.Error(),
-				}
-				conn.WriteJSON(errorMsg)
-			}
+			m.routeMessage(conn, &msg)
+		}
+	}
+}
+
+// routeMessage runs the OnMessage hook and routes a message through the message middleware,
+// in a span if the manager has a tracer
+func (m *Manager) routeMessage(conn *Connection, msg *Message) {
+	msg.ctx = conn.Context()
+	var span trace.Span
+	if m.tracer != nil {
+		msg.ctx, span = m.tracer.Start(msg.ctx, "ws "+msg.Action,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("ws.action", msg.Action),
+				attribute.String("ws.connection_id", conn.ID()),
+				attribute.String("ws.message_id", msg.ID),
+			),
+		)
+		defer span.End()
+	}
+
+	// Execute OnMessage hook
+	if err := m.hookManager.Execute(hooks.OnMessage, conn, msg); err != nil {
+		m.logger.Error().Err(err).Msg("OnMessage hook failed")
+		return
+	}
+
+	// Route message through the message middleware
+	if err := m.dispatch(conn, msg); err != nil {
+		m.logger.Error().Err(err).Msg("Message routing error")
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
 		}
+		m.reportError(conn, err)
+		// Send error response to client, correlated with the request if it has an ID
+		errorMsg := map[string]any{
+			"error": err.Error(),
+		}
+		if msg.ID != "" {
+			errorMsg["id"] = msg.ID
+		}
+		conn.WriteJSON(errorMsg)
 	}
 }
 
@@ -195,13 +363,21 @@ func (m *Manager) GetOrCreateRoom(roomID string) *Room {
 
 	room, exists := m.rooms[roomID]
 	if !exists {
-		room = NewRoom(roomID, m.logger)
-		m.rooms[roomID] = room
+		room = m.newRoom(roomID)
 	}
 
 	return room
 }
 
+// newRoom creates and registers a room; roomMu must be held
+func (m *Manager) newRoom(roomID string) *Room {
+	room := NewRoom(roomID, m.logger)
+	room.SetHistoryLimit(m.roomHistoryLimit)
+	room.SetBroadcastWorkers(m.broadcastWorkers)
+	m.rooms[roomID] = room
+	return room
+}
+
 // GetRoom gets an existing room
 func (m *Manager) GetRoom(roomID string) (*Room, bool) {
 	m.roomMu.RLock()
@@ -211,43 +387,53 @@ func (m *Manager) GetRoom(roomID string) (*Room, bool) {
 }
 
 // JoinRoom adds a connection to a room
+// Joining a room the connection is already in is a no-op
 func (m *Manager) JoinRoom(conn *Connection, roomID string) error {
-	// Check max connections per room
-	if maxConn := m.config.GetMaxConnectionsPerRoom(); maxConn > 0 {
-		room, exists := m.GetRoom(roomID)
-		if exists && room.Size() >= maxConn {
-			return &Error{Code: "ROOM_FULL", Message: "Room is full"}
-		}
+	// The room is looked up (or created) and joined under roomMu, so it cannot be
+	// removed as empty by a concurrent LeaveRoom in between
+	m.roomMu.Lock()
+	room, exists := m.rooms[roomID]
+	if maxConn := m.config.GetMaxConnectionsPerRoom(); maxConn > 0 && exists && room.Size() >= maxConn {
+		m.roomMu.Unlock()
+		return &Error{Code: "ROOM_FULL", Message: "Room is full"}
 	}
+	if !exists {
+		room = m.newRoom(roomID)
+	}
+	joined := room.Join(conn)
+	size := room.Size()
+	m.roomMu.Unlock()
 
-	room := m.GetOrCreateRoom(roomID)
-	if room.Join(conn) {
-		// Execute OnJoinRoom hook
-		m.hookManager.Execute(hooks.OnJoinRoom, conn, roomID)
+	if joined {
+		// Execute OnJoinRoom hook with the room size after joining
+		m.hookManager.Execute(hooks.OnJoinRoom, conn, roomID, size)
 		m.logger.Debug().Str("room", roomID).Msg("Connection joined room")
 	}
 	return nil
 }
 
 // LeaveRoom removes a connection from a room
+// Leaving a room the connection is not in is a no-op
 func (m *Manager) LeaveRoom(conn *Connection, roomID string) error {
 	m.roomMu.Lock()
-	defer m.roomMu.Unlock()
-
 	room, exists := m.rooms[roomID]
 	if !exists {
+		m.roomMu.Unlock()
 		return &Error{Code: "ROOM_NOT_FOUND", Message: "Room not found"}
 	}
 
-	if room.Leave(conn) {
-		// Execute OnLeaveRoom hook
-		m.hookManager.Execute(hooks.OnLeaveRoom, conn, roomID)
-		m.logger.Debug().Str("room", roomID).Msg("Connection left room")
+	left := room.Leave(conn)
+	size := room.Size()
+	// Cleanup empty rooms
+	if size == 0 {
+		delete(m.rooms, roomID)
+	}
+	m.roomMu.Unlock()
 
-		// Cleanup empty rooms
-		if room.Size() == 0 {
-			delete(m.rooms, roomID)
-		}
+	if left {
+		// Execute OnLeaveRoom hook with the room size after leaving
+		m.hookManager.Execute(hooks.OnLeaveRoom, conn, roomID, size)
+		m.logger.Debug().Str("room", roomID).Msg("Connection left room")
 	}
 
 	return nil
@@ -267,33 +453,166 @@ func (m *Manager) BroadcastToRoom(roomID string, message any) error {
 	return nil
 }
 
-// BroadcastToAll broadcasts a message to all connections
-func (m *Manager) BroadcastToAll(message any) {
-	m.connMu.RLock()
-	connections := make([]*Connection, 0, len(m.connections))
-	for conn := range m.connections {
-		connections = append(connections, conn)
+// BroadcastToRooms broadcasts a message to all connections in the given rooms
+// A connection that is in several of the rooms receives the message once.
+// Existing rooms are broadcast to even if some rooms do not exist; those are
+// listed in the returned ROOM_NOT_FOUND error.
+func (m *Manager) BroadcastToRooms(roomIDs []string, message any) error {
+	var missing []string
+	seen := make(map[*Connection]bool)
+	var targets []*Connection
+
+	for _, roomID := range roomIDs {
+		room, exists := m.GetRoom(roomID)
+		if !exists {
+			missing = append(missing, roomID)
+			continue
+		}
+
+		room.retain(message)
+		for _, conn := range room.GetClients() {
+			if !seen[conn] {
+				seen[conn] = true
+				targets = append(targets, conn)
+			}
+		}
 	}
-	m.connMu.RUnlock()
 
+	encoded, err := encodeMessage(message)
+	if err != nil {
+		return err
+	}
+	for _, conn := range targets {
+		if err := conn.WriteJSON(encoded); err != nil {
+			m.logger.Debug().Err(err).Msg("Failed to broadcast to connection")
+		}
+	}
+
+	if len(missing) > 0 {
+		return &Error{Code: "ROOM_NOT_FOUND", Message: "Rooms not found: " + strings.Join(missing, ", ")}
+	}
+	return nil
+}
+
+// BroadcastEphemeral broadcasts a message to all connections in a room
+// without retaining it in the room history (e.g. presence or "is dragging" signals)
+func (m *Manager) BroadcastEphemeral(roomID string, message any) error {
+	m.roomMu.RLock()
+	room, exists := m.rooms[roomID]
+	m.roomMu.RUnlock()
+
+	if !exists {
+		return &Error{Code: "ROOM_NOT_FOUND", Message: "Room not found"}
+	}
+
+	room.BroadcastEphemeral(message)
+	return nil
+}
+
+// BroadcastToAll broadcasts a message to all connections
+func (m *Manager) BroadcastToAll(message any) {
 	// Send to all connections
-	for _, conn := range connections {
+	for _, conn := range m.allConnections() {
 		if err := conn.WriteJSON(message); err != nil {
 			m.logger.Debug().Err(err).Msg("Failed to broadcast to connection")
 		}
 	}
 }
 
+// BroadcastResult is the outcome of a broadcast
+type BroadcastResult struct {
+	Sent   int      // Number of connections the message was queued for
+	Failed []string // IDs of connections the message could not be queued for
+}
+
+// BroadcastToAllResult broadcasts a message to all connections like BroadcastToAll
+// and reports the connections that did not get it (closed, or with a full write buffer).
+// A message dropped by the DropNewest write policy counts as failed, so a wave of slow
+// consumers shows up in Failed.
+func (m *Manager) BroadcastToAllResult(message any) BroadcastResult {
+	var result BroadcastResult
+	for _, conn := range m.allConnections() {
+		if err := conn.send(message); err != nil {
+			m.logger.Debug().Err(err).Str("conn", conn.ID()).Msg("Failed to broadcast to connection")
+			result.Failed = append(result.Failed, conn.ID())
+			continue
+		}
+		result.Sent++
+	}
+	return result
+}
+
+// allConnections returns a snapshot of the registered connections
+func (m *Manager) allConnections() []*Connection {
+	m.connMu.RLock()
+	defer m.connMu.RUnlock()
+
+	connections := make([]*Connection, 0, len(m.connections))
+	for _, conn := range m.connections {
+		connections = append(connections, conn)
+	}
+	return connections
+}
+
 // SendToConnection sends a message to a specific connection
 func (m *Manager) SendToConnection(conn *Connection, message any) error {
 	return conn.WriteJSON(message)
 }
 
+// SendToConnectionID sends a message to the connection with the given ID
+func (m *Manager) SendToConnectionID(id string, message any) error {
+	m.connMu.RLock()
+	conn, exists := m.connections[id]
+	m.connMu.RUnlock()
+
+	if !exists {
+		return &Error{Code: "CONN_NOT_FOUND", Message: "Connection not found"}
+	}
+
+	return conn.WriteJSON(message)
+}
+
+// Kick disconnects the connection with the given ID, sending a close frame with
+// the given code and reason. The OnDisconnect hook receives hooks.DisconnectKicked.
+func (m *Manager) Kick(connID string, code int, reason string) error {
+	m.connMu.Lock()
+	conn, exists := m.connections[connID]
+	delete(m.connections, connID)
+	m.connMu.Unlock()
+
+	if !exists {
+		return &Error{Code: "CONN_NOT_FOUND", Message: "Connection not found"}
+	}
+
+	conn.setDisconnectCause(hooks.DisconnectKicked)
+	m.logger.Info().Str("conn", connID).Int("code", code).Str("reason", reason).Msg("Kicking WebSocket connection")
+	return conn.CloseWithCode(code, reason)
+}
+
+// AddHook registers a lifecycle hook
+// Unlike WithHook, it can be called at any time, including while connections are active
+func (m *Manager) AddHook(hookType hooks.HookType, fn hooks.HookFunc) {
+	m.hookManager.Add(hookType, fn)
+}
+
 // HandleMessage registers a message handler for a specific action
 func (m *Manager) HandleMessage(action string, handler MessageHandler) {
 	m.router.Handle(action, handler)
 }
 
+// HandleDefault registers a handler for messages with an unknown action
+// (e.g. for logging or dispatching to plugins) instead of replying with ErrUnknownAction
+func (m *Manager) HandleDefault(handler MessageHandler) {
+	m.router.HandleDefault(handler)
+}
+
+// HandleBinary registers a handler for binary frames (e.g. protobuf or msgpack payloads)
+// Binary frames are passed to it as-is, without JSON parsing, routing or the OnMessage hook.
+// Without a binary handler, binary frames are parsed as JSON like text frames.
+func (m *Manager) HandleBinary(handler BinaryHandler) {
+	m.router.HandleBinary(handler)
+}
+
 // GetConnectionCount returns the total number of connections
 func (m *Manager) GetConnectionCount() int {
 	m.connMu.RLock()
@@ -308,9 +627,82 @@ func (m *Manager) GetRoomCount() int {
 	return len(m.rooms)
 }
 
+// ListRooms returns a snapshot of all rooms sorted by ID
+func (m *Manager) ListRooms() []RoomInfo {
+	m.roomMu.RLock()
+	rooms := make([]RoomInfo, 0, len(m.rooms))
+	for roomID, room := range m.rooms {
+		rooms = append(rooms, RoomInfo{ID: roomID, Size: room.Size()})
+	}
+	m.roomMu.RUnlock()
+
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].ID < rooms[j].ID })
+	return rooms
+}
+
+// RoomSizes returns a snapshot of the number of connections per room
+func (m *Manager) RoomSizes() map[string]int {
+	m.roomMu.RLock()
+	defer m.roomMu.RUnlock()
+
+	sizes := make(map[string]int, len(m.rooms))
+	for roomID, room := range m.rooms {
+		sizes[roomID] = room.Size()
+	}
+	return sizes
+}
+
+// Drain stops accepting new connections and closes existing ones with code 1001 (going away),
+// so clients can reconnect to another instance. The notice (if not nil) is sent to each client
+// before its close frame. Clients get up to the shutdown timeout to answer the close frame,
+// after which their connections are closed forcibly.
+func (m *Manager) Drain(notice any) {
+	m.drainOnce.Do(func() {
+		close(m.draining)
+	})
+
+	timeout := m.config.GetShutdownTimeout()
+	deadline := time.Now().Add(timeout)
+
+	m.connMu.RLock()
+	connections := make([]*Connection, 0, len(m.connections))
+	for _, conn := range m.connections {
+		connections = append(connections, conn)
+	}
+	m.connMu.RUnlock()
+
+	m.logger.Info().Int("connections", len(connections)).Dur("timeout", timeout).Msg("Draining WebSocket connections")
+
+	var wg sync.WaitGroup
+	for _, conn := range connections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if notice != nil {
+				if err := conn.WriteJSON(notice); err == nil {
+					// The notice must reach the client before the close frame
+					conn.flush(time.Until(deadline))
+				}
+			}
+			conn.closeWithTimeout(websocket.CloseGoingAway, "server shutdown", time.Until(deadline))
+		}()
+	}
+	wg.Wait()
+}
+
+// Close shuts down the manager (see Shutdown)
+// It lets containers and other owners release the manager as an io.Closer
+func (m *Manager) Close() error {
+	return m.Shutdown()
+}
+
 // Shutdown gracefully shuts down the manager
+// With WithDrainOnShutdown, connections are drained first (see Drain)
 func (m *Manager) Shutdown() error {
 	m.shutdownOnce.Do(func() {
+		if m.drainOnShutdown {
+			m.Drain(m.drainNotice)
+		}
 		close(m.shutdown)
 
 		// Close all connections with timeout
@@ -320,10 +712,22 @@ func (m *Manager) Shutdown() error {
 		done := make(chan struct{})
 		go func() {
 			m.connMu.RLock()
-			for conn := range m.connections {
-				conn.Close()
+			connections := make([]*Connection, 0, len(m.connections))
+			for _, conn := range m.connections {
+				connections = append(connections, conn)
 			}
 			m.connMu.RUnlock()
+
+			// Close handshakes run in parallel, each bounded by its own timeout
+			var wg sync.WaitGroup
+			for _, conn := range connections {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					conn.Close()
+				}()
+			}
+			wg.Wait()
 			close(done)
 		}()
 
@@ -0,0 +1,863 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/rs/zerolog"
+	"github.com/shngxx/point/pkg/ws/hooks"
+	"github.com/shngxx/point/pkg/ws/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestConnection creates a connection that is not backed by a real socket.
+// Messages written to it can be read back from its write channel.
+func newTestConnection() *Connection {
+	nop := zerolog.Nop()
+	return NewConnection(nil, &nop, nil, DropNewest)
+}
+
+// TestBroadcastEphemeral tests that ephemeral broadcasts are delivered but not retained
+func TestBroadcastEphemeral(t *testing.T) {
+	m := NewManager(WithRoomHistory(10))
+	conn := newTestConnection()
+
+	if err := m.JoinRoom(conn, "point_1"); err != nil {
+		t.Fatalf("JoinRoom() error = %v", err)
+	}
+
+	if err := m.BroadcastToRoom("point_1", "position"); err != nil {
+		t.Fatalf("BroadcastToRoom() error = %v", err)
+	}
+	if err := m.BroadcastEphemeral("point_1", "dragging"); err != nil {
+		t.Fatalf("BroadcastEphemeral() error = %v", err)
+	}
+
+	// Both messages are delivered live
+	for _, expected := range []string{"position", "dragging"} {
+		select {
+		case msg := <-conn.writeChan:
+			if msg != expected {
+				t.Errorf("received %v, expected %v", msg, expected)
+			}
+		default:
+			t.Fatalf("message %q was not delivered", expected)
+		}
+	}
+
+	// Only the regular broadcast is retained
+	room, _ := m.GetRoom("point_1")
+	history := room.History()
+	if len(history) != 1 || history[0] != "position" {
+		t.Errorf("History() = %v, expected [position]", history)
+	}
+
+	if err := m.BroadcastEphemeral("missing", "dragging"); err == nil {
+		t.Error("BroadcastEphemeral() should return error for unknown room")
+	}
+}
+
+// TestManager_OnErrorHook tests that routing errors are reported through the OnError hook
+// and a failing hook does not prevent the error response
+func TestManager_OnErrorHook(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+	reported := make(chan any, 10)
+
+	m := NewManager(WithHook(hooks.OnError, func(conn hooks.ConnectionInterface, data ...any) error {
+		if len(data) > 0 {
+			reported <- data[0]
+		}
+		return errors.New("telemetry unavailable")
+	}))
+	m.HandleMessage("fail", func(conn *Connection, msg *Message) error {
+		return handlerErr
+	})
+
+	client := dialTestServer(t, newTestServer(t, m))
+	if err := client.WriteJSON(Message{Action: "fail"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	select {
+	case data := <-reported:
+		if data != handlerErr {
+			t.Errorf("OnError data[0] = %v, expected %v", data, handlerErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnError hook was not executed")
+	}
+
+	var resp map[string]any
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	if err := client.ReadJSON(&resp); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if resp["error"] != handlerErr.Error() {
+		t.Errorf("error response = %v, expected %q", resp, handlerErr.Error())
+	}
+}
+
+// TestManager_InvalidJSON tests that malformed JSON is ignored
+// while JSON that is not a message closes the connection
+func TestManager_InvalidJSON(t *testing.T) {
+	m := NewManager()
+	m.HandleMessage("echo", func(conn *Connection, msg *Message) error {
+		return conn.WriteJSON(map[string]any{"echo": true})
+	})
+	client := dialTestServer(t, newTestServer(t, m))
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	for _, frame := range []string{"not json", ""} {
+		if err := client.WriteMessage(fastws.TextMessage, []byte(frame)); err != nil {
+			t.Fatalf("WriteMessage(%q) error = %v", frame, err)
+		}
+	}
+	if err := client.WriteJSON(Message{Action: "echo"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var resp map[string]any
+	if err := client.ReadJSON(&resp); err != nil || resp["echo"] != true {
+		t.Fatalf("ReadJSON() = %v, %v, expected the echo after malformed messages", resp, err)
+	}
+
+	if err := client.WriteMessage(fastws.TextMessage, []byte(`{"action":5}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	if _, _, err := client.ReadMessage(); err == nil {
+		t.Error("ReadMessage() error = nil, expected the connection to be closed")
+	}
+}
+
+// TestManager_SendToConnectionID tests sending a message to a connection looked up by its ID
+func TestManager_SendToConnectionID(t *testing.T) {
+	ids := make(chan string, 1)
+	m := NewManager(WithHook(hooks.OnConnect, func(conn hooks.ConnectionInterface, data ...any) error {
+		ids <- conn.(*Connection).ID()
+		return nil
+	}))
+
+	client := dialTestServer(t, newTestServer(t, m))
+
+	var id string
+	select {
+	case id = <-ids:
+	case <-time.After(time.Second):
+		t.Fatal("connection was not established")
+	}
+	if id == "" {
+		t.Fatal("ID() should not be empty")
+	}
+
+	if err := m.SendToConnectionID(id, map[string]string{"type": "notice"}); err != nil {
+		t.Fatalf("SendToConnectionID() error = %v", err)
+	}
+
+	var resp map[string]string
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	if err := client.ReadJSON(&resp); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if resp["type"] != "notice" {
+		t.Errorf("received %v, expected type notice", resp)
+	}
+
+	err := m.SendToConnectionID("unknown", "message")
+	var wsErr *Error
+	if !errors.As(err, &wsErr) || wsErr.Code != "CONN_NOT_FOUND" {
+		t.Errorf("SendToConnectionID() error = %v, expected CONN_NOT_FOUND", err)
+	}
+}
+
+// TestManager_BroadcastToAllResult tests that connections whose write buffer is full
+// are reported as failed
+func TestManager_BroadcastToAllResult(t *testing.T) {
+	m := NewManager()
+	healthy := newTestConnection()
+	slow := newFullConnection(DropNewest)
+	m.connMu.Lock()
+	m.connections[healthy.ID()] = healthy
+	m.connections[slow.ID()] = slow
+	m.connMu.Unlock()
+
+	result := m.BroadcastToAllResult("position")
+	if result.Sent != 1 || len(result.Failed) != 1 || result.Failed[0] != slow.ID() {
+		t.Errorf("BroadcastToAllResult() = %+v, expected 1 sent and %s failed", result, slow.ID())
+	}
+	if len(healthy.writeChan) != 1 {
+		t.Errorf("healthy connection queued %d messages, expected 1", len(healthy.writeChan))
+	}
+}
+
+// TestManager_Kick tests disconnecting a connection by ID with a close code and reason
+func TestManager_Kick(t *testing.T) {
+	ids := make(chan string, 1)
+	causes := make(chan any, 1)
+	m := NewManager(
+		WithHook(hooks.OnConnect, func(conn hooks.ConnectionInterface, data ...any) error {
+			ids <- conn.(*Connection).ID()
+			return nil
+		}),
+		WithHook(hooks.OnDisconnect, func(conn hooks.ConnectionInterface, data ...any) error {
+			if len(data) > 0 {
+				causes <- data[0]
+			}
+			return nil
+		}),
+	)
+
+	client := dialTestServer(t, newTestServer(t, m))
+
+	var id string
+	select {
+	case id = <-ids:
+	case <-time.After(time.Second):
+		t.Fatal("connection was not established")
+	}
+
+	// The close handshake needs the client to read the close frame
+	closeErr := make(chan error, 1)
+	go func() {
+		client.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, _, err := client.ReadMessage()
+		closeErr <- err
+	}()
+
+	if err := m.Kick(id, 4000, "spamming"); err != nil {
+		t.Fatalf("Kick() error = %v", err)
+	}
+
+	var ce *fastws.CloseError
+	if err := <-closeErr; !errors.As(err, &ce) || ce.Code != 4000 || ce.Text != "spamming" {
+		t.Errorf("client read error = %v, expected close 4000 \"spamming\"", err)
+	}
+
+	select {
+	case cause := <-causes:
+		if cause != hooks.DisconnectKicked {
+			t.Errorf("OnDisconnect cause = %v, expected %q", cause, hooks.DisconnectKicked)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnDisconnect hook was not executed with a cause")
+	}
+
+	if n := m.GetConnectionCount(); n != 0 {
+		t.Errorf("GetConnectionCount() = %d, expected 0", n)
+	}
+
+	var wsErr *Error
+	if err := m.Kick(id, 4000, "again"); !errors.As(err, &wsErr) || wsErr.Code != "CONN_NOT_FOUND" {
+		t.Errorf("Kick() of removed connection error = %v, expected CONN_NOT_FOUND", err)
+	}
+}
+
+// TestManager_BroadcastToRooms tests that a connection in several target rooms
+// receives the message once and missing rooms are reported
+func TestManager_BroadcastToRooms(t *testing.T) {
+	m := NewManager()
+	both := newTestConnection()
+	first := newTestConnection()
+	second := newTestConnection()
+
+	for _, join := range []struct {
+		conn *Connection
+		room string
+	}{
+		{both, "point_1"}, {both, "point_2"}, {first, "point_1"}, {second, "point_2"},
+	} {
+		if err := m.JoinRoom(join.conn, join.room); err != nil {
+			t.Fatalf("JoinRoom() error = %v", err)
+		}
+	}
+
+	err := m.BroadcastToRooms([]string{"point_1", "point_2", "missing_a", "missing_b"}, "update")
+	var wsErr *Error
+	if !errors.As(err, &wsErr) || wsErr.Code != "ROOM_NOT_FOUND" ||
+		!strings.Contains(wsErr.Message, "missing_a") || !strings.Contains(wsErr.Message, "missing_b") {
+		t.Errorf("BroadcastToRooms() error = %v, expected ROOM_NOT_FOUND listing missing rooms", err)
+	}
+
+	for name, conn := range map[string]*Connection{"both": both, "first": first, "second": second} {
+		if n := len(conn.writeChan); n != 1 {
+			t.Errorf("connection %s received %d messages, expected 1", name, n)
+		}
+	}
+}
+
+// TestManager_ListRooms tests room introspection snapshots
+func TestManager_ListRooms(t *testing.T) {
+	m := NewManager()
+	a, b := newTestConnection(), newTestConnection()
+	m.JoinRoom(a, "point_2")
+	m.JoinRoom(a, "point_1")
+	m.JoinRoom(b, "point_1")
+
+	rooms := m.ListRooms()
+	expected := []RoomInfo{{ID: "point_1", Size: 2}, {ID: "point_2", Size: 1}}
+	if len(rooms) != len(expected) || rooms[0] != expected[0] || rooms[1] != expected[1] {
+		t.Errorf("ListRooms() = %v, expected %v", rooms, expected)
+	}
+
+	sizes := m.RoomSizes()
+	if len(sizes) != 2 || sizes["point_1"] != 2 || sizes["point_2"] != 1 {
+		t.Errorf("RoomSizes() = %v, expected map[point_1:2 point_2:1]", sizes)
+	}
+
+	room, _ := m.GetRoom("point_1")
+	room.SetMetadata("owner", "admin")
+	metadata := room.Metadata()
+	metadata["owner"] = "changed"
+	if owner, _ := room.GetMetadata("owner"); owner != "admin" {
+		t.Errorf("modifying Metadata() copy changed room metadata to %v", owner)
+	}
+}
+
+// TestManager_RateLimit tests that messages over the per-connection rate limit are dropped
+// and reported through the OnError hook
+func TestManager_RateLimit(t *testing.T) {
+	const limit = 10
+	var handled, limited atomic.Int32
+
+	m := NewManager(
+		WithMiddleware(middleware.RateLimit(limit)),
+		WithHook(hooks.OnError, func(conn hooks.ConnectionInterface, data ...any) error {
+			if len(data) > 0 && data[0] == ErrRateLimited {
+				limited.Add(1)
+			}
+			return nil
+		}),
+	)
+	m.HandleMessage("move", func(conn *Connection, msg *Message) error {
+		handled.Add(1)
+		return nil
+	})
+
+	client := dialTestServer(t, newTestServer(t, m))
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := client.WriteJSON(Message{Action: "move"}); err != nil {
+			t.Fatalf("WriteJSON() error = %v", err)
+		}
+	}
+
+	if !waitFor(t, 2*time.Second, func() bool { return handled.Load()+limited.Load() == 100 }) {
+		t.Fatalf("processed %d of 100 messages", handled.Load()+limited.Load())
+	}
+
+	// The burst plus whatever was refilled while sending
+	maxAllowed := limit + int32(time.Since(start).Seconds()*limit) + 1
+	if n := handled.Load(); n < limit || n > maxAllowed {
+		t.Errorf("handled %d messages, expected between %d and %d", n, limit, maxAllowed)
+	}
+}
+
+// TestManager_MessageMiddleware tests that message middleware runs in order around routing
+// and a blocked action never reaches its handler
+func TestManager_MessageMiddleware(t *testing.T) {
+	var order []string
+	var orderMu sync.Mutex
+	record := func(name string) MessageMiddleware {
+		return func(conn *Connection, msg *Message, next func() error) error {
+			orderMu.Lock()
+			order = append(order, name+":"+msg.Action)
+			orderMu.Unlock()
+			return next()
+		}
+	}
+	blockTeleport := func(conn *Connection, msg *Message, next func() error) error {
+		if msg.Action == "teleport" {
+			return &Error{Code: "FORBIDDEN", Message: "teleport is not allowed"}
+		}
+		return next()
+	}
+
+	var teleports atomic.Int32
+	moved := make(chan struct{}, 1)
+	m := NewManager(WithMessageMiddleware(record("first"), blockTeleport, record("last")))
+	m.HandleMessage("teleport", func(conn *Connection, msg *Message) error {
+		teleports.Add(1)
+		return nil
+	})
+	m.HandleMessage("move", func(conn *Connection, msg *Message) error {
+		moved <- struct{}{}
+		return nil
+	})
+
+	client := dialTestServer(t, newTestServer(t, m))
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := client.WriteJSON(Message{Action: "teleport", ID: "1"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var resp map[string]any
+	if err := client.ReadJSON(&resp); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if resp["id"] != "1" || !strings.Contains(fmt.Sprint(resp["error"]), "teleport is not allowed") {
+		t.Errorf("response = %v, expected the middleware error", resp)
+	}
+
+	if err := client.WriteJSON(Message{Action: "move"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	select {
+	case <-moved:
+	case <-time.After(2 * time.Second):
+		t.Fatal("move did not reach its handler")
+	}
+
+	if teleports.Load() != 0 {
+		t.Errorf("teleport handler called %d times, expected 0", teleports.Load())
+	}
+	orderMu.Lock()
+	defer orderMu.Unlock()
+	expected := []string{"first:teleport", "first:move", "last:move"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("middleware order = %v, expected %v", order, expected)
+	}
+}
+
+// TestManager_AuthMiddleware tests authenticating connections by a handshake token
+func TestManager_AuthMiddleware(t *testing.T) {
+	users := make(chan any, 2)
+	m := NewManager(
+		WithMiddleware(middleware.Auth(func(token string) (string, error) {
+			if token != "secret" {
+				return "", errors.New("invalid token")
+			}
+			return "user_42", nil
+		})),
+		WithHook(hooks.OnConnect, func(conn hooks.ConnectionInterface, data ...any) error {
+			userID, _ := conn.GetMetadata(middleware.UserIDKey)
+			users <- userID
+			return nil
+		}),
+	)
+	url := newTestServer(t, m)
+
+	tests := []struct {
+		name   string
+		url    string
+		header http.Header
+		valid  bool
+	}{
+		{"query", url + "?token=secret", nil, true},
+		{"header", url, http.Header{"Authorization": {"Bearer secret"}}, true},
+		{"invalid", url + "?token=wrong", nil, false},
+		{"missing", url, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _, err := fastws.DefaultDialer.Dial(tt.url, tt.header)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer client.Close()
+
+			if tt.valid {
+				select {
+				case userID := <-users:
+					if userID != "user_42" {
+						t.Errorf("user_id = %v, expected user_42", userID)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("authenticated connection was not established")
+				}
+				return
+			}
+
+			client.SetReadDeadline(time.Now().Add(2 * time.Second))
+			_, _, err = client.ReadMessage()
+			var ce *fastws.CloseError
+			if !errors.As(err, &ce) || ce.Code != fastws.ClosePolicyViolation {
+				t.Errorf("read error = %v, expected policy violation close", err)
+			}
+		})
+	}
+}
+
+// TestManager_DrainOnShutdown tests that Shutdown sends the notice and a going away close
+// frame to each client and rejects new connections with try again later
+func TestManager_DrainOnShutdown(t *testing.T) {
+	m := NewManager(WithDrainOnShutdown(ShutdownNotice))
+	url := newTestServer(t, m)
+
+	clients := []*fastws.Conn{dialTestServer(t, url), dialTestServer(t, url)}
+	if !waitFor(t, time.Second, func() bool { return m.GetConnectionCount() == len(clients) }) {
+		t.Fatalf("GetConnectionCount() = %d, expected %d", m.GetConnectionCount(), len(clients))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.Shutdown()
+		close(done)
+	}()
+
+	for i, client := range clients {
+		client.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+		var notice map[string]string
+		if err := client.ReadJSON(&notice); err != nil {
+			t.Fatalf("client %d: ReadJSON() error = %v", i, err)
+		}
+		if notice["type"] != "server_shutdown" {
+			t.Errorf("client %d: notice = %v, expected server_shutdown", i, notice)
+		}
+
+		_, _, err := client.ReadMessage()
+		var ce *fastws.CloseError
+		if !errors.As(err, &ce) || ce.Code != fastws.CloseGoingAway {
+			t.Errorf("client %d: read error = %v, expected going away close", i, err)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown did not complete after clients disconnected")
+	}
+
+	// New connections are rejected with try again later
+	late := dialTestServer(t, url)
+	late.SetReadDeadline(time.Now().Add(time.Second))
+	if _, _, err := late.ReadMessage(); !fastws.IsCloseError(err, fastws.CloseTryAgainLater) {
+		t.Errorf("read error after drain = %v, expected try again later close (1013)", err)
+	}
+	if !waitFor(t, time.Second, func() bool { return m.GetConnectionCount() == 0 }) {
+		t.Errorf("GetConnectionCount() = %d, expected 0", m.GetConnectionCount())
+	}
+}
+
+// TestManager_MiddlewareRejectCode tests that a connection rejected by middleware
+// is closed with a policy violation instead of an abnormal closure
+func TestManager_MiddlewareRejectCode(t *testing.T) {
+	m := NewManager(WithMiddleware(func(c middleware.ConnectionInterface) error {
+		return errors.New("banned")
+	}))
+	client := dialTestServer(t, newTestServer(t, m))
+
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := client.ReadMessage()
+	var ce *fastws.CloseError
+	if !errors.As(err, &ce) || ce.Code != fastws.ClosePolicyViolation || ce.Text != "connection rejected" {
+		t.Errorf("read error = %v, expected policy violation close (1008)", err)
+	}
+}
+
+// TestManager_RoomHooksSize tests that room hooks receive the room ID and the resulting room size
+func TestManager_RoomHooksSize(t *testing.T) {
+	var joins, leaves []int
+	record := func(sizes *[]int) hooks.HookFunc {
+		return func(conn hooks.ConnectionInterface, data ...any) error {
+			if len(data) != 2 || data[0] != "point_1" {
+				t.Errorf("hook data = %v, expected [point_1 <size>]", data)
+				return nil
+			}
+			*sizes = append(*sizes, data[1].(int))
+			return nil
+		}
+	}
+
+	m := NewManager(
+		WithHook(hooks.OnJoinRoom, record(&joins)),
+		WithHook(hooks.OnLeaveRoom, record(&leaves)),
+	)
+
+	conns := []*Connection{newTestConnection(), newTestConnection(), newTestConnection()}
+	for _, conn := range conns {
+		m.JoinRoom(conn, "point_1")
+	}
+	m.LeaveRoom(conns[0], "point_1")
+
+	if len(joins) != 3 || joins[0] != 1 || joins[1] != 2 || joins[2] != 3 {
+		t.Errorf("OnJoinRoom sizes = %v, expected [1 2 3]", joins)
+	}
+	if len(leaves) != 1 || leaves[0] != 2 {
+		t.Errorf("OnLeaveRoom sizes = %v, expected [2]", leaves)
+	}
+}
+
+// invalidBufferConfig is a manager config returning invalid buffer sizes
+type invalidBufferConfig struct {
+	DefaultConfig
+}
+
+func (c *invalidBufferConfig) GetReadBufferSize() int  { return -1 }
+func (c *invalidBufferConfig) GetWriteBufferSize() int { return 0 }
+
+// TestManager_UpgradeConfig tests that the upgrader config uses the configured buffer sizes
+func TestManager_UpgradeConfig(t *testing.T) {
+	m := NewManager(WithConfig(&DefaultConfig{ReadBufferSize: 1024, WriteBufferSize: 8192}))
+	if cfg := m.UpgradeConfig(); cfg.ReadBufferSize != 1024 || cfg.WriteBufferSize != 8192 {
+		t.Errorf("UpgradeConfig() buffers = %d/%d, expected 1024/8192", cfg.ReadBufferSize, cfg.WriteBufferSize)
+	}
+
+	m = NewManager(WithConfig(&invalidBufferConfig{}))
+	if cfg := m.UpgradeConfig(); cfg.ReadBufferSize != 4096 || cfg.WriteBufferSize != 4096 {
+		t.Errorf("UpgradeConfig() buffers = %d/%d, expected defaults 4096/4096", cfg.ReadBufferSize, cfg.WriteBufferSize)
+	}
+}
+
+// TestManager_AddHookConcurrent tests adding hooks while connections are being handled
+func TestManager_AddHookConcurrent(t *testing.T) {
+	m := NewManagerWithDefaults(nil)
+	var messages atomic.Int32
+	m.HandleMessage("move", func(conn *Connection, msg *Message) error { return nil })
+
+	client := dialTestServer(t, newTestServer(t, m))
+
+	stop := make(chan struct{})
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				if err := client.WriteJSON(Message{Action: "move"}); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		m.AddHook(hooks.OnMessage, func(conn hooks.ConnectionInterface, data ...any) error {
+			messages.Add(1)
+			return nil
+		})
+		time.Sleep(100 * time.Microsecond)
+	}
+	close(stop)
+	<-sent
+
+	if !waitFor(t, time.Second, func() bool { return messages.Load() > 0 }) {
+		t.Error("hooks added at runtime were not executed")
+	}
+}
+
+// TestManager_Ack tests correlating acknowledgements and errors with the request ID
+func TestManager_Ack(t *testing.T) {
+	m := NewManager()
+	m.HandleMessage("move", func(conn *Connection, msg *Message) error {
+		return conn.WriteAck(msg.ID, map[string]int{"x": 401})
+	})
+	m.HandleMessage("fail", func(conn *Connection, msg *Message) error {
+		return errors.New("move rejected")
+	})
+
+	client := dialTestServer(t, newTestServer(t, m))
+	client.SetReadDeadline(time.Now().Add(time.Second))
+
+	if err := client.WriteJSON(Message{ID: "req-1", Action: "move"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	_, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if expected := `{"id":"req-1","ack":true,"data":{"x":401}}`; string(data) != expected {
+		t.Errorf("ack = %s, expected %s", data, expected)
+	}
+
+	if err := client.WriteJSON(Message{ID: "req-2", Action: "fail"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var resp map[string]any
+	if err := client.ReadJSON(&resp); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if resp["id"] != "req-2" || resp["error"] != "move rejected" {
+		t.Errorf("error response = %v, expected id req-2 and error", resp)
+	}
+}
+
+// TestManager_RoomStress concurrently joins, leaves, broadcasts and disconnects
+// to detect deadlocks, data races (with -race) and lost room memberships
+func TestManager_RoomStress(t *testing.T) {
+	m := NewManager()
+	rooms := []string{"point_1", "point_2", "point_3"}
+
+	inRoom := func(conn *Connection, roomID string) bool {
+		room, ok := m.GetRoom(roomID)
+		if !ok {
+			return false
+		}
+		for _, c := range room.GetClients() {
+			if c == conn {
+				return true
+			}
+		}
+		return false
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i := 0; i < 200; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				conn := newTestConnection()
+				for j := 0; j < 20; j++ {
+					roomID := rooms[(i+j)%len(rooms)]
+					m.JoinRoom(conn, roomID)
+					m.JoinRoom(conn, roomID) // idempotent
+					if !inRoom(conn, roomID) {
+						t.Errorf("connection lost membership of %s after joining", roomID)
+					}
+					m.BroadcastToRoom(roomID, "update")
+					m.ListRooms()
+					if j%2 == 0 {
+						m.LeaveRoom(conn, roomID)
+						m.LeaveRoom(conn, roomID) // idempotent
+					}
+					// Drain writes so broadcasts keep reaching the buffer
+					for len(conn.writeChan) > 0 {
+						<-conn.writeChan
+					}
+				}
+				// Disconnect
+				m.leaveAllRooms(conn)
+			}()
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("deadlock: stress test did not finish")
+	}
+
+	if n := m.GetRoomCount(); n != 0 {
+		t.Errorf("GetRoomCount() = %d after all connections left, expected 0", n)
+	}
+}
+
+// TestManager_Tracing tests starting a span per routed message
+func TestManager_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	m := NewManager(WithTracer(provider.Tracer("test")))
+	handlerSpans := make(chan trace.SpanContext, 2)
+	m.HandleMessage("move", func(conn *Connection, msg *Message) error {
+		handlerSpans <- trace.SpanContextFromContext(msg.Context())
+		return conn.WriteAck(msg.ID, nil)
+	})
+	m.HandleMessage("fail", func(conn *Connection, msg *Message) error {
+		return errors.New("move rejected")
+	})
+
+	client := dialTestServer(t, newTestServer(t, m))
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	for _, msg := range []Message{{ID: "req-1", Action: "move"}, {ID: "req-2", Action: "fail"}} {
+		if err := client.WriteJSON(msg); err != nil {
+			t.Fatalf("WriteJSON() error = %v", err)
+		}
+		if _, _, err := client.ReadMessage(); err != nil {
+			t.Fatalf("ReadMessage() error = %v", err)
+		}
+	}
+
+	if !waitFor(t, time.Second, func() bool { return len(recorder.Ended()) == 2 }) {
+		t.Fatalf("Spans = %d, expected one per message", len(recorder.Ended()))
+	}
+	spans := recorder.Ended()
+
+	span := spans[0]
+	if span.Name() != "ws move" {
+		t.Errorf("Name = %q, expected %q", span.Name(), "ws move")
+	}
+	attrs := attribute.NewSet(span.Attributes()...)
+	if v, _ := attrs.Value("ws.action"); v.AsString() != "move" {
+		t.Errorf("ws.action = %q, expected move", v.AsString())
+	}
+	if v, _ := attrs.Value("ws.message_id"); v.AsString() != "req-1" {
+		t.Errorf("ws.message_id = %q, expected req-1", v.AsString())
+	}
+	if handlerSpan := <-handlerSpans; handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("Handler context span = %v, expected the message span", handlerSpan.SpanID())
+	}
+
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "move rejected" {
+		t.Errorf("Status = %+v, expected the handler error", spans[1].Status())
+	}
+}
+
+// TestManager_RoomMembers tests listing the authenticated members of a room
+// and the presence broadcasts sent as they join and leave
+func TestManager_RoomMembers(t *testing.T) {
+	m := NewManager(
+		WithMiddleware(middleware.Auth(func(token string) (string, error) {
+			return token, nil
+		})),
+		WithPresence(),
+	)
+	m.HandleMessage("join", func(conn *Connection, msg *Message) error {
+		return m.JoinRoom(conn, "lobby")
+	})
+	url := newTestServer(t, m)
+
+	if _, err := m.RoomMembers("lobby"); err == nil {
+		t.Error("RoomMembers() of a missing room error = nil, expected ROOM_NOT_FOUND")
+	}
+
+	clients := make(map[string]*fastws.Conn)
+	for i, user := range []string{"carol", "alice", "bob"} {
+		client := dialTestServer(t, url+"?token="+user)
+		if err := client.WriteJSON(Message{Action: "join"}); err != nil {
+			t.Fatalf("WriteJSON() error = %v", err)
+		}
+		if !waitFor(t, time.Second, func() bool {
+			members, _ := m.RoomMembers("lobby")
+			return len(members) == i+1
+		}) {
+			t.Fatalf("%s did not join the room", user)
+		}
+		clients[user] = client
+	}
+
+	members, err := m.RoomMembers("lobby")
+	if err != nil {
+		t.Fatalf("RoomMembers() error = %v", err)
+	}
+	if !slices.Equal(members, []string{"alice", "bob", "carol"}) {
+		t.Errorf("RoomMembers() = %v, expected [alice bob carol]", members)
+	}
+
+	clients["bob"].Close()
+
+	// carol saw alice and bob join, then bob leave
+	carol := clients["carol"]
+	carol.SetReadDeadline(time.Now().Add(2 * time.Second))
+	expected := []PresenceMessage{
+		{Type: PresenceJoined, Room: "lobby", Member: "alice"},
+		{Type: PresenceJoined, Room: "lobby", Member: "bob"},
+		{Type: PresenceLeft, Room: "lobby", Member: "bob"},
+	}
+	for i, want := range expected {
+		var got PresenceMessage
+		if err := carol.ReadJSON(&got); err != nil {
+			t.Fatalf("presence %d ReadJSON() error = %v", i, err)
+		}
+		if got != want {
+			t.Errorf("presence %d = %+v, expected %+v", i, got, want)
+		}
+	}
+}
@@ -1,24 +1,57 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
 	"sync"
 )
 
 // Message represents a generic WebSocket message
 type Message struct {
+	ID     string          `json:"id,omitempty"` // Optional correlation ID, echoed in acknowledgements
 	Action string          `json:"action"`
 	Data   json.RawMessage `json:"data,omitempty"`
 	Type   string          `json:"type,omitempty"`
+
+	ctx context.Context // Set by the manager while the message is routed
+	raw []byte          // Original frame, set by the manager
+}
+
+// Context returns the context of the message while it is handled: the connection context,
+// carrying the message span if the manager has a tracer (see WithTracer)
+// Use it to start child spans in handlers. Returns context.Background() for messages not routed by a manager.
+func (m *Message) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// Raw returns the original JSON frame of the message, for handlers that accept fields
+// outside of Data. Returns nil for messages not read by a manager.
+func (m *Message) Raw() []byte {
+	return m.raw
+}
+
+// Ack is the acknowledgement of a message with a correlation ID
+type Ack struct {
+	ID   string `json:"id"`
+	Ack  bool   `json:"ack"`
+	Data any    `json:"data,omitempty"`
 }
 
 // MessageHandler is a function that handles a message
 type MessageHandler func(conn *Connection, message *Message) error
 
+// BinaryHandler is a function that handles a raw binary frame
+type BinaryHandler func(conn *Connection, data []byte) error
+
 // Router handles message routing by action/type
 type Router struct {
-	handlers map[string]MessageHandler
-	mu       sync.RWMutex
+	handlers       map[string]MessageHandler
+	defaultHandler MessageHandler
+	binaryHandler  BinaryHandler
+	mu             sync.RWMutex
 }
 
 // NewRouter creates a new message router
@@ -50,6 +83,12 @@ func (r *Router) Route(conn *Connection, message *Message) error {
 		}
 	}
 
+	if !ok {
+		r.mu.RLock()
+		handler, ok = r.defaultHandler, r.defaultHandler != nil
+		r.mu.RUnlock()
+	}
+
 	if !ok {
 		return ErrUnknownAction
 	}
@@ -57,6 +96,28 @@ func (r *Router) Route(conn *Connection, message *Message) error {
 	return handler(conn, message)
 }
 
+// HandleDefault registers a handler for messages whose action and type match no handler
+// Without a default handler such messages fail with ErrUnknownAction
+func (r *Router) HandleDefault(handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultHandler = handler
+}
+
+// HandleBinary registers a handler for binary frames, bypassing JSON parsing
+func (r *Router) HandleBinary(handler BinaryHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.binaryHandler = handler
+}
+
+// BinaryHandler returns the registered binary frame handler, if any
+func (r *Router) BinaryHandler() (BinaryHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.binaryHandler, r.binaryHandler != nil
+}
+
 // HasHandler checks if a handler exists for the given action
 func (r *Router) HasHandler(action string) bool {
 	r.mu.RLock()
@@ -65,11 +126,33 @@ func (r *Router) HasHandler(action string) bool {
 	return ok
 }
 
+// HandleTyped registers a handler for a specific action whose payload (msg.Data)
+// is unmarshaled into T. An empty payload yields the zero value of T.
+// Malformed payloads are rejected with an INVALID_PAYLOAD *Error without calling fn.
+func HandleTyped[T any](m *Manager, action string, fn func(conn *Connection, payload T) error) {
+	m.HandleMessage(action, func(conn *Connection, msg *Message) error {
+		var payload T
+		if len(msg.Data) > 0 {
+			if err := json.Unmarshal(msg.Data, &payload); err != nil {
+				return &Error{Code: "INVALID_PAYLOAD", Message: "Invalid " + action + " payload: " + err.Error()}
+			}
+		}
+		return fn(conn, payload)
+	})
+}
+
 // Errors
 var (
-	ErrUnknownAction = &Error{Code: "UNKNOWN_ACTION", Message: "Unknown message action"}
+	ErrUnknownAction   = &Error{Code: "UNKNOWN_ACTION", Message: "Unknown message action"}
+	ErrWriteBufferFull = &Error{Code: "WRITE_BUFFER_FULL", Message: "Write buffer full"}
+	ErrRateLimited     = &Error{Code: "RATE_LIMITED", Message: "Message rate limit exceeded"}
+	ErrActionTooLong   = &Error{Code: "ACTION_TOO_LONG", Message: "Message action or type too long"}
 )
 
+// MaxActionLength is the maximum length of a message's action and type
+// Longer messages are rejected with ErrActionTooLong without being routed
+const MaxActionLength = 64
+
 // Error represents a WebSocket error
 type Error struct {
 	Code    string `json:"code"`
@@ -79,4 +162,3 @@ type Error struct {
 func (e *Error) Error() string {
 	return e.Message
 }
-
@@ -0,0 +1,32 @@
+package ws
+
+// MessageMiddleware runs around the routing of each incoming JSON message (see WithMessageMiddleware)
+// Calling next continues with the next middleware and finally routes the message; returning
+// without calling it drops the message. A returned error is handled like a routing error:
+// it is reported to the OnError hook and sent to the client.
+//
+// Example:
+//
+//	func adminOnly(conn *ws.Connection, msg *ws.Message, next func() error) error {
+//	    if msg.Action == "teleport" {
+//	        if role, _ := conn.GetMetadata("role"); role != "admin" {
+//	            return &ws.Error{Code: "FORBIDDEN", Message: "Admin only"}
+//	        }
+//	    }
+//	    return next()
+//	}
+type MessageMiddleware func(conn *Connection, msg *Message, next func() error) error
+
+// dispatch routes a message through the message middleware chain
+func (m *Manager) dispatch(conn *Connection, msg *Message) error {
+	var call func(i int) error
+	call = func(i int) error {
+		if i == len(m.messageMiddleware) {
+			return m.router.Route(conn, msg)
+		}
+		return m.messageMiddleware[i](conn, msg, func() error {
+			return call(i + 1)
+		})
+	}
+	return call(0)
+}
@@ -0,0 +1,87 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestRouter_HandleDefault tests the fallback handler for unknown actions
+func TestRouter_HandleDefault(t *testing.T) {
+	r := NewRouter()
+	var handled []string
+	r.Handle("move", func(conn *Connection, msg *Message) error {
+		handled = append(handled, "move:"+msg.Action)
+		return nil
+	})
+
+	if err := r.Route(nil, &Message{Action: "teleport"}); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("Route() without default error = %v, expected ErrUnknownAction", err)
+	}
+
+	r.HandleDefault(func(conn *Connection, msg *Message) error {
+		handled = append(handled, "default:"+msg.Action)
+		return nil
+	})
+
+	for _, action := range []string{"move", "teleport"} {
+		if err := r.Route(nil, &Message{Action: action}); err != nil {
+			t.Errorf("Route(%q) error = %v", action, err)
+		}
+	}
+
+	expected := []string{"move:move", "default:teleport"}
+	if len(handled) != len(expected) || handled[0] != expected[0] || handled[1] != expected[1] {
+		t.Errorf("handled = %v, expected %v", handled, expected)
+	}
+}
+
+// TestHandleTyped tests payload unmarshaling and malformed payload handling of typed handlers
+func TestHandleTyped(t *testing.T) {
+	type moveMessage struct {
+		DX int `json:"dx"`
+		DY int `json:"dy"`
+	}
+
+	m := NewManager()
+	var received []moveMessage
+	HandleTyped(m, "move", func(conn *Connection, payload moveMessage) error {
+		received = append(received, payload)
+		return nil
+	})
+
+	tests := []struct {
+		name     string
+		data     string
+		expected *moveMessage // nil if the payload must be rejected
+	}{
+		{"valid", `{"dx":5,"dy":-2}`, &moveMessage{DX: 5, DY: -2}},
+		{"empty", ``, &moveMessage{}},
+		{"malformed", `{"dx":"left"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			err := m.router.Route(nil, &Message{Action: "move", Data: json.RawMessage(tt.data)})
+
+			if tt.expected == nil {
+				var wsErr *Error
+				if !errors.As(err, &wsErr) || wsErr.Code != "INVALID_PAYLOAD" {
+					t.Errorf("Route() error = %v, expected INVALID_PAYLOAD", err)
+				}
+				if len(received) != 0 {
+					t.Errorf("handler called with %v for malformed payload", received)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Route() error = %v", err)
+			}
+			if len(received) != 1 || received[0] != *tt.expected {
+				t.Errorf("received %v, expected %v", received, *tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/websocket/v2"
+)
+
+// UserIDKey is the connection metadata key under which Auth stores the authenticated user ID
+const UserIDKey = "user_id"
+
+// ErrUnauthorized is returned by Auth when the handshake carries no token
+var ErrUnauthorized = errors.New("unauthorized: missing token")
+
+// Auth returns a middleware that authenticates a connection by a token from the handshake
+// The token is taken from the Authorization header (with or without the "Bearer " prefix)
+// or the "token" query parameter, since browsers cannot set headers on WebSocket requests.
+// On success the user ID returned by validate is stored in metadata under "user_id";
+// on failure the connection is closed with a policy violation (1008) close frame.
+func Auth(validate func(token string) (userID string, err error)) Handler {
+	return func(c ConnectionInterface) error {
+		token := handshakeToken(c)
+		if token == "" {
+			return reject(c, ErrUnauthorized)
+		}
+
+		userID, err := validate(token)
+		if err != nil {
+			return reject(c, err)
+		}
+
+		c.SetMetadata(UserIDKey, userID)
+		return nil
+	}
+}
+
+// handshakeToken reads the token from the upgrade request of the connection
+func handshakeToken(c ConnectionInterface) string {
+	wc, ok := c.(interface{ Conn() *websocket.Conn })
+	if !ok || wc.Conn() == nil {
+		return ""
+	}
+	conn := wc.Conn()
+
+	if header := conn.Headers("Authorization"); header != "" {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	return conn.Query("token")
+}
+
+// reject closes the connection with a policy violation and returns err
+func reject(c ConnectionInterface, err error) error {
+	if cc, ok := c.(interface{ CloseWithCode(int, string) error }); ok {
+		cc.CloseWithCode(websocket.ClosePolicyViolation, "unauthorized")
+	} else {
+		c.Close()
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiterKey is the connection metadata key under which RateLimit stores the connection's limiter
+const RateLimiterKey = "ws.rate_limiter"
+
+// RateLimit returns a middleware that limits the number of messages a connection may send
+// Middleware runs once per connection, so it only attaches a token-bucket limiter to the
+// connection metadata; the manager checks it for every incoming message before routing
+// and drops messages over the limit. Bursts of up to maxPerSecond messages are allowed.
+func RateLimit(maxPerSecond int) Handler {
+	return func(c ConnectionInterface) error {
+		if maxPerSecond > 0 {
+			c.SetMetadata(RateLimiterKey, NewTokenBucket(maxPerSecond))
+		}
+		return nil
+	}
+}
+
+// TokenBucket is a token-bucket rate limiter safe for concurrent use
+type TokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // Tokens added per second
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket creates a full token bucket refilled at perSecond tokens per second
+func NewTokenBucket(perSecond int) *TokenBucket {
+	return &TokenBucket{
+		rate:     float64(perSecond),
+		capacity: float64(perSecond),
+		tokens:   float64(perSecond),
+		last:     time.Now(),
+	}
+}
+
+// Allow takes a token from the bucket, reporting false if none is available
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
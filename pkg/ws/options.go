@@ -4,6 +4,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/shngxx/point/pkg/ws/hooks"
 	"github.com/shngxx/point/pkg/ws/middleware"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Option is a function that configures the Manager
@@ -34,6 +35,15 @@ func WithMiddleware(mw ...middleware.Handler) Option {
 	}
 }
 
+// WithMessageMiddleware sets middleware run around the routing of each incoming message,
+// in the given order (see MessageMiddleware). Connection middleware (WithMiddleware) runs
+// once per connection instead.
+func WithMessageMiddleware(mw ...MessageMiddleware) Option {
+	return func(m *Manager) {
+		m.messageMiddleware = append(m.messageMiddleware, mw...)
+	}
+}
+
 // WithHook registers a lifecycle hook
 func WithHook(hookType hooks.HookType, fn hooks.HookFunc) Option {
 	return func(m *Manager) {
@@ -43,3 +53,49 @@ func WithHook(hookType hooks.HookType, fn hooks.HookFunc) Option {
 		m.hookManager.Add(hookType, fn)
 	}
 }
+
+// WithRoomHistory enables retaining the last n broadcast messages per room
+// Ephemeral broadcasts are never retained
+func WithRoomHistory(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.roomHistoryLimit = n
+		}
+	}
+}
+
+// WithWritePolicy sets what happens when a connection's write buffer is full
+// Default: DropNewest
+func WithWritePolicy(policy WritePolicy) Option {
+	return func(m *Manager) {
+		m.writePolicy = policy
+	}
+}
+
+// WithDrainOnShutdown makes Shutdown drain connections before closing them:
+// each client receives the notice (e.g. ShutdownNotice; nil sends none) and a
+// going away (1001) close frame, and gets up to the shutdown timeout to disconnect
+func WithDrainOnShutdown(notice any) Option {
+	return func(m *Manager) {
+		m.drainOnShutdown = true
+		m.drainNotice = notice
+	}
+}
+
+// WithBroadcastWorkers sends room broadcasts from up to n goroutines,
+// which speeds up broadcasts to very large rooms. Default: sequential
+func WithBroadcastWorkers(n int) Option {
+	return func(m *Manager) {
+		if n > 1 {
+			m.broadcastWorkers = n
+		}
+	}
+}
+
+// WithTracer starts a span per routed message with tracer, named "ws <action>"
+// The span is available to handlers through Message.Context()
+func WithTracer(tracer trace.Tracer) Option {
+	return func(m *Manager) {
+		m.tracer = tracer
+	}
+}
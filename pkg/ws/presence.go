@@ -0,0 +1,97 @@
+package ws
+
+import (
+	"sort"
+
+	"github.com/shngxx/point/pkg/ws/hooks"
+	"github.com/shngxx/point/pkg/ws/middleware"
+)
+
+// Presence event types sent by BroadcastPresence
+const (
+	PresenceJoined = "member_joined"
+	PresenceLeft   = "member_left"
+)
+
+// PresenceMessage notifies the members of a room that a member joined or left
+type PresenceMessage struct {
+	Type   string `json:"type"` // PresenceJoined or PresenceLeft
+	Room   string `json:"room"`
+	Member string `json:"member"` // See MemberID
+}
+
+// MemberID returns the ID a connection is listed under in presence:
+// its "user_id" metadata (set by middleware.Auth), or the connection ID
+func MemberID(conn hooks.ConnectionInterface) string {
+	if userID, ok := conn.GetMetadata(middleware.UserIDKey); ok {
+		if id, ok := userID.(string); ok && id != "" {
+			return id
+		}
+	}
+	if c, ok := conn.(interface{ ID() string }); ok {
+		return c.ID()
+	}
+	return ""
+}
+
+// RoomMembers returns the member IDs (see MemberID) of the connections in a room, sorted
+func (m *Manager) RoomMembers(roomID string) ([]string, error) {
+	room, exists := m.GetRoom(roomID)
+	if !exists {
+		return nil, &Error{Code: "ROOM_NOT_FOUND", Message: "Room not found"}
+	}
+
+	clients := room.GetClients()
+	members := make([]string, 0, len(clients))
+	for _, conn := range clients {
+		members = append(members, MemberID(conn))
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// BroadcastPresence sends a PresenceMessage of the given type (PresenceJoined or PresenceLeft)
+// about conn to the other connections in a room. Presence is not retained in the room history.
+// A room that no longer exists (its last member left) is not an error.
+func (m *Manager) BroadcastPresence(conn hooks.ConnectionInterface, eventType, roomID string) {
+	room, exists := m.GetRoom(roomID)
+	if !exists {
+		return
+	}
+
+	clients := room.GetClients()
+	others := make([]*Connection, 0, len(clients))
+	for _, client := range clients {
+		if hooks.ConnectionInterface(client) != conn {
+			others = append(others, client)
+		}
+	}
+	room.send(PresenceMessage{Type: eventType, Room: roomID, Member: MemberID(conn)}, others)
+}
+
+// WithPresence broadcasts presence to the members of a room when a connection joins or leaves it,
+// including leaving all its rooms on disconnect
+func WithPresence() Option {
+	return func(m *Manager) {
+		broadcast := func(eventType string) hooks.HookFunc {
+			return func(conn hooks.ConnectionInterface, data ...any) error {
+				if len(data) > 0 {
+					if roomID, ok := data[0].(string); ok {
+						m.BroadcastPresence(conn, eventType, roomID)
+					}
+				}
+				return nil
+			}
+		}
+		WithHook(hooks.OnJoinRoom, broadcast(PresenceJoined))(m)
+		WithHook(hooks.OnLeaveRoom, broadcast(PresenceLeft))(m)
+
+		// OnDisconnect runs before the connection is removed from its rooms
+		WithHook(hooks.OnDisconnect, func(conn hooks.ConnectionInterface, data ...any) error {
+			for _, roomID := range conn.GetSubscriptions() {
+				m.BroadcastPresence(conn, PresenceLeft, roomID)
+			}
+			return nil
+		})(m)
+	}
+}
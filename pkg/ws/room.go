@@ -1,6 +1,7 @@
 package ws
 
 import (
+	"encoding/json"
 	"sync"
 
 	"github.com/rs/zerolog"
@@ -14,6 +15,20 @@ type Room struct {
 	logger     *zerolog.Logger
 	metadata   map[string]any
 	metadataMu sync.RWMutex
+
+	// Retained messages (history), bounded by historyLimit (0 = disabled)
+	history      []any
+	historyLimit int
+	historyMu    sync.RWMutex
+
+	// Number of goroutines sending a broadcast (<= 1 sends sequentially)
+	broadcastWorkers int
+}
+
+// RoomInfo is a snapshot of a room's state
+type RoomInfo struct {
+	ID   string `json:"id"`
+	Size int    `json:"size"`
 }
 
 // NewRoom creates a new room
@@ -75,27 +90,24 @@ func (r *Room) Size() int {
 }
 
 // Broadcast sends a message to all connections in the room
+// The message is retained in the room history (if enabled)
 func (r *Room) Broadcast(message any) {
-	r.clientsMu.RLock()
-	clients := make([]*Connection, 0, len(r.clients))
-	for conn := range r.clients {
-		clients = append(clients, conn)
-	}
-	r.clientsMu.RUnlock()
+	r.retain(message)
+	r.BroadcastEphemeral(message)
+}
 
-	// Send to all clients (outside of lock to avoid deadlock)
-	for _, conn := range clients {
-		if err := conn.WriteJSON(message); err != nil {
-			r.logger.Debug().
-				Str("room", r.id).
-				Err(err).
-				Msg("Failed to send message to client in room")
-		}
-	}
+// BroadcastEphemeral sends a message to all connections in the room
+// without retaining it in the room history. Use it for transient signals
+// (e.g. "user is dragging the point") that must not be replayed later.
+func (r *Room) BroadcastEphemeral(message any) {
+	r.send(message, r.GetClients())
 }
 
 // BroadcastExcluding sends a message to all connections except the specified one
+// The message is retained in the room history (if enabled)
 func (r *Room) BroadcastExcluding(message any, exclude *Connection) {
+	r.retain(message)
+
 	r.clientsMu.RLock()
 	clients := make([]*Connection, 0, len(r.clients))
 	for conn := range r.clients {
@@ -105,7 +117,67 @@ func (r *Room) BroadcastExcluding(message any, exclude *Connection) {
 	}
 	r.clientsMu.RUnlock()
 
-	// Send to all clients (outside of lock)
+	r.send(message, clients)
+}
+
+// SetBroadcastWorkers sets how many goroutines send a broadcast to the room's clients
+// Values <= 1 send sequentially
+func (r *Room) SetBroadcastWorkers(n int) {
+	r.clientsMu.Lock()
+	defer r.clientsMu.Unlock()
+	r.broadcastWorkers = n
+}
+
+// send encodes the message once and writes it to the given clients
+// Called outside of clientsMu to avoid deadlock
+func (r *Room) send(message any, clients []*Connection) {
+	if len(clients) == 0 {
+		return
+	}
+
+	// Marshal once instead of once per connection in writeLoop
+	message, err := encodeMessage(message)
+	if err != nil {
+		r.logger.Error().Str("room", r.id).Err(err).Msg("Failed to marshal broadcast message")
+		return
+	}
+
+	r.clientsMu.RLock()
+	workers := min(r.broadcastWorkers, len(clients))
+	r.clientsMu.RUnlock()
+
+	if workers <= 1 {
+		r.write(message, clients)
+		return
+	}
+
+	// Split clients into one chunk per worker
+	var wg sync.WaitGroup
+	chunk := (len(clients) + workers - 1) / workers
+	for start := 0; start < len(clients); start += chunk {
+		end := min(start+chunk, len(clients))
+		wg.Add(1)
+		go func(clients []*Connection) {
+			defer wg.Done()
+			r.write(message, clients)
+		}(clients[start:end])
+	}
+	wg.Wait()
+}
+
+// encodeMessage marshals a message to JSON bytes, which writeLoop sends as-is
+// []byte and string messages are already encoded
+func encodeMessage(message any) (any, error) {
+	switch message.(type) {
+	case []byte, string:
+		return message, nil
+	default:
+		return json.Marshal(message)
+	}
+}
+
+// write writes an encoded message to each of the clients
+func (r *Room) write(message any, clients []*Connection) {
 	for _, conn := range clients {
 		if err := conn.WriteJSON(message); err != nil {
 			r.logger.Debug().
@@ -142,3 +214,49 @@ func (r *Room) GetMetadata(key string) (any, bool) {
 	value, ok := r.metadata[key]
 	return value, ok
 }
+
+// Metadata returns a copy of the room metadata
+func (r *Room) Metadata() map[string]any {
+	r.metadataMu.RLock()
+	defer r.metadataMu.RUnlock()
+	metadata := make(map[string]any, len(r.metadata))
+	for key, value := range r.metadata {
+		metadata[key] = value
+	}
+	return metadata
+}
+
+// SetHistoryLimit sets the maximum number of retained messages (0 disables history)
+func (r *Room) SetHistoryLimit(limit int) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+	if limit < 0 {
+		limit = 0
+	}
+	r.historyLimit = limit
+	if len(r.history) > limit {
+		r.history = append([]any(nil), r.history[len(r.history)-limit:]...)
+	}
+}
+
+// History returns a snapshot of the retained messages, oldest first
+func (r *Room) History() []any {
+	r.historyMu.RLock()
+	defer r.historyMu.RUnlock()
+	history := make([]any, len(r.history))
+	copy(history, r.history)
+	return history
+}
+
+// retain appends a message to the room history, dropping the oldest one when full
+func (r *Room) retain(message any) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+	if r.historyLimit <= 0 {
+		return
+	}
+	if len(r.history) >= r.historyLimit {
+		r.history = r.history[1:]
+	}
+	r.history = append(r.history, message)
+}
@@ -0,0 +1,106 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// newTestRoom creates a room with n socketless clients
+func newTestRoom(n int) (*Room, []*Connection) {
+	nop := zerolog.Nop()
+	room := NewRoom("bench", &nop)
+	clients := make([]*Connection, n)
+	for i := range clients {
+		clients[i] = newTestConnection()
+		room.Join(clients[i])
+	}
+	return room, clients
+}
+
+// drain empties the write buffers of the clients
+func drain(clients []*Connection) {
+	for _, conn := range clients {
+		for len(conn.writeChan) > 0 {
+			<-conn.writeChan
+		}
+	}
+}
+
+// TestRoom_BroadcastMarshalOnce tests that broadcasts deliver the same pre-encoded
+// payload to every client, with and without broadcast workers
+func TestRoom_BroadcastMarshalOnce(t *testing.T) {
+	for _, workers := range []int{0, 8} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			room, clients := newTestRoom(1000)
+			room.SetBroadcastWorkers(workers)
+
+			room.Broadcast(map[string]int{"x": 401, "y": 300})
+
+			var first []byte
+			for i, conn := range clients {
+				if n := len(conn.writeChan); n != 1 {
+					t.Fatalf("client %d received %d messages, expected 1", i, n)
+				}
+				data, ok := (<-conn.writeChan).([]byte)
+				if !ok || string(data) != `{"x":401,"y":300}` {
+					t.Fatalf("client %d received %v, expected encoded JSON", i, data)
+				}
+				if first == nil {
+					first = data
+				} else if &first[0] != &data[0] {
+					t.Fatalf("client %d received a separately marshaled payload", i)
+				}
+			}
+		})
+	}
+}
+
+// benchmarkMessage is a typical position update
+var benchmarkMessage = map[string]any{"type": "position", "x": 401, "y": 300, "ts": 1700000000000}
+
+// BenchmarkRoomBroadcast_PerConnectionMarshal measures the previous behavior:
+// the message is marshaled separately for each of 1000 clients (as writeLoop did)
+func BenchmarkRoomBroadcast_PerConnectionMarshal(b *testing.B) {
+	_, clients := newTestRoom(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, conn := range clients {
+			data, _ := json.Marshal(benchmarkMessage)
+			conn.WriteJSON(data)
+		}
+		b.StopTimer()
+		drain(clients)
+		b.StartTimer()
+	}
+}
+
+// BenchmarkRoomBroadcast_MarshalOnce measures Room.Broadcast marshaling once for 1000 clients
+func BenchmarkRoomBroadcast_MarshalOnce(b *testing.B) {
+	room, clients := newTestRoom(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		room.Broadcast(benchmarkMessage)
+		b.StopTimer()
+		drain(clients)
+		b.StartTimer()
+	}
+}
+
+// BenchmarkRoomBroadcast_Workers measures Room.Broadcast with a worker pool for 1000 clients
+func BenchmarkRoomBroadcast_Workers(b *testing.B) {
+	room, clients := newTestRoom(1000)
+	room.SetBroadcastWorkers(8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		room.Broadcast(benchmarkMessage)
+		b.StopTimer()
+		drain(clients)
+		b.StartTimer()
+	}
+}